@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+	"github.com/tmc/langchaingo/llms/openai"
+
+	"github.com/MaxBear/maxhire/analyzer/fake"
+	gcpModels "github.com/MaxBear/maxhire/deps/gcp/models"
+)
+
+// Details of a job application extracted from an email
+type Details struct {
+	// one of: accept, reject, pending, interviewing, offer, withdrawn
+	Status      string `json:"status"`
+	JobTitle    string `json:"job_title"`
+	CompanyName string `json:"company_name"`
+}
+
+// Analyzer extracts the details of job applications from emails
+type Analyzer interface {
+	// ExtractDetails extracts the details of the application from the body of one email
+	ExtractDetails(ctx context.Context, message string) (*Details, error)
+	// AnalyzeEmails sets the status, position and company of the emails, returns the errors of the
+	// emails which could not be analyzed
+	AnalyzeEmails(ctx context.Context, emails gcpModels.Emails) []error
+}
+
+// Providers of the models analyzing the emails
+const (
+	// ProviderOpenAI calls the OpenAI api, or any OpenAI compatible api with WithBaseURL, with $OPENAI_API_KEY
+	ProviderOpenAI = "openai"
+	// ProviderAnthropic calls the Anthropic api with $ANTHROPIC_API_KEY
+	ProviderAnthropic = "anthropic"
+	// ProviderOllama calls the OpenAI compatible api of a local Ollama server
+	ProviderOllama = "ollama"
+	// ProviderFake extracts the details with keyword rules, without calling any api
+	ProviderFake = "fake"
+)
+
+// Providers are the supported providers
+var Providers = []string{ProviderOpenAI, ProviderAnthropic, ProviderOllama, ProviderFake}
+
+// Defaults of the providers requiring a model or url
+const (
+	DefaultAnthropicModel = "claude-3-5-haiku-latest"
+	DefaultOllamaModel    = "llama3.1"
+	DefaultOllamaURL      = "http://localhost:11434/v1"
+)
+
+// newLLM returns the model of the provider, model and baseURL default to the ones of the provider if empty
+func newLLM(provider, model, baseURL string) (llms.Model, error) {
+	switch provider {
+	case ProviderOpenAI:
+		opts := []openai.Option{}
+		if model != "" {
+			opts = append(opts, openai.WithModel(model))
+		}
+		if baseURL != "" {
+			opts = append(opts, openai.WithBaseURL(baseURL))
+		}
+		return openai.New(opts...)
+	case ProviderAnthropic:
+		if model == "" {
+			model = DefaultAnthropicModel
+		}
+		opts := []anthropic.Option{anthropic.WithModel(model)}
+		if baseURL != "" {
+			opts = append(opts, anthropic.WithBaseURL(baseURL))
+		}
+		return anthropic.New(opts...)
+	case ProviderOllama:
+		if model == "" {
+			model = DefaultOllamaModel
+		}
+		if baseURL == "" {
+			baseURL = DefaultOllamaURL
+		}
+		// ollama ignores the token, the client requires one
+		return openai.New(openai.WithModel(model), openai.WithBaseURL(baseURL), openai.WithToken("ollama"))
+	case ProviderFake:
+		return fake.New(), nil
+	}
+	return nil, fmt.Errorf("unknown llm provider %q, expecting one of: %s", provider, strings.Join(Providers, ", "))
+}
@@ -0,0 +1,154 @@
+// Package fake is a deterministic model extracting the details of job application emails with keyword
+// rules, to analyze emails without calling an LLM, e.g. in tests.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ToolName is the tool called when the request has no tools
+const ToolName = "extract_application_details"
+
+// statusPhrases are the phrases of the statuses other than pending, the first status with a phrase
+// found in the email wins
+var statusPhrases = []struct {
+	status  string
+	phrases []string
+}{
+	{"withdrawn", []string{"application has been withdrawn", "withdrawn your application", "withdraw your application"}},
+	{"reject", []string{"unfortunately", "not to move forward", "not moving forward", "other candidates", "position is now filled", "position has been filled", "decided to pursue"}},
+	{"offer", []string{"pleased to offer", "offer letter", "extend an offer", "extend you an offer"}},
+	{"interviewing", []string{"invite you to interview", "schedule an interview", "schedule a call", "interview invitation", "next round"}},
+	{"accept", []string{"move forward with your application", "moving forward with your application"}},
+}
+
+var (
+	// the job title follows these, up to one of titleEnds or the end of the sentence, a period after a
+	// word which is not an abbreviation like Sr.
+	titleStart  = regexp.MustCompile(`(?i)\b(?:application|apply|applying) for (?:the |our )?`)
+	titleEnds   = regexp.MustCompile(` role\b| position\b| opening\b|, and | at [A-Z]|[!?\n]`)
+	sentenceEnd = regexp.MustCompile(`\w{3,}(\.)(?:\s|$)`)
+
+	// the company name follows these
+	companyPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\binterest in ([A-Z][\w&.'-]*(?: [A-Z][\w&.'-]*)*)`),
+		regexp.MustCompile(`\bjoining ([A-Z][\w&.'-]*(?: [A-Z][\w&.'-]*)*)`),
+		regexp.MustCompile(`\bat ([A-Z][\w&.'-]*(?: [A-Z][\w&.'-]*)*)`),
+	}
+)
+
+// LLM is a llms.Model answering every request with a call to the tool of the request, with the status,
+// job title and company name found in the last message by keyword rules
+type LLM struct{}
+
+var _ llms.Model = (*LLM)(nil)
+
+func New() *LLM {
+	return &LLM{}
+}
+
+// Extract returns the arguments of the tool call answering message
+func Extract(message string) map[string]string {
+	return map[string]string{
+		"status":       status(message),
+		"job_title":    jobTitle(message),
+		"company_name": companyName(message),
+	}
+}
+
+func status(message string) string {
+	lower := strings.ToLower(message)
+	for _, s := range statusPhrases {
+		for _, phrase := range s.phrases {
+			if strings.Contains(lower, phrase) {
+				return s.status
+			}
+		}
+	}
+	return "pending"
+}
+
+func jobTitle(message string) string {
+	loc := titleStart.FindStringIndex(message)
+	if loc == nil {
+		return ""
+	}
+	title := message[loc[1]:]
+	if end := titleEnds.FindStringIndex(title); end != nil {
+		title = title[:end[0]]
+	}
+	if end := sentenceEnd.FindStringSubmatchIndex(title); end != nil {
+		title = title[:end[2]]
+	}
+	return strings.TrimSpace(title)
+}
+
+func companyName(message string) string {
+	for _, pattern := range companyPatterns {
+		if match := pattern.FindStringSubmatch(message); match != nil {
+			return strings.TrimRight(match[1], ".'")
+		}
+	}
+	return ""
+}
+
+// GenerateContent answers with a call to the first tool of the request
+func (f *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, errors.New("no messages")
+	}
+
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	name := ToolName
+	if len(opts.Tools) > 0 && opts.Tools[0].Function != nil {
+		name = opts.Tools[0].Function.Name
+	}
+
+	parts := []string{}
+	for _, part := range messages[len(messages)-1].Parts {
+		if text, ok := part.(llms.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	message := strings.Join(parts, "\n")
+
+	args, err := json.Marshal(Extract(message))
+	if err != nil {
+		return nil, err
+	}
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			ToolCalls: []llms.ToolCall{{
+				ID:           "fake",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: name, Arguments: string(args)},
+			}},
+			GenerationInfo: map[string]any{
+				"PromptTokens":     len(strings.Fields(message)),
+				"CompletionTokens": len(strings.Fields(string(args))),
+			},
+		}},
+	}, nil
+}
+
+// Call returns the arguments of the tool call answering prompt
+func (f *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	resp, err := f.GenerateContent(ctx, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, prompt)}, options...)
+	if err != nil {
+		return "", err
+	}
+	return resp.Choices[0].ToolCalls[0].FunctionCall.Arguments, nil
+}
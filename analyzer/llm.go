@@ -0,0 +1,287 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/semaphore"
+
+	gcpModels "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/logging"
+	"github.com/MaxBear/maxhire/telemetry"
+)
+
+const (
+	MAX_QUERIES = 5
+	MAX_RETRIES = 2
+	MAX_TOKENS  = 1024
+	TIMEOUT     = 15 * time.Second
+)
+
+var tracer = otel.Tracer("github.com/MaxBear/maxhire/analyzer")
+
+// Ai is the Analyzer asking an LLM to extract the details of the emails
+type Ai struct {
+	provider   string
+	llm        llms.Model
+	model      string
+	baseURL    string
+	timeout    time.Duration
+	maxQueries int
+	retries    int
+	logger     *slog.Logger
+}
+
+type AiOpt func(*Ai)
+
+// WithModel sets the model analyzing the emails, defaults to the default of the provider
+func WithModel(model string) AiOpt {
+	return func(ai *Ai) {
+		ai.model = model
+	}
+}
+
+// WithBaseURL sets the url of the api of the provider, e.g. of an OpenAI compatible server
+func WithBaseURL(url string) AiOpt {
+	return func(ai *Ai) {
+		ai.baseURL = url
+	}
+}
+
+// WithTimeout sets the timeout of analyzing one email, defaults to TIMEOUT
+func WithTimeout(timeout time.Duration) AiOpt {
+	return func(ai *Ai) {
+		ai.timeout = timeout
+	}
+}
+
+// WithMaxQueries sets the number of emails analyzed at the same time, defaults to MAX_QUERIES
+func WithMaxQueries(n int) AiOpt {
+	return func(ai *Ai) {
+		ai.maxQueries = n
+	}
+}
+
+// WithRetries sets the number of times a failed call is retried, defaults to MAX_RETRIES
+func WithRetries(n int) AiOpt {
+	return func(ai *Ai) {
+		ai.retries = n
+	}
+}
+
+// WithLogger sets the logger of the analyzer, defaults to slog.Default()
+func WithLogger(logger *slog.Logger) AiOpt {
+	return func(ai *Ai) {
+		ai.logger = logger
+	}
+}
+
+// WithLLM sets the model analyzing the emails instead of the one of the provider, e.g. a fake.LLM
+func WithLLM(llm llms.Model) AiOpt {
+	return func(ai *Ai) {
+		ai.llm = llm
+	}
+}
+
+var _ Analyzer = (*Ai)(nil)
+
+// New returns the analyzer calling the model of provider, one of Providers
+func New(provider string, opts ...AiOpt) (*Ai, error) {
+	ai := &Ai{
+		provider:   provider,
+		timeout:    TIMEOUT,
+		maxQueries: MAX_QUERIES,
+		retries:    MAX_RETRIES,
+		logger:     slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(ai)
+	}
+
+	if ai.llm == nil {
+		llm, err := newLLM(provider, ai.model, ai.baseURL)
+		if err != nil {
+			return nil, err
+		}
+		ai.llm = llm
+	}
+
+	return ai, nil
+}
+
+// tokens returns the tokens used by a call, from the generation info of OpenAI or Anthropic
+func tokens(info map[string]any) (prompt, completion int) {
+	for _, key := range []string{"PromptTokens", "InputTokens"} {
+		if n, ok := info[key].(int); ok {
+			prompt = n
+		}
+	}
+	for _, key := range []string{"CompletionTokens", "OutputTokens"} {
+		if n, ok := info[key].(int); ok {
+			completion = n
+		}
+	}
+	return prompt, completion
+}
+
+// call makes one call to the model within the timeout, and records it in the metrics and traces
+func (ai *Ai) call(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	ctx, span := tracer.Start(ctx, "llm.GenerateContent", trace.WithAttributes(attribute.String("llm.provider", ai.provider)))
+	defer span.End()
+	if ai.model != "" {
+		span.SetAttributes(attribute.String("llm.model", ai.model))
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, ai.timeout)
+	defer cancelFunc()
+
+	start := time.Now()
+	resp, err := ai.llm.GenerateContent(ctx, messages, options...)
+	var promptTokens, completionTokens int
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if len(resp.Choices) > 0 {
+		promptTokens, completionTokens = tokens(resp.Choices[0].GenerationInfo)
+		span.SetAttributes(attribute.Int("llm.prompt_tokens", promptTokens), attribute.Int("llm.completion_tokens", completionTokens))
+	}
+	telemetry.ObserveLLMCall(ai.provider, start, promptTokens, completionTokens, err)
+
+	return resp, err
+}
+
+// generate calls the model, retrying a failed call up to ai.retries times with a growing delay
+func (ai *Ai) generate(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := ai.call(ctx, messages, options...)
+		if err == nil || attempt >= ai.retries || ctx.Err() != nil {
+			return resp, err
+		}
+
+		telemetry.ObserveLLMRetry(ai.provider)
+		ai.logger.WarnContext(ctx, "llm call failed, retrying", "provider", ai.provider, "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(1<<attempt) * time.Second):
+		}
+	}
+}
+
+// ExtractDetails asks the model to call the extract_application_details tool with the details of the email
+func (ai *Ai) ExtractDetails(ctx context.Context, message string) (*Details, error) {
+	tool := llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        "extract_application_details",
+			Description: "Extracts the application status, job title, and company name from a job application email message",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"status": map[string]any{
+						"type":        "string",
+						"description": "The status of the job application: 'pending' for an application confirmation, 'accept' for moving forward, 'interviewing' for an interview invitation, 'offer' for a job offer, 'reject' for a rejection, 'withdrawn' for a withdrawn application",
+						"enum":        []string{"accept", "reject", "pending", "interviewing", "offer", "withdrawn"},
+					},
+					"job_title": map[string]any{
+						"type":        "string",
+						"description": "The job title or position name mentioned in the email",
+					},
+					"company_name": map[string]any{
+						"type":        "string",
+						"description": "The name of the company being applied to or mentioned in the email",
+					},
+				},
+				"required": []string{"status", "job_title", "company_name"},
+			},
+		},
+	}
+
+	// Call the model using GenerateContent (the modern method)
+	resp, err := ai.generate(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "Analyze the email message and extract: 1) the status of the job application: confirmation ('pending'), acceptance ('accept'), interview invitation ('interviewing'), job offer ('offer'), rejection ('reject') or withdrawal ('withdrawn'), 2) the job title or position name mentioned in the email, and 3) the company name."),
+		llms.TextParts(llms.ChatMessageTypeHuman, message),
+	}, llms.WithTools([]llms.Tool{tool}), llms.WithMaxTokens(MAX_TOKENS))
+	if err != nil {
+		ai.logger.ErrorContext(ctx, "llm error when trying to guess message details", "provider", ai.provider, "error", err)
+		return nil, err
+	}
+
+	// Parse the extracted result from the tool calls, anthropic returns them after the text of the answer
+	for _, choice := range resp.Choices {
+		if len(choice.ToolCalls) == 0 || choice.ToolCalls[0].FunctionCall == nil {
+			continue
+		}
+		details := &Details{}
+		if err := json.Unmarshal([]byte(choice.ToolCalls[0].FunctionCall.Arguments), details); err != nil {
+			ai.logger.ErrorContext(ctx, "llm error when trying to parse message details", "provider", ai.provider, "error", err)
+			return nil, err
+		}
+		return details, nil
+	}
+
+	return nil, fmt.Errorf("llm unable to determine message details from email")
+}
+
+func (ai *Ai) AnalyzeEmails(ctx context.Context, emails gcpModels.Emails) []error {
+	ctx, span := tracer.Start(ctx, "analyzer.AnalyzeEmails", trace.WithAttributes(attribute.Int("emails", len(emails))))
+	defer span.End()
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	sem := semaphore.NewWeighted(int64(ai.maxQueries))
+	errs := []error{}
+
+	for i := range emails {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			ai.logger.ErrorContext(ctx, "failed to acquire semaphore", "error", err)
+			errs = append(errs, err)
+			break
+		}
+
+		wg.Add(1)
+		go func(idx int) {
+			defer func() {
+				wg.Done()
+				sem.Release(1)
+			}()
+
+			ai.logger.DebugContext(ctx, "analyzing email", "index", idx, logging.KeySender, emails[idx].EmailRecord.FullSender)
+
+			details, err := ai.ExtractDetails(ctx, emails[idx].EmailRecord.Msg)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				return
+			}
+
+			if sstatus, err := gcpModels.ParseStatus(details.Status); err == nil {
+				emails[idx].Status = sstatus
+			}
+
+			emails[idx].Position = details.JobTitle
+
+			// Use company name from status guess if available and not already set
+			if details.CompanyName != "" && emails[idx].Company == "" {
+				emails[idx].Company = details.CompanyName
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
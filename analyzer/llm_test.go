@@ -2,18 +2,33 @@ package analyzer
 
 import (
 	"context"
+	"errors"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
 
+	"github.com/MaxBear/maxhire/analyzer/fake"
 	gcpModels "github.com/MaxBear/maxhire/deps/gcp/models"
 )
 
-func setup(t *testing.T) (*Ai, context.Context) {
-	godotenv.Load("../../configs/.env")
-	ai, err := New()
+// providers are the providers the tests run against: the fake one, and the ones listed in
+// $MAXHIRE_TEST_LLM_PROVIDERS, e.g. openai,anthropic, which read their api keys from configs/.env
+func providers() []string {
+	providers := []string{ProviderFake}
+	if env := os.Getenv("MAXHIRE_TEST_LLM_PROVIDERS"); env != "" {
+		providers = append(providers, strings.Split(env, ",")...)
+	}
+	return providers
+}
+
+func setup(t *testing.T, provider string, opts ...AiOpt) (*Ai, context.Context) {
+	godotenv.Load("../configs/.env")
+	ai, err := New(provider, opts...)
 	require.Nil(t, err)
 
 	ctx := context.Background()
@@ -80,13 +95,57 @@ func TestAnalyzeEmails(t *testing.T) {
 		},
 	}
 
-	ai, ctx := setup(t)
+	for _, provider := range providers() {
+		t.Run(provider, func(t *testing.T) {
+			ai, ctx := setup(t, provider)
+
+			in := make(gcpModels.Emails, len(tc.in))
+			for i, email := range tc.in {
+				copied := *email
+				in[i] = &copied
+			}
+
+			err := ai.AnalyzeEmails(ctx, in)
+			assert.Equal(t, 0, len(err))
+			for i, out := range in {
+				assert.Equal(t, tc.res[i].Company, out.Company)
+				assert.Equal(t, tc.res[i].Position, out.Position)
+				assert.Equal(t, tc.res[i].Status, out.Status)
+			}
+		})
+	}
+}
+
+// flakyLLM fails the first calls
+type flakyLLM struct {
+	*fake.LLM
+	failures int
+	calls    int
+}
 
-	err := ai.AnalyzeEmails(ctx, tc.in)
-	assert.Equal(t, 0, len(err))
-	for i, out := range tc.in {
-		assert.Equal(t, tc.res[i].Company, out.Company)
-		assert.Equal(t, tc.res[i].Position, out.Position)
-		assert.Equal(t, tc.res[i].Status, out.Status)
+func (f *flakyLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errors.New("unavailable")
 	}
+	return f.LLM.GenerateContent(ctx, messages, options...)
+}
+
+func TestExtractDetails_Retries(t *testing.T) {
+	llm := &flakyLLM{LLM: fake.New(), failures: 1}
+	ai, ctx := setup(t, ProviderOpenAI, WithLLM(llm), WithRetries(1))
+
+	details, err := ai.ExtractDetails(ctx, "Unfortunately, we decided not to move forward with your application for the Data Engineer role at Acme.")
+	require.NoError(t, err)
+	assert.Equal(t, &Details{Status: "reject", JobTitle: "Data Engineer", CompanyName: "Acme"}, details)
+	assert.Equal(t, 2, llm.calls)
+
+	llm = &flakyLLM{LLM: fake.New(), failures: 2}
+	ai, ctx = setup(t, ProviderOpenAI, WithLLM(llm), WithRetries(1))
+	_, err = ai.ExtractDetails(ctx, "Thanks for applying")
+	assert.Error(t, err)
+	assert.Equal(t, 2, llm.calls)
+
+	_, err = New("other")
+	assert.Error(t, err)
 }
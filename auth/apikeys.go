@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// APIKeys authenticates static api keys
+type APIKeys struct {
+	// tenants by the sha256 of their keys, so lookups don't depend on how much of a key matches
+	tenants map[[32]byte]string
+}
+
+// NewAPIKeys returns the authenticator of the keys, which maps keys to tenants
+func NewAPIKeys(keys map[string]string) *APIKeys {
+	a := &APIKeys{
+		tenants: make(map[[32]byte]string, len(keys)),
+	}
+	for key, tenant := range keys {
+		a.tenants[sha256.Sum256([]byte(key))] = tenant
+	}
+	return a
+}
+
+// LoadAPIKeys reads the api keys from a file with one "tenant:key" per line,
+// empty lines and lines starting with # are ignored
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keys := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tenant, key, ok := strings.Cut(line, ":")
+		tenant, key = strings.TrimSpace(tenant), strings.TrimSpace(key)
+		if !ok || tenant == "" || key == "" {
+			return nil, fmt.Errorf("invalid api key on line %d of %s, expecting tenant:key", n, path)
+		}
+		if _, ok := keys[key]; ok {
+			return nil, fmt.Errorf("duplicate api key on line %d of %s", n, path)
+		}
+		keys[key] = tenant
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewAPIKeys(keys), nil
+}
+
+func (a *APIKeys) Authenticate(ctx context.Context, token string) (string, error) {
+	tenant, ok := a.tenants[sha256.Sum256([]byte(token))]
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	return tenant, nil
+}
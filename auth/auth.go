@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/MaxBear/maxhire/service"
+)
+
+// ErrInvalidToken is returned by authenticators for a token they don't accept
+var ErrInvalidToken = errors.New("invalid token")
+
+// Authenticator returns the tenant a bearer token was issued to
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+type chain []Authenticator
+
+// Chain returns an authenticator accepting the tokens accepted by any of the authenticators
+func Chain(authenticators ...Authenticator) Authenticator {
+	return chain(authenticators)
+}
+
+func (c chain) Authenticate(ctx context.Context, token string) (string, error) {
+	err := ErrInvalidToken
+	for _, a := range c {
+		var tenant string
+		tenant, err = a.Authenticate(ctx, token)
+		if err == nil {
+			return tenant, nil
+		}
+	}
+	return "", err
+}
+
+// authenticate verifies the bearer token of the grpc request and returns the context of its tenant
+func authenticate(ctx context.Context, a Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	tenant, err := a.Authenticate(ctx, strings.TrimSpace(token))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return service.WithTenant(ctx, tenant), nil
+}
+
+// exempted reports whether the method belongs to one of the services
+func exempted(fullMethod string, services []string) bool {
+	for _, service := range services {
+		if strings.HasPrefix(fullMethod, "/"+service+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// UnaryServerInterceptor rejects the calls without a valid bearer token, and scopes the others to
+// the tenant of the token. The calls of the exempt services, e.g. grpc.health.v1.Health, are let
+// through without a tenant.
+func UnaryServerInterceptor(a Authenticator, exempt ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if exempted(info.FullMethod, exempt) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls
+func StreamServerInterceptor(a Authenticator, exempt ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if exempted(info.FullMethod, exempt) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), a)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
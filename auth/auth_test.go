@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/MaxBear/maxhire/service"
+)
+
+func TestLoadAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(path, []byte("# api keys\njane:secret1\n\njohn: secret2\n"), 0600))
+
+	keys, err := LoadAPIKeys(path)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	tenant, err := keys.Authenticate(ctx, "secret1")
+	require.NoError(t, err)
+	assert.Equal(t, "jane", tenant)
+	tenant, err = keys.Authenticate(ctx, "secret2")
+	require.NoError(t, err)
+	assert.Equal(t, "john", tenant)
+	_, err = keys.Authenticate(ctx, "secret")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	for _, content := range []string{"secret\n", "jane:\n", "jane:secret\njohn:secret\n"} {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		_, err = LoadAPIKeys(path)
+		assert.Error(t, err, content)
+	}
+}
+
+func writeJWKS(t *testing.T, path string, kid string, key *rsa.PublicKey) {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0600))
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestJWTVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, "key1", &key.PublicKey)
+
+	v, err := NewJWTVerifier(path, WithIssuer("https://issuer"), WithAudience("maxhire"), WithTenantClaim("email"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	exp := time.Now().Add(time.Hour).Unix()
+	tenant, err := v.Authenticate(ctx, sign(t, key, "key1", jwt.MapClaims{
+		"iss": "https://issuer", "aud": "maxhire", "exp": exp, "email": "jane@example.com",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", tenant)
+
+	for name, claims := range map[string]jwt.MapClaims{
+		"expired":      {"iss": "https://issuer", "aud": "maxhire", "exp": time.Now().Add(-time.Hour).Unix(), "email": "jane@example.com"},
+		"no expiry":    {"iss": "https://issuer", "aud": "maxhire", "email": "jane@example.com"},
+		"issuer":       {"iss": "https://other", "aud": "maxhire", "exp": exp, "email": "jane@example.com"},
+		"audience":     {"iss": "https://issuer", "aud": "other", "exp": exp, "email": "jane@example.com"},
+		"tenant claim": {"iss": "https://issuer", "aud": "maxhire", "exp": exp, "sub": "jane"},
+	} {
+		_, err = v.Authenticate(ctx, sign(t, key, "key1", claims))
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+
+	// tokens signed by a key missing from the JWKS are rejected until the file is updated
+	rotated, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	token := sign(t, rotated, "key2", jwt.MapClaims{"iss": "https://issuer", "aud": "maxhire", "exp": exp, "email": "jane@example.com"})
+	_, err = v.Authenticate(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	writeJWKS(t, path, "key2", &rotated.PublicKey)
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	tenant, err = v.Authenticate(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", tenant)
+
+	// an invalid file keeps the previous keys
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute)))
+	_, err = v.Authenticate(ctx, token)
+	require.NoError(t, err)
+
+	_, err = NewJWTVerifier(path)
+	assert.Error(t, err)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(Chain(
+		NewAPIKeys(map[string]string{"secret1": "jane"}),
+		NewAPIKeys(map[string]string{"secret2": "john"}),
+	), "grpc.health.v1.Health")
+	handler := func(ctx context.Context, req any) (any, error) {
+		return service.Tenant(ctx), nil
+	}
+
+	for authorization, tenant := range map[string]string{"Bearer secret1": "jane", "bearer secret2": "john"} {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+		res, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		require.NoError(t, err)
+		assert.Equal(t, tenant, res)
+	}
+
+	for _, md := range []metadata.MD{
+		nil,
+		metadata.Pairs("authorization", "secret1"),
+		metadata.Pairs("authorization", "Basic secret1"),
+		metadata.Pairs("authorization", "Bearer secret3"),
+	} {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), md)
+	}
+
+	res, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "", res)
+}
@@ -0,0 +1,243 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultTenantClaim is the claim holding the tenant of a JWT
+const DefaultTenantClaim = "sub"
+
+// jwk is a JSON Web Key, only the public key fields are read
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k *jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{
+			"P-256": elliptic.P256(),
+			"P-384": elliptic.P384(),
+			"P-521": elliptic.P521(),
+		}
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size %d", len(x))
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+// loadJWKS reads the signing keys of a JWKS file by key id
+func loadJWKS(path string) (map[string]any, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &jwks); err != nil {
+		return nil, fmt.Errorf("invalid JWKS file %s, error: %w", path, err)
+	}
+
+	keys := make(map[string]any)
+	for i, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %d in JWKS file %s, error: %w", i, path, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys in JWKS file %s", path)
+	}
+	return keys, nil
+}
+
+// JWTVerifier authenticates JWTs signed by the keys of a JWKS file. The file is read again when it
+// changes, so keys can be rotated without a restart.
+type JWTVerifier struct {
+	path        string
+	issuer      string
+	audience    string
+	tenantClaim string
+
+	mu      sync.Mutex
+	modTime time.Time
+	keys    map[string]any
+}
+
+type JWTVerifierOpt func(*JWTVerifier)
+
+// WithIssuer only accepts the tokens issued by issuer
+func WithIssuer(issuer string) JWTVerifierOpt {
+	return func(v *JWTVerifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience only accepts the tokens issued for audience
+func WithAudience(audience string) JWTVerifierOpt {
+	return func(v *JWTVerifier) {
+		v.audience = audience
+	}
+}
+
+// WithTenantClaim sets the claim holding the tenant, defaults to DefaultTenantClaim
+func WithTenantClaim(claim string) JWTVerifierOpt {
+	return func(v *JWTVerifier) {
+		v.tenantClaim = claim
+	}
+}
+
+func NewJWTVerifier(jwksFile string, opts ...JWTVerifierOpt) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		path:        jwksFile,
+		tenantClaim: DefaultTenantClaim,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if _, err := v.currentKeys(); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// currentKeys returns the keys of the JWKS file, reading it again if it changed.
+// The previous keys are kept if the changed file can't be read.
+func (v *JWTVerifier) currentKeys() (map[string]any, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	info, err := os.Stat(v.path)
+	if err != nil {
+		if v.keys != nil {
+			log.Printf("error reading JWKS file %s, keeping the previous keys, error: %s", v.path, err.Error())
+			return v.keys, nil
+		}
+		return nil, err
+	}
+	if v.keys != nil && info.ModTime().Equal(v.modTime) {
+		return v.keys, nil
+	}
+
+	keys, err := loadJWKS(v.path)
+	if err != nil {
+		if v.keys != nil {
+			log.Printf("error reading JWKS file %s, keeping the previous keys, error: %s", v.path, err.Error())
+			return v.keys, nil
+		}
+		return nil, err
+	}
+	v.keys = keys
+	v.modTime = info.ModTime()
+	return keys, nil
+}
+
+func (v *JWTVerifier) Authenticate(ctx context.Context, token string) (string, error) {
+	keys, err := v.currentKeys()
+	if err != nil {
+		return "", err
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		opts = append(opts, jwt.WithAudience(v.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.NewParser(opts...).ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidToken, err.Error())
+	}
+
+	tenant, _ := claims[v.tenantClaim].(string)
+	if tenant == "" {
+		return "", fmt.Errorf("%w: missing %s claim", ErrInvalidToken, v.tenantClaim)
+	}
+	return tenant, nil
+}
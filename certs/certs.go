@@ -0,0 +1,121 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Files is the certificate of a server with the CA verifying its client certificates. The files are read
+// again when they change, so certificates can be renewed without a restart.
+type Files struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu        sync.Mutex
+	modTimes  []time.Time
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+// Load reads the certificate and key files, and the CA file of the client certificates. Clients don't need
+// a certificate if clientCAFile is empty.
+func Load(certFile, keyFile, clientCAFile string) (*Files, error) {
+	f := &Files{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+	if err := f.reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *Files) paths() []string {
+	paths := []string{f.certFile, f.keyFile}
+	if f.clientCAFile != "" {
+		paths = append(paths, f.clientCAFile)
+	}
+	return paths
+}
+
+// reload reads the files again if any of them changed since they were last read. The caller holds mu.
+func (f *Files) reload() error {
+	paths := f.paths()
+	modTimes := make([]time.Time, len(paths))
+	changed := f.cert == nil
+	for i, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTimes[i] = info.ModTime()
+		changed = changed || !modTimes[i].Equal(f.modTimes[i])
+	}
+	if !changed {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+	if err != nil {
+		return err
+	}
+
+	var clientCAs *x509.CertPool
+	if f.clientCAFile != "" {
+		pem, err := os.ReadFile(f.clientCAFile)
+		if err != nil {
+			return err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates in client CA file %s", f.clientCAFile)
+		}
+	}
+
+	f.cert, f.clientCAs, f.modTimes = &cert, clientCAs, modTimes
+	return nil
+}
+
+// current returns the certificate and client CAs, reading them again if the files changed.
+// The previous ones are kept while the changed files can't be read, e.g. the certificate was
+// replaced but not the key yet.
+func (f *Files) current() (*tls.Certificate, *x509.CertPool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.reload(); err != nil {
+		log.Printf("error reading certificate %s, keeping the previous one, error: %s", f.certFile, err.Error())
+	}
+	return f.cert, f.clientCAs
+}
+
+func (f *Files) config(nextProtos []string) *tls.Config {
+	cert, clientCAs := f.current()
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   nextProtos,
+		Certificates: []tls.Certificate{*cert},
+	}
+	if clientCAs != nil {
+		config.ClientCAs = clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config
+}
+
+// ServerConfig returns the tls config of a server negotiating the application protocols, using the
+// current files on every connection
+func (f *Files) ServerConfig(nextProtos ...string) *tls.Config {
+	config := f.config(nextProtos)
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return f.config(nextProtos), nil
+	}
+	return config
+}
@@ -0,0 +1,175 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+// issue returns a certificate with the serial number, self signed if parent is nil
+func issue(t *testing.T, serial int64, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) write(t *testing.T, certFile, keyFile string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600))
+	if keyFile == "" {
+		require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+		return
+	}
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+// serve accepts tls connections until the listener is closed
+func serve(t *testing.T, config *tls.Config) string {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if err := conn.(*tls.Conn).Handshake(); err != nil {
+					return
+				}
+				conn.Write([]byte("ok"))
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// dial returns the serial number of the server certificate
+func dial(addr string, config *tls.Config) (int64, error) {
+	conn, err := tls.Dial("tcp", addr, config)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	// client certificates are verified after the client handshake completes with tls 1.3
+	if _, err := conn.Read(make([]byte, 2)); err != nil {
+		return 0, err
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestServerConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key")
+	ca := issue(t, 1, nil)
+	issue(t, 2, ca).write(t, certFile, keyFile, time.Now())
+
+	files, err := Load(certFile, keyFile, "")
+	require.NoError(t, err)
+	addr := serve(t, files.ServerConfig())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	serial, err := dial(addr, &tls.Config{RootCAs: roots})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), serial)
+
+	// the renewed certificate is served to new connections
+	issue(t, 3, ca).write(t, certFile, keyFile, time.Now().Add(time.Minute))
+	serial, err = dial(addr, &tls.Config{RootCAs: roots})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), serial)
+
+	// a certificate without its key keeps the previous one
+	issue(t, 4, ca).write(t, certFile, "", time.Now().Add(2*time.Minute))
+	serial, err = dial(addr, &tls.Config{RootCAs: roots})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), serial)
+
+	_, err = Load(certFile, filepath.Join(dir, "missing.key"), "")
+	assert.Error(t, err)
+}
+
+func TestServerConfig_ClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.pem")
+	ca := issue(t, 1, nil)
+	issue(t, 2, ca).write(t, certFile, keyFile, time.Now())
+	ca.write(t, caFile, "", time.Now())
+
+	files, err := Load(certFile, keyFile, caFile)
+	require.NoError(t, err)
+	addr := serve(t, files.ServerConfig())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := issue(t, 10, ca)
+	_, err = dial(addr, &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{client.tlsCertificate()}})
+	require.NoError(t, err)
+
+	_, err = dial(addr, &tls.Config{RootCAs: roots})
+	assert.Error(t, err)
+
+	// clients of a replaced CA are rejected
+	other := issue(t, 20, nil)
+	other.write(t, caFile, "", time.Now().Add(time.Minute))
+	_, err = dial(addr, &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{client.tlsCertificate()}})
+	assert.Error(t, err)
+	_, err = dial(addr, &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{issue(t, 21, other).tlsCertificate()}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,270 @@
+package classifier
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MaxBear/maxhire/analyzer"
+	gcpModels "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/logging"
+	"github.com/MaxBear/maxhire/telemetry"
+)
+
+// DefaultMinConfidence is the confidence of the rules below which emails are analyzed by the fallback,
+// it takes the status and the company or the position
+const DefaultMinConfidence = 0.7
+
+// weights of the fields extracted by the rules in the confidence, in percent
+const (
+	statusWeight    = 40
+	ambiguousWeight = 20
+	companyWeight   = 30
+	positionWeight  = 30
+)
+
+var tracer = otel.Tracer("github.com/MaxBear/maxhire/classifier")
+
+// Result of the classification of an email
+type Result struct {
+	// ATS is the name of the ATS which sent the email, empty if none was recognized
+	ATS string
+	analyzer.Details
+	// Confidence from 0 to 1, the sum of 0.4 for the status, or 0.2 if keywords of several statuses
+	// other than pending are found, 0.3 for the company and 0.3 for the position
+	Confidence float64
+}
+
+// Classifier is the Analyzer extracting the details of the emails with rules, and with the fallback
+// Analyzer, e.g. an LLM, when the confidence of the rules is low
+type Classifier struct {
+	rules         *Rules
+	fallback      analyzer.Analyzer
+	minConfidence float64
+	logger        *slog.Logger
+}
+
+type ClassifierOpt func(*Classifier)
+
+// WithFallback sets the Analyzer of the emails the rules classify with a low confidence, their results are
+// used whatever their confidence without one
+func WithFallback(fallback analyzer.Analyzer) ClassifierOpt {
+	return func(c *Classifier) {
+		c.fallback = fallback
+	}
+}
+
+// WithMinConfidence sets the confidence below which emails are analyzed by the fallback, defaults to
+// DefaultMinConfidence
+func WithMinConfidence(confidence float64) ClassifierOpt {
+	return func(c *Classifier) {
+		c.minConfidence = confidence
+	}
+}
+
+// WithLogger sets the logger of the classifier, defaults to slog.Default()
+func WithLogger(logger *slog.Logger) ClassifierOpt {
+	return func(c *Classifier) {
+		c.logger = logger
+	}
+}
+
+var _ analyzer.Analyzer = (*Classifier)(nil)
+
+func New(rules *Rules, opts ...ClassifierOpt) *Classifier {
+	c := &Classifier{
+		rules:         rules,
+		minConfidence: DefaultMinConfidence,
+		logger:        slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// firstGroup returns the first group of the first match of exprs in message accepted by valid
+func firstGroup(exprs []*regexp.Regexp, message string, valid func(string) bool) string {
+	for _, re := range exprs {
+		for _, match := range re.FindAllStringSubmatch(message, -1) {
+			value := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(match[1]), ",.;:"))
+			if value != "" && valid(value) {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+type keywordMatch struct {
+	status     string
+	start, end int
+}
+
+// status returns the status of the keywords found in message, and whether keywords of other statuses
+// than pending were found too
+func status(atss []*ATS, message string) (string, bool) {
+	lower := strings.ToLower(message)
+	matches := []keywordMatch{}
+	for _, ats := range atss {
+		for status, keywords := range ats.Status {
+			for _, keyword := range keywords {
+				keyword = strings.ToLower(keyword)
+				if keyword == "" {
+					// would match everywhere, rejected by ParseRules
+					continue
+				}
+				for offset := 0; ; {
+					i := strings.Index(lower[offset:], keyword)
+					if i < 0 {
+						break
+					}
+					start := offset + i
+					matches = append(matches, keywordMatch{status: status, start: start, end: start + len(keyword)})
+					offset = start + len(keyword)
+				}
+			}
+		}
+	}
+
+	// of overlapping keywords, e.g. "not to move forward" and "move forward with your application", the
+	// first and longest one is kept
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].end > matches[j].end
+	})
+	found := map[string]bool{}
+	end := 0
+	for _, m := range matches {
+		if m.start >= end {
+			found[m.status] = true
+			end = m.end
+		}
+	}
+
+	result := ""
+	others := 0
+	for _, s := range Statuses {
+		if !found[s] {
+			continue
+		}
+		if result == "" {
+			result = s
+		}
+		if s != "pending" {
+			others++
+		}
+	}
+	return result, others > 1
+}
+
+// Classify extracts the details of an email from its sender and body with the rules
+func (c *Classifier) Classify(sender, message string) *Result {
+	res := &Result{}
+
+	// the rules of the ATS of the email come first, then the generic ones
+	atss := []*ATS{}
+	for _, ats := range c.rules.ATS {
+		if !ats.generic() && ats.match(sender, message) {
+			res.ATS = ats.Name
+			atss = append(atss, ats)
+			break
+		}
+	}
+	for _, ats := range c.rules.ATS {
+		if ats.generic() {
+			atss = append(atss, ats)
+		}
+	}
+
+	score := 0
+	for _, ats := range atss {
+		if res.CompanyName == "" {
+			res.CompanyName = firstGroup(ats.company, message, func(company string) bool {
+				return !gcpModels.Company(company).Invalid()
+			})
+		}
+		if res.JobTitle == "" {
+			res.JobTitle = firstGroup(ats.position, message, func(string) bool { return true })
+		}
+	}
+	if res.CompanyName != "" {
+		score += companyWeight
+	}
+	if res.JobTitle != "" {
+		score += positionWeight
+	}
+
+	var ambiguous bool
+	res.Status, ambiguous = status(atss, message)
+	switch {
+	case res.Status == "":
+	case ambiguous:
+		score += ambiguousWeight
+	default:
+		score += statusWeight
+	}
+
+	res.Confidence = float64(score) / 100
+	return res
+}
+
+// ExtractDetails extracts the details of the application from the body of one email with the rules, or
+// with the fallback if their confidence is low
+func (c *Classifier) ExtractDetails(ctx context.Context, message string) (*analyzer.Details, error) {
+	res := c.Classify("", message)
+	if res.Confidence >= c.minConfidence || c.fallback == nil {
+		telemetry.ObserveClassification(res.ATS, false)
+		return &res.Details, nil
+	}
+	telemetry.ObserveClassification(res.ATS, true)
+	return c.fallback.ExtractDetails(ctx, message)
+}
+
+// AnalyzeEmails sets the status, position and company of the emails classified with a high confidence by
+// the rules, and has the fallback analyze the others
+func (c *Classifier) AnalyzeEmails(ctx context.Context, emails gcpModels.Emails) []error {
+	ctx, span := tracer.Start(ctx, "classifier.AnalyzeEmails", trace.WithAttributes(attribute.Int("emails", len(emails))))
+	defer span.End()
+
+	low := gcpModels.Emails{}
+	for i, email := range emails {
+		res := c.Classify(email.EmailRecord.FullSender, email.EmailRecord.Msg)
+		c.logger.DebugContext(ctx, "classified email", "index", i, logging.KeySender, email.EmailRecord.FullSender,
+			"ats", res.ATS, "status", res.Status, "confidence", res.Confidence)
+
+		if res.Confidence < c.minConfidence && c.fallback != nil {
+			telemetry.ObserveClassification(res.ATS, true)
+			low = append(low, email)
+			continue
+		}
+		telemetry.ObserveClassification(res.ATS, false)
+
+		if status, err := gcpModels.ParseStatus(res.Status); err == nil {
+			email.Status = status
+		}
+		if res.JobTitle != "" {
+			email.Position = res.JobTitle
+		}
+		if res.CompanyName != "" && email.Company == "" {
+			email.Company = res.CompanyName
+		}
+	}
+	span.SetAttributes(attribute.Int("fallback", len(low)))
+	c.logger.InfoContext(ctx, "classified emails with the rules", "emails", len(emails), "fallback", len(low))
+
+	if len(low) == 0 {
+		return nil
+	}
+	return c.fallback.AnalyzeEmails(ctx, low)
+}
@@ -0,0 +1,150 @@
+package classifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MaxBear/maxhire/analyzer"
+	gcpModels "github.com/MaxBear/maxhire/deps/gcp/models"
+)
+
+func TestClassify(t *testing.T) {
+	rules, err := LoadRules("")
+	require.NoError(t, err)
+	c := New(rules)
+
+	for name, tc := range map[string]struct {
+		sender, msg string
+		res         Result
+	}{
+		"greenhouse": {
+			sender: "no-reply@us.greenhouse-mail.io",
+			msg:    "Hello xx,  Thank you for your interest in Lyft! We wanted to let you know we received your application for Software Engineer – Developer Workflows & Infrastructure Automation, and we are delighted that you would consider joining our team.",
+			res:    Result{ATS: "greenhouse", Details: analyzer.Details{Status: "pending", JobTitle: "Software Engineer – Developer Workflows & Infrastructure Automation", CompanyName: "Lyft"}, Confidence: 1},
+		},
+		"lever": {
+			sender: "no-reply@hire.lever.co",
+			msg:    "Hi xx, Thank you for your interest in Plaid. Unfortunately, after reviewing your application for the Backend Engineer role, we have decided not to move forward at this time.",
+			res:    Result{ATS: "lever", Details: analyzer.Details{Status: "reject", JobTitle: "Backend Engineer", CompanyName: "Plaid"}, Confidence: 1},
+		},
+		"ashby": {
+			sender: "Zapier Hiring Team <no-reply@ashbyhq.com>",
+			msg:    "Hi xx, This is our friendly Zapbot confirming we received your application for the Sr. Software Engineer (L4) role at Zapier.  Thanks for applying!",
+			res:    Result{ATS: "ashby", Details: analyzer.Details{Status: "pending", JobTitle: "Sr. Software Engineer (L4)", CompanyName: "Zapier"}, Confidence: 1},
+		},
+		"workday": {
+			sender: "acme@myworkday.com",
+			msg:    "Dear xx, Thank you for applying to the Site Reliability Engineer (R-12345) position at Acme. After careful review, we have decided to move forward with other candidates.",
+			res:    Result{ATS: "workday", Details: analyzer.Details{Status: "reject", JobTitle: "Site Reliability Engineer", CompanyName: "Acme"}, Confidence: 1},
+		},
+		"smartrecruiters": {
+			sender: "jobs@smartrecruiters.com",
+			msg:    "Hi xx, Thank you for your application for the Platform Engineer position at Visa. We would like to invite you to interview with the team.",
+			res:    Result{ATS: "smartrecruiters", Details: analyzer.Details{Status: "interviewing", JobTitle: "Platform Engineer", CompanyName: "Visa"}, Confidence: 1},
+		},
+		"marker": {
+			msg: "We are pleased to offer you the Data Engineer position at Initech. See https://jobs.lever.co/initech for details.",
+			res: Result{ATS: "lever", Details: analyzer.Details{Status: "offer", CompanyName: "Initech"}, Confidence: 0.7},
+		},
+		"ambiguous": {
+			sender: "careers@example.com",
+			msg:    "Unfortunately the interview slot is gone, please schedule an interview again.",
+			res:    Result{Details: analyzer.Details{Status: "reject"}, Confidence: 0.2},
+		},
+		"unknown": {
+			sender: "friend@example.com",
+			msg:    "Are we still on for lunch?",
+			res:    Result{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.res, c.Classify(tc.sender, tc.msg))
+		})
+	}
+}
+
+// countingAnalyzer counts the emails it analyzes
+type countingAnalyzer struct {
+	emails int
+}
+
+func (a *countingAnalyzer) ExtractDetails(ctx context.Context, message string) (*analyzer.Details, error) {
+	a.emails++
+	return &analyzer.Details{Status: "accept", JobTitle: "Analyst", CompanyName: "Fallback"}, nil
+}
+
+func (a *countingAnalyzer) AnalyzeEmails(ctx context.Context, emails gcpModels.Emails) []error {
+	for _, email := range emails {
+		a.emails++
+		email.Status = gcpModels.Success
+		email.Company = "Fallback"
+	}
+	return nil
+}
+
+func TestAnalyzeEmails(t *testing.T) {
+	rules, err := LoadRules("")
+	require.NoError(t, err)
+
+	emails := func() gcpModels.Emails {
+		return gcpModels.Emails{
+			{EmailRecord: &gcpModels.RawEmailRecord{
+				FullSender: "no-reply@hire.lever.co",
+				Msg:        "Thank you for your interest in Plaid. Unfortunately, after reviewing your application for the Backend Engineer role, we have decided not to move forward.",
+			}},
+			{EmailRecord: &gcpModels.RawEmailRecord{
+				FullSender: "someone@example.com",
+				Msg:        "Unfortunately we went another way.",
+			}},
+		}
+	}
+
+	fallback := &countingAnalyzer{}
+	in := emails()
+	assert.Empty(t, New(rules, WithFallback(fallback)).AnalyzeEmails(context.Background(), in))
+	assert.Equal(t, 1, fallback.emails)
+	assert.Equal(t, "Plaid", in[0].Company)
+	assert.Equal(t, "Backend Engineer", in[0].Position)
+	assert.Equal(t, gcpModels.Reject, in[0].Status)
+	assert.Equal(t, "Fallback", in[1].Company)
+	assert.Equal(t, gcpModels.Success, in[1].Status)
+
+	// without fallback, the partial results are applied
+	in = emails()
+	assert.Empty(t, New(rules).AnalyzeEmails(context.Background(), in))
+	assert.Equal(t, "", in[1].Company)
+	assert.Equal(t, gcpModels.Reject, in[1].Status)
+
+	details, err := New(rules, WithFallback(fallback), WithMinConfidence(0.5)).ExtractDetails(context.Background(), "Unfortunately we went another way.")
+	require.NoError(t, err)
+	assert.Equal(t, "Fallback", details.CompanyName)
+	assert.Equal(t, 2, fallback.emails)
+}
+
+func TestParseRules(t *testing.T) {
+	for name, rules := range map[string]string{
+		"no name":        "ats:\n  - senders: [a.io]\n",
+		"invalid regexp": "ats:\n  - name: a\n    company: ['(']\n",
+		"no group":       "ats:\n  - name: a\n    position: ['role']\n",
+		"unknown status": "ats:\n  - name: a\n    status:\n      hired: [welcome]\n",
+		"empty keyword":  "ats:\n  - name: a\n    status:\n      reject: ['']\n",
+		"blank keyword":  "ats:\n  - name: a\n    status:\n      reject: [welcome, ' ']\n",
+		"invalid yaml":   "ats: {",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseRules([]byte(rules))
+			assert.Error(t, err)
+		})
+	}
+
+	rules, err := ParseRules([]byte("ats:\n  - name: a\n    senders: [a.io]\n    company: ['at (\\w+)']\n"))
+	require.NoError(t, err)
+	assert.Equal(t, &Result{ATS: "a", Details: analyzer.Details{CompanyName: "Acme"}, Confidence: 0.3}, New(rules).Classify("x@a.io", "welcome at Acme"))
+
+	// empty keywords of rules not parsed are ignored
+	found, _ := status([]*ATS{{Name: "a", Status: map[string][]string{"reject": {""}}}}, "welcome at Acme")
+	assert.Empty(t, found)
+}
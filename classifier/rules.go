@@ -0,0 +1,124 @@
+package classifier
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed rules.yaml
+var defaultRules []byte
+
+// Statuses are the statuses of the rules, by priority: the first one found in an email wins
+var Statuses = []string{"withdrawn", "reject", "offer", "interviewing", "accept", "pending"}
+
+// Rules classify the emails of the applicant tracking systems
+type Rules struct {
+	ATS []*ATS `yaml:"ats"`
+}
+
+// ATS are the rules of the emails of an applicant tracking system. The rules of an ATS without senders
+// and markers apply to every email.
+type ATS struct {
+	Name string `yaml:"name"`
+	// Senders are substrings of the sender addresses of the ATS, e.g. greenhouse-mail.io
+	Senders []string `yaml:"senders"`
+	// Markers are substrings of the bodies of the emails of the ATS, e.g. the domain of its job boards
+	Markers []string `yaml:"markers"`
+	// Company and Position are regular expressions whose first group is the company or the position
+	Company  []string `yaml:"company"`
+	Position []string `yaml:"position"`
+	// Status are the case insensitive keywords of the statuses, by status, one of Statuses
+	Status map[string][]string `yaml:"status"`
+
+	company  []*regexp.Regexp
+	position []*regexp.Regexp
+}
+
+// generic tells whether the rules apply to every email
+func (a *ATS) generic() bool {
+	return len(a.Senders) == 0 && len(a.Markers) == 0
+}
+
+// match tells whether the email was sent by the ATS
+func (a *ATS) match(sender, message string) bool {
+	sender = strings.ToLower(sender)
+	for _, s := range a.Senders {
+		if strings.Contains(sender, strings.ToLower(s)) {
+			return true
+		}
+	}
+	for _, m := range a.Markers {
+		if strings.Contains(message, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func compile(name string, exprs []string) ([]*regexp.Regexp, error) {
+	res := []*regexp.Regexp{}
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s rule %q: %w", name, expr, err)
+		}
+		if re.NumSubexp() < 1 {
+			return nil, fmt.Errorf("invalid %s rule %q: expecting a group", name, expr)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// ParseRules parses YAML rules, see rules.yaml
+func ParseRules(b []byte) (*Rules, error) {
+	rules := &Rules{}
+	if err := yaml.Unmarshal(b, rules); err != nil {
+		return nil, err
+	}
+
+	errs := []error{}
+	for i, ats := range rules.ATS {
+		if ats.Name == "" {
+			errs = append(errs, fmt.Errorf("ats %d: missing name", i))
+		}
+		var err error
+		if ats.company, err = compile("company", ats.Company); err != nil {
+			errs = append(errs, fmt.Errorf("ats %s: %w", ats.Name, err))
+		}
+		if ats.position, err = compile("position", ats.Position); err != nil {
+			errs = append(errs, fmt.Errorf("ats %s: %w", ats.Name, err))
+		}
+		for status, keywords := range ats.Status {
+			if !slices.Contains(Statuses, status) {
+				errs = append(errs, fmt.Errorf("ats %s: invalid status %q, expecting one of: %s", ats.Name, status, strings.Join(Statuses, ", ")))
+			}
+			if slices.ContainsFunc(keywords, func(keyword string) bool { return strings.TrimSpace(keyword) == "" }) {
+				errs = append(errs, fmt.Errorf("ats %s: empty %s keyword", ats.Name, status))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// LoadRules reads the YAML rules of path, the default rules if path is empty
+func LoadRules(path string) (*Rules, error) {
+	if path == "" {
+		return ParseRules(defaultRules)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(b)
+}
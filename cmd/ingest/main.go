@@ -2,68 +2,63 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/joho/godotenv"
+	"go.opentelemetry.io/otel"
 
-	analyzer "github.com/MaxBear/maxhire/analyzer/openai"
-	gcpAppScriptService "github.com/MaxBear/maxhire/deps/gcp/AppScriptService"
+	"github.com/MaxBear/maxhire/analyzer"
+	"github.com/MaxBear/maxhire/classifier"
+	"github.com/MaxBear/maxhire/config"
 	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/service"
+	"github.com/MaxBear/maxhire/source"
+	_ "github.com/MaxBear/maxhire/source/builtin"
+	"github.com/MaxBear/maxhire/storage/sqlite"
+	"github.com/MaxBear/maxhire/telemetry"
 )
 
 func validTimeRange(start_time, end_time string) bool {
 	// time.DateOnly is a predefined constant for "2006-01-02"
 	ts, err := time.Parse(time.DateOnly, start_time)
 	if err != nil {
-		log.Printf("error parsing start time, error: %s", err.Error())
+		slog.Error("error parsing start time", "start_time", start_time, "error", err)
 		return false
 	}
 
 	te, err := time.Parse(time.DateOnly, end_time)
 	if err != nil {
-		log.Printf("error parsing end time, error: %s", err.Error())
+		slog.Error("error parsing end time", "end_time", end_time, "error", err)
 		return false
 	}
 
 	if ts.Sub(te) > 24*time.Hour {
-		log.Printf("Start time must be more than 24 hours after end time.")
+		slog.Error("start time must be more than 24 hours after end time")
 		return false
 	}
 
 	return true
 }
 
-func genApplicationData(ctx context.Context, start_time, end_time, jsonFile, csvFile string, useLlm bool) error {
-	// Initialize gcp app script service
-	appScriptDeploymentId := os.Getenv("APP_SCRIPT_DEPLOYMENT_ID")
-
-	s, err := gcpAppScriptService.New(
-		ctx,
-		gcpAppScriptService.WithOauthRedirectPort(8080),
-		gcpAppScriptService.WithOauthRedirectUrl("http://localhost:8080"),
-		gcpAppScriptService.WithCredFile("../../configs/gcp_app_script_credentials.json"),
-		gcpAppScriptService.WithTokFile("../../configs/gcp_oauth_token.json"),
-		gcpAppScriptService.WithAppScriptDeploymentId(appScriptDeploymentId),
-	)
+// genApplicationData writes the emails of the source sent from start to end excluded to the json and csv files
+func genApplicationData(ctx context.Context, logger *slog.Logger, s source.EmailSource, start, end time.Time, jsonFile, csvFile string) error {
+	logger.InfoContext(ctx, "email source call starts", "source", s.Name(), "start", start, "end", end)
+	began := time.Now()
+	raws, err := source.Fetch(ctx, s, start, end)
 	if err != nil {
-		log.Printf("error initializing GCP App Script Service, err :%s", err.Error())
+		logger.ErrorContext(ctx, "error getting application emails", "source", s.Name(), "error", err)
 		return err
 	}
-
-	log.Printf("App Script call starts...")
-	start := time.Now()
-	raws, err := s.GetApplicationEmails(start_time, end_time)
-	if err != nil {
-		log.Printf("Error get application emails using Gcp App Script service, error: %s", err.Error())
-		return err
-	}
-	duration := time.Since(start)
-	log.Printf("App Script call took: %0.3f (s), %d (ms)\n", duration.Seconds(), duration.Milliseconds())
+	logger.InfoContext(ctx, "email source call completed", "source", s.Name(), "emails", len(raws), "duration", time.Since(began))
 
 	emails := raws.ToEmails()
 
@@ -75,6 +70,56 @@ func genApplicationData(ctx context.Context, start_time, end_time, jsonFile, csv
 	return nil
 }
 
+// loadEmails returns the emails of a json file, none if it does not exist yet
+func loadEmails(jsonFile string) (gcp.Emails, error) {
+	if _, err := os.Stat(jsonFile); errors.Is(err, fs.ErrNotExist) {
+		return gcp.Emails{}, nil
+	}
+	return gcp.FromJson(jsonFile)
+}
+
+// genIncrementalData adds the emails of the source sent from start to end excluded, and not read since its
+// checkpoint, to the emails of the json and csv files, then saves the checkpoint
+func genIncrementalData(ctx context.Context, logger *slog.Logger, s source.EmailSource, checkpointFile string, start, end time.Time, jsonFile, csvFile string) error {
+	checkpoints, err := source.LoadCheckpoints(checkpointFile)
+	if err != nil {
+		logger.ErrorContext(ctx, "unable to load checkpoints", "file", checkpointFile, "error", err)
+		return err
+	}
+	existing, err := loadEmails(jsonFile)
+	if err != nil {
+		logger.ErrorContext(ctx, "unable to load application data", "file", jsonFile, "error", err)
+		return err
+	}
+
+	logger.InfoContext(ctx, "email source call starts", "source", s.Name(), "checkpoint", source.Key(s), "start", start, "end", end, "incremental", true)
+	began := time.Now()
+	raws, err := source.FetchNew(ctx, s, checkpoints, start, end)
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting application emails", "source", s.Name(), "error", err)
+		return err
+	}
+
+	emails, added := existing.Merge(raws.ToEmails())
+	logger.InfoContext(ctx, "email source call completed", "source", s.Name(), "emails", len(raws), "added", added, "duration", time.Since(began))
+
+	// the checkpoint is only saved once the emails read are
+	if added > 0 {
+		if err := emails.ToCsv(csvFile); err != nil {
+			return err
+		}
+		if err := emails.ToJson(jsonFile); err != nil {
+			return err
+		}
+	}
+	if err := checkpoints.Save(checkpointFile); err != nil {
+		logger.ErrorContext(ctx, "unable to save checkpoints", "file", checkpointFile, "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func fname(orig string) string {
 	extension := filepath.Ext(orig)
 
@@ -84,27 +129,73 @@ func fname(orig string) string {
 	return fmt.Sprintf("%s_llm", nameWithoutExtension)
 }
 
-func analyzeApplicationData(ctx context.Context, jsonFile string) error {
+// analyzeApplicationData classifies the emails of jsonFile with the rules, and with the LLM the emails
+// the rules are not confident about unless rulesOnly. If incremental, only the emails not analyzed yet are,
+// and added to the analyzed ones.
+func analyzeApplicationData(ctx context.Context, logger *slog.Logger, cfg config.LLM, rulesCfg config.Rules, jsonFile string, rulesOnly, incremental bool) error {
 	emails, err := gcp.FromJson(jsonFile)
 	if err != nil {
-		log.Printf("unable to load application data from %s\n, error: %s", jsonFile, err.Error())
+		logger.ErrorContext(ctx, "unable to load application data", "file", jsonFile, "error", err)
 		return err
 	}
 
-	llm, err := analyzer.New()
+	analyzed := gcp.Emails{}
+	if incremental {
+		analyzedFile := fmt.Sprintf("%s.json", fname(jsonFile))
+		if analyzed, err = loadEmails(analyzedFile); err != nil {
+			logger.ErrorContext(ctx, "unable to load analyzed application data", "file", analyzedFile, "error", err)
+			return err
+		}
+		keys := map[string]bool{}
+		for _, email := range analyzed {
+			keys[email.EmailRecord.Key()] = true
+		}
+		emails = slices.DeleteFunc(emails, func(email *gcp.Email) bool {
+			return keys[email.EmailRecord.Key()]
+		})
+		logger.InfoContext(ctx, "emails not analyzed yet", "emails", len(emails), "analyzed", len(analyzed))
+		if len(emails) == 0 {
+			return nil
+		}
+	}
+
+	rules, err := classifier.LoadRules(rulesCfg.ATSRules)
 	if err != nil {
-		log.Printf("error initialize Llm analyzers, error: %s", err.Error())
+		logger.ErrorContext(ctx, "error loading classifier rules", "file", rulesCfg.ATSRules, "error", err)
 		return err
 	}
+	opts := []classifier.ClassifierOpt{
+		classifier.WithMinConfidence(rulesCfg.MinConfidence),
+		classifier.WithLogger(logger),
+	}
 
-	errs := llm.AnalyzeEmails(ctx, emails)
+	if !rulesOnly {
+		llm, err := analyzer.New(cfg.Provider,
+			analyzer.WithModel(cfg.Model),
+			analyzer.WithBaseURL(cfg.BaseURL),
+			analyzer.WithTimeout(cfg.Timeout),
+			analyzer.WithMaxQueries(cfg.Concurrency),
+			analyzer.WithRetries(cfg.Retries),
+			analyzer.WithLogger(logger),
+		)
+		if err != nil {
+			logger.ErrorContext(ctx, "error initializing LLM analyzer", "error", err)
+			return err
+		}
+		opts = append(opts, classifier.WithFallback(llm))
+	}
+
+	errs := classifier.New(rules, opts...).AnalyzeEmails(ctx, emails)
 	if len(errs) > 0 {
-		log.Println("error analyzing email applications, errors:")
 		for i, err := range errs {
-			log.Printf("%d %s", i, err.Error())
+			logger.ErrorContext(ctx, "error analyzing email applications", "index", i, "error", err)
 		}
 	}
 
+	if incremental {
+		emails, _ = analyzed.Merge(emails)
+	}
+
 	// change PENDING applications being REJECTED to status APPLIED
 	emails.UpdateStatus()
 
@@ -116,46 +207,143 @@ func analyzeApplicationData(ctx context.Context, jsonFile string) error {
 	return nil
 }
 
+// markGhosted marks the applications stored in the database without a response for ghostedAfterDays as ghosted
+func markGhosted(ctx context.Context, logger *slog.Logger, db string, ghostedAfterDays int) error {
+	store, err := sqlite.Open(ctx, db)
+	if err != nil {
+		logger.ErrorContext(ctx, "error opening database", "db", db, "error", err)
+		return err
+	}
+	defer store.Close()
+
+	svc, err := service.NewService(ctx, "", service.WithStore(store), service.WithLogger(logger))
+	if err != nil {
+		logger.ErrorContext(ctx, "error initializing service", "error", err)
+		return err
+	}
+
+	marked, err := svc.MarkGhosted(ctx, time.Duration(ghostedAfterDays)*24*time.Hour)
+	if err != nil {
+		logger.ErrorContext(ctx, "error marking ghosted applications", "error", err)
+		return err
+	}
+
+	for _, app := range marked {
+		logger.InfoContext(ctx, "ghosted", "company", app.Company, "position", app.Position, "date", app.Date.Format(time.DateOnly))
+	}
+	logger.InfoContext(ctx, "marked applications as ghosted", "count", len(marked))
+
+	return nil
+}
+
 func main() {
+	cfg := config.Default()
+	config.Register(flag.CommandLine, cfg, config.SectionStorage, config.SectionGhosted, config.SectionIngest, config.SectionGoogle, config.SectionImap, config.SectionLLM, config.SectionRules,
+		config.SectionTelemetry, config.SectionLog)
 	csv := flag.String("csv", "raw.csv", "csv file contains job application records")
 	json := flag.String("json", "raw.json", "json file contains job application records")
 	gen := flag.Bool("gen", false, "generating job application records to csv file")
 	start_time := flag.String("start_time", "", "start time for filtering job applications, format: 2006-01-01")
 	end_time := flag.String("end_time", "", "end time for filtering job applications, format: 2006-01-02")
 	llm := flag.Bool("llm", false, "using LLM to analyze job applications")
+	rulesOnly := flag.Bool("rules", false, "classify job applications with the rules only, without the LLM")
+	incremental := flag.Bool("incremental", false, "only read the emails since the checkpoint of the source, added to the -json and -csv files, and only analyze the emails not analyzed yet")
+	ghosted := flag.Bool("ghosted", false, "mark applications in the -db database without a response as ghosted")
 
 	flag.Parse()
 
-	if *gen == false && *llm == false {
-		os.Exit(0)
+	if err := config.Load(flag.CommandLine, cfg); err != nil {
+		log.Printf("invalid configuration, error: %s", err.Error())
+		os.Exit(1)
 	}
+	// the sources are registered by name, not known to config
+	if !slices.Contains(source.Names(), cfg.Ingest.Source) {
+		log.Printf("invalid configuration, error: invalid ingest.source %q, expecting one of: %s", cfg.Ingest.Source, strings.Join(source.Names(), ", "))
+		os.Exit(1)
+	}
+	gcp.InvalidCompanyWords = cfg.Rules.InvalidCompanyWords
+	gcp.NoReplyPrefixes = cfg.Rules.NoReplyPrefixes
 
-	ctx := context.Background()
+	logger, err := cfg.Log.NewLogger(os.Stderr)
+	if err != nil {
+		log.Printf("error configuring logs, error: %s", err.Error())
+		os.Exit(1)
+	}
+	// the logs of the packages still using the log package go through the logger too
+	slog.SetDefault(logger)
 
-	err := godotenv.Load("../../configs/.env")
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), "maxhire-ingest", cfg.Telemetry.OTLPEndpoint)
 	if err != nil {
-		log.Printf("Error loading .env file, error: %s", err.Error())
+		logger.Error("error setting up tracing", "error", err)
 		os.Exit(1)
 	}
+	ctx, span := otel.Tracer("github.com/MaxBear/maxhire/cmd/ingest").Start(context.Background(), "ingest")
+
+	// exit exports the spans and writes the metrics of the run before exiting
+	exit := func(code int) {
+		span.End()
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("error exporting spans", "error", err)
+		}
+		if cfg.Telemetry.MetricsFile != "" {
+			if err := telemetry.WriteFile(cfg.Telemetry.MetricsFile); err != nil {
+				logger.Error("error writing metrics", "file", cfg.Telemetry.MetricsFile, "error", err)
+			}
+		}
+		os.Exit(code)
+	}
 
-	if *gen {
-		if !validTimeRange(*start_time, *end_time) {
-			log.Printf("Invalid time range")
-			os.Exit(1)
+	// Works on the api server database only, no credentials needed
+	if *ghosted {
+		if cfg.Storage.DSN == "" {
+			logger.Error("-db is required with -ghosted")
+			exit(1)
 		}
+		if err := markGhosted(ctx, logger, cfg.Storage.DSN, cfg.Ghosted.AfterDays); err != nil {
+			exit(1)
+		}
+	}
 
-		err := genApplicationData(ctx, *start_time, *end_time, *json, *csv, *llm)
+	// the mailbox source, e.g. selected by -mbox or -eml-dir, imports the local mailbox files without -gen
+	importing := cfg.Ingest.Source == config.SourceMailbox
+
+	if *gen == false && importing == false && *llm == false && *rulesOnly == false {
+		exit(0)
+	}
+
+	if *gen || importing {
+		// the mailbox files are read whole, and the incremental reads since the checkpoint, unless a time
+		// range is given
+		whole := (importing || *incremental) && *start_time == "" && *end_time == ""
+		if !whole && !validTimeRange(*start_time, *end_time) {
+			logger.Error("invalid time range")
+			exit(1)
+		}
+		start, end, _ := gcp.DateRange(*start_time, *end_time)
+
+		s, err := source.New(ctx, cfg.Ingest.Source, logger, cfg)
 		if err != nil {
-			os.Exit(1)
+			logger.ErrorContext(ctx, "error initializing email source", "source", cfg.Ingest.Source, "error", err)
+			exit(1)
+		}
+		if *incremental {
+			err = genIncrementalData(ctx, logger, s, cfg.Ingest.CheckpointFile, start, end, *json, *csv)
+		} else {
+			err = genApplicationData(ctx, logger, s, start, end, *json, *csv)
+		}
+		if err != nil {
+			exit(1)
 		}
 	}
 
-	// Use llm to populate fields such as company name, application status etc.
-	if *llm {
-		err := analyzeApplicationData(ctx, *json)
+	// Use the rules and llm to populate fields such as company name, application status etc.
+	if *llm || *rulesOnly {
+		err := analyzeApplicationData(ctx, logger, cfg.LLM, cfg.Rules, *json, *rulesOnly, *incremental)
 
 		if err != nil {
-			os.Exit(1)
+			exit(1)
 		}
 	}
+
+	exit(0)
 }
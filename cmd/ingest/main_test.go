@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MaxBear/maxhire/analyzer"
+	"github.com/MaxBear/maxhire/classifier"
+	"github.com/MaxBear/maxhire/config"
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/source"
+	"github.com/MaxBear/maxhire/source/fake"
+)
+
+// TestPipeline generates the records of the emails of a fake source, and classifies them with the rules
+// and the fake LLM
+func TestPipeline(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	jsonFile, csvFile := filepath.Join(dir, "raw.json"), filepath.Join(dir, "raw.csv")
+
+	s := fake.New(fake.WithEmails(
+		&gcp.RawEmailRecord{
+			SentTime:   time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC),
+			FullSender: "no-reply@us.greenhouse-mail.io",
+			Domain:     "us.greenhouse-mail.io",
+			Subject:    "Thank you for applying to Lyft",
+			Msg:        "Hello xx, Thank you for your interest in Lyft! We wanted to let you know we received your application for Software Engineer, and we are delighted that you would consider joining our team.",
+		},
+		&gcp.RawEmailRecord{
+			SentTime:   time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC),
+			FullSender: "no-reply@hire.lever.co",
+			Domain:     "hire.lever.co",
+			Subject:    "Your application to Plaid",
+			Msg:        "Hi xx, Thank you for your interest in Plaid. Unfortunately, after reviewing your application for the Backend Engineer role, we have decided not to move forward at this time.",
+		},
+		// classified by the LLM
+		&gcp.RawEmailRecord{
+			SentTime:   time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC),
+			FullSender: "careers@initech.com",
+			Domain:     "initech.com",
+			Subject:    "Next steps",
+			Msg:        "Hi xx, thank you for your interest in Initech. We would like to schedule an interview for the Data Engineer role.",
+		},
+		&gcp.RawEmailRecord{
+			SentTime:   time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC),
+			FullSender: "later@example.com",
+			Subject:    "Out of range",
+		},
+	))
+
+	start, end, err := gcp.DateRange("2025-03-01", "2025-03-04")
+	require.NoError(t, err)
+	require.NoError(t, genApplicationData(ctx, slog.Default(), s, start, end, jsonFile, csvFile))
+	assert.Equal(t, []fake.Request{{Start: start, End: end}}, s.Requests())
+
+	assert.FileExists(t, csvFile)
+	raws, err := gcp.FromJson(jsonFile)
+	require.NoError(t, err)
+	assert.Len(t, raws, 3)
+
+	cfg := config.Default()
+	cfg.LLM.Provider = analyzer.ProviderFake
+	require.NoError(t, analyzeApplicationData(ctx, slog.Default(), cfg.LLM, cfg.Rules, jsonFile, false, false))
+
+	emails, err := gcp.FromJson(filepath.Join(dir, "raw_llm.json"))
+	require.NoError(t, err)
+	require.Len(t, emails, 3)
+
+	assert.Equal(t, "Lyft", emails[0].Company)
+	assert.Equal(t, "Plaid", emails[1].Company)
+	assert.Equal(t, gcp.Reject, emails[1].Status)
+	assert.Equal(t, "Backend Engineer", emails[1].Position)
+	assert.Equal(t, "Initech", emails[2].Company)
+	assert.Equal(t, gcp.Interviewing, emails[2].Status)
+	assert.Equal(t, "fake/3", emails[2].EmailRecord.MessageId)
+
+	// the records are not written if the source fails
+	failing := fake.New(fake.WithError(errors.New("unavailable")))
+	jsonFile = filepath.Join(dir, "failed.json")
+	assert.Error(t, genApplicationData(ctx, slog.Default(), failing, time.Time{}, time.Time{}, jsonFile, filepath.Join(dir, "failed.csv")))
+	assert.NoFileExists(t, jsonFile)
+}
+
+func TestConfig(t *testing.T) {
+	// config names the providers and defaults without importing analyzer and classifier
+	assert.Equal(t, analyzer.Providers, config.LLMProviders)
+	assert.Equal(t, analyzer.ProviderOpenAI, config.Default().LLM.Provider)
+	assert.Equal(t, classifier.DefaultMinConfidence, config.Default().Rules.MinConfidence)
+}
+
+// TestIncremental adds the new emails of the fake source to the records, and only analyzes them
+func TestIncremental(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	jsonFile, csvFile, checkpointFile := filepath.Join(dir, "raw.json"), filepath.Join(dir, "raw.csv"), filepath.Join(dir, "checkpoint.json")
+	cfg := config.Default()
+
+	lyft := &gcp.RawEmailRecord{
+		SentTime:   time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC),
+		FullSender: "no-reply@us.greenhouse-mail.io",
+		Msg:        "Hello xx, Thank you for your interest in Lyft! We received your application for Software Engineer.",
+		MessageId:  "lyft",
+	}
+	plaid := &gcp.RawEmailRecord{
+		SentTime:   time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC),
+		FullSender: "no-reply@hire.lever.co",
+		Msg:        "Hi xx, Thank you for your interest in Plaid. Unfortunately, after reviewing your application for the Backend Engineer role, we have decided not to move forward at this time.",
+		MessageId:  "plaid",
+	}
+
+	require.NoError(t, genIncrementalData(ctx, slog.Default(), fake.New(fake.WithEmails(lyft)), checkpointFile, time.Time{}, time.Time{}, jsonFile, csvFile))
+	require.NoError(t, analyzeApplicationData(ctx, slog.Default(), cfg.LLM, cfg.Rules, jsonFile, true, true))
+
+	// an edit of the analyzed email is kept, it is not analyzed again
+	analyzedFile := filepath.Join(dir, "raw_llm.json")
+	analyzed, err := gcp.FromJson(analyzedFile)
+	require.NoError(t, err)
+	require.Len(t, analyzed, 1)
+	assert.Equal(t, "Lyft", analyzed[0].Company)
+	analyzed[0].Company = "Lyft Inc"
+	require.NoError(t, analyzed.ToJson(analyzedFile))
+
+	s := fake.New(fake.WithEmails(lyft, plaid))
+	require.NoError(t, genIncrementalData(ctx, slog.Default(), s, checkpointFile, time.Time{}, time.Time{}, jsonFile, csvFile))
+	// resumed at the checkpoint
+	assert.Equal(t, lyft.SentTime.Add(-source.Overlap), s.Requests()[0].Start)
+	require.NoError(t, analyzeApplicationData(ctx, slog.Default(), cfg.LLM, cfg.Rules, jsonFile, true, true))
+
+	raws, err := gcp.FromJson(jsonFile)
+	require.NoError(t, err)
+	assert.Len(t, raws, 2)
+
+	analyzed, err = gcp.FromJson(analyzedFile)
+	require.NoError(t, err)
+	require.Len(t, analyzed, 2)
+	// newest first
+	assert.Equal(t, "Plaid", analyzed[0].Company)
+	assert.Equal(t, gcp.Reject, analyzed[0].Status)
+	assert.Equal(t, "Lyft Inc", analyzed[1].Company)
+
+	// nothing new, nothing analyzed
+	require.NoError(t, genIncrementalData(ctx, slog.Default(), s, checkpointFile, time.Time{}, time.Time{}, jsonFile, csvFile))
+	raws, err = gcp.FromJson(jsonFile)
+	require.NoError(t, err)
+	assert.Len(t, raws, 2)
+	cfg.LLM.Provider = "unavailable"
+	require.NoError(t, analyzeApplicationData(ctx, slog.Default(), cfg.LLM, cfg.Rules, jsonFile, false, true))
+}
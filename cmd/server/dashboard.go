@@ -0,0 +1,20 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed web
+var web embed.FS
+
+// dashboard serves the web dashboard, which calls the http/json api served on the same address
+func dashboard() http.Handler {
+	files, err := fs.Sub(web, "web")
+	if err != nil {
+		// web is embedded, can't be missing
+		panic(err)
+	}
+	return http.FileServerFS(files)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+
+	applicationspb "github.com/MaxBear/maxhire/proto/gen/go/applications/v1"
+	"github.com/MaxBear/maxhire/storage"
+)
+
+// unauthenticatedServices can be called without a bearer token, so probes and tools don't need one
+var unauthenticatedServices = []string{
+	healthpb.Health_ServiceDesc.ServiceName,
+	reflectionv1.ServerReflection_ServiceDesc.ServiceName,
+	reflectionv1alpha.ServerReflection_ServiceDesc.ServiceName,
+}
+
+// reportHealth sets the status of the server, and of the applications service, from the readiness
+// of the store every interval until ctx is done
+func reportHealth(ctx context.Context, healthServer *health.Server, store storage.Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		status := healthpb.HealthCheckResponse_SERVING
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		if err := store.Ping(pingCtx); err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			if last != status {
+				slog.ErrorContext(ctx, "storage not ready", "error", err)
+			}
+		}
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		healthServer.SetServingStatus("", status)
+		healthServer.SetServingStatus(applicationspb.Applications_ServiceDesc.ServiceName, status)
+		last = status
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// shutdown stops accepting calls and waits up to timeout for the calls in progress of the grpc server and
+// the http servers, nil ones are skipped. The remaining
+// ones are cancelled. Watch streams only end when their client goes away, so they are usually cancelled.
+func shutdown(grpcServer *grpc.Server, timeout time.Duration, httpServers ...*http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, httpServer := range httpServers {
+		if httpServer == nil {
+			continue
+		}
+		wg.Go(func() {
+			if err := httpServer.Shutdown(ctx); err != nil {
+				httpServer.Close()
+			}
+		})
+	}
+	wg.Go(func() {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			slog.Warn("calls still in progress, cancelling them", "timeout", timeout)
+			grpcServer.Stop()
+		}
+	})
+	wg.Wait()
+}
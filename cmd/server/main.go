@@ -2,44 +2,279 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
 
+	"github.com/MaxBear/maxhire/auth"
+	"github.com/MaxBear/maxhire/certs"
+	"github.com/MaxBear/maxhire/config"
+	"github.com/MaxBear/maxhire/gateway"
+	"github.com/MaxBear/maxhire/logging"
 	applicationspb "github.com/MaxBear/maxhire/proto/gen/go/applications/v1"
 	"github.com/MaxBear/maxhire/server"
 	"github.com/MaxBear/maxhire/service"
+	"github.com/MaxBear/maxhire/storage"
+	"github.com/MaxBear/maxhire/storage/memory"
+	"github.com/MaxBear/maxhire/storage/sqlite"
+	"github.com/MaxBear/maxhire/telemetry"
 )
 
+// authenticator returns the authenticator of the configured api keys and JWKS, nil if none is
+func authenticator(cfg config.Server) (auth.Authenticator, error) {
+	authenticators := []auth.Authenticator{}
+	if cfg.APIKeys != "" {
+		keys, err := auth.LoadAPIKeys(cfg.APIKeys)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, keys)
+	}
+	if cfg.JWKS != "" {
+		verifier, err := auth.NewJWTVerifier(cfg.JWKS,
+			auth.WithIssuer(cfg.JWTIssuer),
+			auth.WithAudience(cfg.JWTAudience),
+			auth.WithTenantClaim(cfg.JWTTenantClaim))
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, verifier)
+	}
+	if len(authenticators) == 0 {
+		return nil, nil
+	}
+	return auth.Chain(authenticators...), nil
+}
+
 func main() {
+	cfg := config.Default()
+	config.Register(flag.CommandLine, cfg, config.SectionServer, config.SectionStorage, config.SectionGhosted,
+		config.SectionTelemetry, config.SectionLog)
 	json := flag.String("json", "", "json file contains job application records")
 	flag.Parse()
 
-	ctx, cancel := context.WithCancel(context.Background())
+	if err := config.Load(flag.CommandLine, cfg); err != nil {
+		log.Printf("invalid configuration, error: %s", err.Error())
+		os.Exit(1)
+	}
+	logger, err := cfg.Log.NewLogger(os.Stderr)
+	if err != nil {
+		log.Printf("error configuring logs, error: %s", err.Error())
+		os.Exit(1)
+	}
+	// the logs of the packages still using the log package go through the logger too
+	slog.SetDefault(logger)
+
+	// the first SIGINT or SIGTERM shuts the server down gracefully, the second one kills it
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithCancel(signalCtx)
 	defer cancel()
 
-	port := 9090
-	lis, err := net.Listen("tcp", ":9000")
+	shutdownTracing, err := telemetry.SetupTracing(ctx, "maxhire-server", cfg.Telemetry.OTLPEndpoint)
+	if err != nil {
+		logger.Error("error setting up tracing", "error", err)
+		os.Exit(1)
+	}
+
+	var files *certs.Files
+	if cfg.Server.TLSCert != "" {
+		files, err = certs.Load(cfg.Server.TLSCert, cfg.Server.TLSKey, cfg.Server.TLSClientCA)
+		if err != nil {
+			logger.Error("error loading certificate", "file", cfg.Server.TLSCert, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	lis, err := net.Listen("tcp", cfg.Server.Addr)
 	if err != nil {
-		log.Printf("error starting grpc server on port %d, error: %s", port, err.Error())
+		logger.Error("error starting grpc server", "addr", cfg.Server.Addr, "error", err)
 		os.Exit(1)
 	}
+	if files != nil {
+		lis = tls.NewListener(lis, files.ServerConfig("h2"))
+	}
 
-	svc, err := service.NewService(ctx, *json)
+	var store storage.Store = memory.New()
+	if cfg.Storage.DSN != "" {
+		store, err = sqlite.Open(ctx, cfg.Storage.DSN)
+		if err != nil {
+			logger.Error("error opening database", "db", cfg.Storage.DSN, "error", err)
+			os.Exit(1)
+		}
+	} else if cfg.Storage.Snapshot != "" {
+		store, err = memory.Open(cfg.Storage.Snapshot)
+		if err != nil {
+			logger.Error("error loading snapshot", "file", cfg.Storage.Snapshot, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	svc, err := service.NewService(ctx, *json, service.WithStore(store), service.WithLogger(logger))
 	if err != nil {
-		log.Printf("error starting grpc service, error: %s", err.Error())
+		logger.Error("error starting grpc service", "error", err)
 		os.Exit(1)
 	}
 
-	srv := server.New(svc)
-	grpcServer := grpc.NewServer()
-	applicationspb.RegisterApplicationsServer(grpcServer, srv)
+	if cfg.Ghosted.AfterDays > 0 {
+		go svc.RunGhostedDetection(ctx, time.Duration(cfg.Ghosted.AfterDays)*24*time.Hour, cfg.Ghosted.Interval)
+	}
 
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Printf("error starting grpc server, error: %s", err.Error())
+	authn, err := authenticator(cfg.Server)
+	if err != nil {
+		logger.Error("error configuring authentication", "error", err)
 		os.Exit(1)
 	}
+	// calls are counted, traced and logged before authentication, so that rejected calls are too
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		telemetry.UnaryServerInterceptor(),
+		logging.UnaryServerInterceptor(logger, healthpb.Health_ServiceDesc.ServiceName),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		telemetry.StreamServerInterceptor(),
+		logging.StreamServerInterceptor(logger, healthpb.Health_ServiceDesc.ServiceName),
+	}
+	if authn != nil {
+		unaryInterceptors = append(unaryInterceptors, auth.UnaryServerInterceptor(authn, unauthenticatedServices...))
+		streamInterceptors = append(streamInterceptors, auth.StreamServerInterceptor(authn, unauthenticatedServices...))
+	} else {
+		logger.Warn("no api keys or JWKS configured, serving without authentication")
+	}
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+
+	if err := telemetry.RegisterApplications(store, logger); err != nil {
+		logger.Error("error registering application metrics", "error", err)
+		os.Exit(1)
+	}
+
+	srv := server.New(svc, server.WithLogger(logger))
+	grpcServer := grpc.NewServer(serverOpts...)
+	applicationspb.RegisterApplicationsServer(grpcServer, srv)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	go reportHealth(ctx, healthServer, store, cfg.Server.HealthInterval)
+	if cfg.Server.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	serveErrs := make(chan error, 3)
+	var httpServer *http.Server
+
+	if cfg.Server.HTTPAddr != "" {
+		// the gateway calls the grpc server like any other client, through an in-memory connection so
+		// it needs no client certificate
+		local := bufconn.Listen(1 << 20)
+		go grpcServer.Serve(local)
+		conn, err := grpc.NewClient("passthrough:///gateway",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return local.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+		if err != nil {
+			logger.Error("error connecting http gateway to grpc server", "error", err)
+			os.Exit(1)
+		}
+		defer conn.Close()
+
+		opts := []gateway.GatewayOpt{gateway.WithLogger(logger)}
+		if len(cfg.Server.CORSOrigins) > 0 {
+			opts = append(opts, gateway.WithAllowedOrigins(cfg.Server.CORSOrigins...))
+		}
+		gw := gateway.New(applicationspb.NewApplicationsClient(conn), opts...)
+		mux := http.NewServeMux()
+		mux.Handle("/v1/", gw)
+		mux.Handle("/openapi.json", gw)
+		if cfg.Server.Dashboard {
+			mux.Handle("/", dashboard())
+		}
+		httpServer = &http.Server{Addr: cfg.Server.HTTPAddr, Handler: otelhttp.NewHandler(mux, "http")}
+		go func() {
+			var err error
+			if files != nil {
+				httpServer.TLSConfig = files.ServerConfig("h2", "http/1.1")
+				err = httpServer.ListenAndServeTLS("", "")
+			} else {
+				err = httpServer.ListenAndServe()
+			}
+			if !errors.Is(err, http.ErrServerClosed) {
+				serveErrs <- fmt.Errorf("http server on %s: %w", cfg.Server.HTTPAddr, err)
+			}
+		}()
+	}
+
+	// the metrics count the applications of every tenant, they are kept off the public addresses
+	var metricsServer *http.Server
+	if cfg.Server.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", telemetry.Handler())
+		metricsServer = &http.Server{Addr: cfg.Server.MetricsAddr, Handler: mux}
+		logger.Info("serving metrics", "addr", cfg.Server.MetricsAddr)
+		go func() {
+			if err := metricsServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				serveErrs <- fmt.Errorf("metrics server on %s: %w", cfg.Server.MetricsAddr, err)
+			}
+		}()
+	}
+
+	logger.Info("serving grpc api", "addr", cfg.Server.Addr)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			serveErrs <- fmt.Errorf("grpc server on %s: %w", cfg.Server.Addr, err)
+		}
+	}()
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		logger.Info("shutting down, waiting for the calls in progress", "timeout", cfg.Server.ShutdownTimeout)
+	case err := <-serveErrs:
+		logger.Error("error serving, shutting down", "error", err)
+		exitCode = 1
+	}
+	stop()
+	healthServer.Shutdown()
+	shutdown(grpcServer, cfg.Server.ShutdownTimeout, httpServer, metricsServer)
+	cancel()
+
+	// flushes the applications kept in memory to the snapshot
+	if err := store.Close(); err != nil {
+		logger.Error("error closing storage", "error", err)
+		exitCode = 1
+	}
+
+	// the context is done by now, the spans are exported within the shutdown timeout
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer flushCancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Error("error exporting spans", "error", err)
+	}
+	if cfg.Telemetry.MetricsFile != "" {
+		if err := telemetry.WriteFile(cfg.Telemetry.MetricsFile); err != nil {
+			logger.Error("error writing metrics", "file", cfg.Telemetry.MetricsFile, "error", err)
+		}
+	}
+	os.Exit(exitCode)
 }
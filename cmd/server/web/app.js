@@ -0,0 +1,421 @@
+// Dashboard of the applications api, talks to the http/json gateway served next to it.
+"use strict";
+
+const PAGE_SIZE = 50;
+const DAY = 24 * 3600;
+
+const $ = (id) => document.getElementById(id);
+
+const state = {
+  statuses: [],
+  interviewTypes: [],
+  nextPageToken: "",
+  selected: null,
+};
+
+// authHeaders returns the headers authenticating with the token entered, if the server asked for one
+function authHeaders() {
+  const token = localStorage.getItem("token");
+  return token ? { Authorization: `Bearer ${token}` } : {};
+}
+
+// login asks for a new token after the server rejected the token of the request, and reports whether
+// the request should be sent again
+function login(used, message) {
+  // another request already asked for a new token
+  if (localStorage.getItem("token") !== used) {
+    return true;
+  }
+  const token = prompt(`${message}, enter your api key or token:`);
+  if (!token) {
+    return false;
+  }
+  localStorage.setItem("token", token.trim());
+  return true;
+}
+
+async function api(method, path, body) {
+  for (;;) {
+    const token = localStorage.getItem("token");
+    const res = await fetch(path, {
+      method,
+      headers: { ...authHeaders(), ...(body ? { "Content-Type": "application/json" } : {}) },
+      body: body ? JSON.stringify(body) : undefined,
+    });
+    const json = await res.json();
+    if (res.status === 401 && login(token, json.message || res.statusText)) {
+      continue;
+    }
+    if (!res.ok) {
+      throw new Error(json.message || res.statusText);
+    }
+    return json;
+  }
+}
+
+function el(tag, attrs = {}, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs)) {
+    node.setAttribute(key, value);
+  }
+  node.append(...children);
+  return node;
+}
+
+function label(name) {
+  return name.toLowerCase().replaceAll("_", " ");
+}
+
+function formatDate(timestamp) {
+  return timestamp ? new Date(timestamp).toLocaleDateString() : "";
+}
+
+function formatDateTime(timestamp) {
+  return timestamp ? new Date(timestamp).toLocaleString() : "";
+}
+
+// Durations are encoded as seconds with an s suffix
+function seconds(duration) {
+  return duration ? parseFloat(duration) : 0;
+}
+
+function formatDays(duration) {
+  const days = seconds(duration) / DAY;
+  return days < 1 ? `${Math.round(days * 24)}h` : `${Math.round(days * 10) / 10}d`;
+}
+
+function options(select, values, selected) {
+  select.replaceChildren(...values.map((value) => {
+    const option = el("option", { value }, label(value));
+    option.selected = value === selected;
+    return option;
+  }));
+}
+
+// Enum values come from the OpenAPI document, generated from the proto
+async function loadEnums() {
+  const spec = await api("GET", "/openapi.json");
+  const schemas = spec.components.schemas;
+  state.statuses = schemas["maxbear.maxhire.Application"].properties.status.enum;
+  state.interviewTypes = schemas["maxbear.maxhire.Interview"].properties.interview_type.enum;
+
+  options(document_.filters.statuses, state.statuses);
+  document_.filters.statuses.size = 4;
+  options(document_.edit.status, state.statuses);
+  options(document_.addInterview.interview_type, state.interviewTypes.filter((t) => t !== "UNSPECIFIED"));
+}
+
+const document_ = {
+  get filters() { return $("filters").elements; },
+  get edit() { return $("edit").elements; },
+  get addInterview() { return $("add-interview").elements; },
+};
+
+// Query string of the filter form, shared by the list and the statistics
+function filterQuery() {
+  const form = document_.filters;
+  const query = new URLSearchParams();
+  if (form.company.value) {
+    query.set("company", form.company.value);
+    query.set("company_prefix", "true");
+  }
+  if (form.query.value) {
+    query.set("query", form.query.value);
+  }
+  for (const option of form.statuses.selectedOptions) {
+    query.append("statuses", option.value);
+  }
+  if (form.start_date.value) {
+    query.set("start_date", new Date(form.start_date.value).toISOString());
+  }
+  if (form.end_date.value) {
+    const end = new Date(form.end_date.value);
+    end.setUTCHours(23, 59, 59);
+    query.set("end_date", end.toISOString());
+  }
+  if (form.has_interviews.value) {
+    query.set("has_interviews", form.has_interviews.value);
+  }
+  return query;
+}
+
+function row(application) {
+  const tr = el("tr", { "data-id": application.id },
+    el("td", {}, formatDate(application.date)),
+    el("td", {}, application.company),
+    el("td", {}, application.position),
+    el("td", {}, el("span", { class: `status ${application.status.toLowerCase()}` }, label(application.status))),
+    el("td", {}, String(application.interviews.length)),
+  );
+  tr.addEventListener("click", () => showDetail(application.id));
+  return tr;
+}
+
+async function loadApplications(append = false) {
+  const query = filterQuery();
+  query.set("order_by", document_.filters.order_by.value);
+  query.set("page_size", PAGE_SIZE);
+  if (append) {
+    query.set("page_token", state.nextPageToken);
+  }
+
+  const res = await api("GET", `/v1/applications?${query}`);
+  const rows = res.applications.map(row);
+  if (append) {
+    $("rows").append(...rows);
+  } else {
+    $("rows").replaceChildren(...rows);
+  }
+  state.nextPageToken = res.next_page_token;
+  $("more").hidden = !state.nextPageToken;
+  $("total").textContent = `${res.total_size} applications`;
+  return res.revision;
+}
+
+async function showDetail(id) {
+  state.selected = id;
+  $("detail-error").textContent = "";
+  let application, events;
+  try {
+    [application, events] = await Promise.all([
+      api("GET", `/v1/applications/${id}`),
+      api("GET", `/v1/applications/${id}/events`),
+    ]);
+  } catch (err) {
+    $("detail-error").textContent = err.message;
+    return;
+  }
+  application = application.application;
+
+  $("detail").hidden = false;
+  $("detail-title").textContent = `${application.company}, ${formatDate(application.date)}`;
+  document_.edit.position.value = application.position;
+  document_.edit.status.value = application.status;
+
+  const interviews = [...application.interviews].sort((a, b) => a.datetime.localeCompare(b.datetime));
+  $("interviews").replaceChildren(...interviews.map((interview) =>
+    el("li", {}, `${formatDateTime(interview.datetime)} ${label(interview.interview_type)}, ${interview.duration_min} min`)));
+  $("detail").dataset.interviews = JSON.stringify(application.interviews);
+
+  $("timeline").replaceChildren(...events.events.map((event) => {
+    let text;
+    switch (event.type) {
+      case "EVENT_STATUS_CHANGED":
+        text = `Status ${label(event.previous_status)} → ${label(event.status)}`;
+        break;
+      case "EVENT_INTERVIEW_SCHEDULED":
+        text = `${label(event.interview_type)} interview on ${formatDateTime(event.interview_time)}`;
+        break;
+      case "EVENT_EMAIL_LINKED":
+        text = `Email ${event.message_id}`;
+        break;
+    }
+    return el("li", { class: event.type.toLowerCase() },
+      el("time", {}, formatDateTime(event.time)), " ", text,
+      el("span", { class: "source" }, label(event.source.replace("SOURCE_", ""))));
+  }));
+}
+
+async function saveApplication(event) {
+  event.preventDefault();
+  try {
+    await api("PATCH", `/v1/applications/${state.selected}?update_mask=position,status`, {
+      position: document_.edit.position.value,
+      status: document_.edit.status.value,
+    });
+  } catch (err) {
+    $("detail-error").textContent = err.message;
+    return;
+  }
+  await refresh();
+}
+
+async function addInterview(event) {
+  event.preventDefault();
+  const form = document_.addInterview;
+  const interviews = JSON.parse($("detail").dataset.interviews);
+  interviews.push({
+    datetime: new Date(form.datetime.value).toISOString(),
+    interview_type: form.interview_type.value,
+    duration_min: parseInt(form.duration_min.value, 10),
+  });
+  try {
+    await api("PUT", `/v1/applications/${state.selected}/interviews`, { interviews });
+  } catch (err) {
+    $("detail-error").textContent = err.message;
+    return;
+  }
+  form.datetime.value = "";
+  await refresh();
+}
+
+// Charts are plain svg bar charts
+const SVG = "http://www.w3.org/2000/svg";
+
+function svg(tag, attrs, text) {
+  const node = document.createElementNS(SVG, tag);
+  for (const [key, value] of Object.entries(attrs)) {
+    node.setAttribute(key, value);
+  }
+  if (text !== undefined) {
+    node.textContent = text;
+  }
+  return node;
+}
+
+// bars draws vertical bars, bars are {label, value, title}
+function bars(target, data) {
+  const width = 480, height = 200, bottom = 40, top = 14;
+  const chart = $(target);
+  chart.setAttribute("viewBox", `0 0 ${width} ${height}`);
+  chart.replaceChildren();
+  if (data.length === 0) {
+    chart.append(svg("text", { x: width / 2, y: height / 2, "text-anchor": "middle" }, "No data"));
+    return;
+  }
+  const maxValue = Math.max(1, ...data.map((d) => d.value));
+  const step = width / data.length;
+  const every = Math.ceil(data.length / 12);
+  data.forEach((d, i) => {
+    const barHeight = (d.value / maxValue) * (height - bottom - top);
+    const x = i * step + step * 0.1;
+    const y = height - bottom - barHeight;
+    const bar = svg("rect", { x, y, width: step * 0.8, height: barHeight, class: "bar" });
+    bar.append(svg("title", {}, d.title || `${d.label}: ${d.value}`));
+    chart.append(bar);
+    if (d.value > 0) {
+      chart.append(svg("text", { x: x + step * 0.4, y: y - 3, "text-anchor": "middle", class: "value" }, d.value));
+    }
+    if (i % every === 0) {
+      chart.append(svg("text", { x: x + step * 0.4, y: height - bottom + 14, "text-anchor": "middle" }, d.label));
+    }
+  });
+}
+
+// hbars draws horizontal bars with the share of the first bar, for the funnel
+function hbars(target, data) {
+  const width = 480, barHeight = 28, gap = 8, left = 90;
+  const chart = $(target);
+  const height = data.length * (barHeight + gap);
+  chart.setAttribute("viewBox", `0 0 ${width} ${height}`);
+  chart.replaceChildren();
+  const total = Math.max(1, data.length ? data[0].value : 0);
+  data.forEach((d, i) => {
+    const y = i * (barHeight + gap);
+    const barWidth = (d.value / total) * (width - left - 60);
+    chart.append(svg("text", { x: left - 6, y: y + barHeight * 0.65, "text-anchor": "end" }, d.label));
+    chart.append(svg("rect", { x: left, y, width: barWidth, height: barHeight, class: "bar" }));
+    chart.append(svg("text", { x: left + barWidth + 6, y: y + barHeight * 0.65, class: "value" },
+      `${d.value} (${Math.round((d.value / total) * 100)}%)`));
+  });
+}
+
+async function loadStats() {
+  // only the date range of the filters applies to the statistics
+  const filters = filterQuery();
+  const query = new URLSearchParams();
+  for (const name of ["start_date", "end_date"]) {
+    const value = filters.get(name);
+    if (value) {
+      query.set(name, value);
+    }
+  }
+  const stats = await api("GET", `/v1/stats?${query}`);
+
+  $("summary").replaceChildren(
+    el("div", {}, el("strong", {}, String(stats.total)), "applications"),
+    el("div", {}, el("strong", {}, `${Math.round(stats.response_rate * 100)}%`), "response rate"),
+    el("div", {}, el("strong", {}, formatDays(stats.median_time_to_first_response)), "median time to first response"),
+    el("div", {}, el("strong", {}, formatDays(stats.p90_time_to_first_response)), "90th percentile"),
+  );
+
+  bars("weekly", stats.weekly.map((week) => ({
+    label: new Date(week.week_start).toLocaleDateString(undefined, { month: "short", day: "numeric" }),
+    value: week.applications,
+    title: `Week of ${formatDate(week.week_start)}: ${week.applications}`,
+  })));
+
+  hbars("funnel", stats.funnel.map((stage) => ({ label: stage.stage, value: stage.applications })));
+
+  let lower = "0";
+  bars("response-times", stats.response_times.map((bucket) => {
+    const upper = bucket.upper_bound ? formatDays(bucket.upper_bound) : "";
+    const range = upper ? `≤ ${upper}` : `> ${lower}`;
+    lower = upper || lower;
+    return { label: range, value: bucket.applications };
+  }));
+
+  bars("statuses", stats.status_counts
+    .filter((count) => count.applications > 0)
+    .map((count) => ({ label: label(count.status), value: count.applications })));
+}
+
+async function refresh() {
+  const [revision] = await Promise.all([loadApplications(), loadStats()]);
+  if (state.selected) {
+    await showDetail(state.selected);
+  }
+  return revision;
+}
+
+// watch refreshes the dashboard whenever an application changes, resuming from the last revision seen
+async function watch(revision) {
+  for (;;) {
+    try {
+      const res = await fetch(`/v1/applications:watch?from_revision=${revision}`, { headers: authHeaders() });
+      if (!res.ok) {
+        throw new Error(res.statusText);
+      }
+      $("live").className = "on";
+      const reader = res.body.pipeThrough(new TextDecoderStream()).getReader();
+      let buffer = "";
+      let timer;
+      for (;;) {
+        const { value, done } = await reader.read();
+        if (done) {
+          break;
+        }
+        buffer += value;
+        const lines = buffer.split("\n");
+        buffer = lines.pop();
+        for (const line of lines) {
+          const change = JSON.parse(line);
+          if (change.error) {
+            throw new Error(change.error.message);
+          }
+          revision = change.result.revision;
+        }
+        // changes often come in batches
+        clearTimeout(timer);
+        timer = setTimeout(refresh, 300);
+      }
+    } catch (err) {
+      console.warn("watch interrupted", err);
+      // resume from the current state when the history was compacted
+      revision = await refresh().catch(() => revision);
+    }
+    $("live").className = "";
+    await new Promise((resolve) => setTimeout(resolve, 2000));
+  }
+}
+
+async function main() {
+  await loadEnums();
+
+  $("filters").addEventListener("submit", (event) => {
+    event.preventDefault();
+    refresh();
+  });
+  $("more").addEventListener("click", () => loadApplications(true));
+  $("close").addEventListener("click", () => {
+    state.selected = null;
+    $("detail").hidden = true;
+  });
+  $("edit").addEventListener("submit", saveApplication);
+  $("add-interview").addEventListener("submit", addInterview);
+
+  const revision = await refresh();
+  watch(revision);
+}
+
+main();
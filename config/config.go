@@ -0,0 +1,511 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/logging"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the settings, followed by the
+// upper cased name of their flag with dashes replaced by underscores, e.g. MAXHIRE_HTTP for -http
+// and MAXHIRE_EML_DIR for -eml-dir
+const EnvPrefix = "MAXHIRE_"
+
+// Sections of the configuration, see Register
+const (
+	SectionServer    = "server"
+	SectionStorage   = "storage"
+	SectionGhosted   = "ghosted"
+	SectionIngest    = "ingest"
+	SectionGoogle    = "google"
+	SectionImap      = "imap"
+	SectionLLM       = "llm"
+	SectionRules     = "rules"
+	SectionTelemetry = "telemetry"
+	SectionLog       = "log"
+)
+
+var sections = []string{SectionServer, SectionStorage, SectionGhosted, SectionIngest, SectionGoogle, SectionImap, SectionLLM, SectionRules, SectionTelemetry, SectionLog}
+
+// Config of the binaries. Every setting is read from the YAML configuration file, then from its
+// environment variable, then from its flag, the last one set wins.
+type Config struct {
+	EnvFile   string    `yaml:"env_file" flag:"env_file" usage:"file of environment variables to load, e.g. configs/.env, the variables already set are kept"`
+	Server    Server    `yaml:"server"`
+	Storage   Storage   `yaml:"storage"`
+	Ghosted   Ghosted   `yaml:"ghosted"`
+	Ingest    Ingest    `yaml:"ingest"`
+	Google    Google    `yaml:"google"`
+	Imap      Imap      `yaml:"imap"`
+	LLM       LLM       `yaml:"llm"`
+	Rules     Rules     `yaml:"rules"`
+	Telemetry Telemetry `yaml:"telemetry"`
+	Log       Log       `yaml:"log"`
+}
+
+// Server configures the applications api server
+type Server struct {
+	Addr           string   `yaml:"addr" flag:"addr" usage:"address to serve the grpc api on"`
+	HTTPAddr       string   `yaml:"http_addr" flag:"http" usage:"address to serve the http/json api on, disabled if empty"`
+	CORSOrigins    []string `yaml:"cors_origins" flag:"cors_origins" usage:"comma separated origins allowed to call the http/json api from a browser, * for any"`
+	Dashboard      bool     `yaml:"dashboard" flag:"dashboard" usage:"serve the web dashboard on the http/json api address"`
+	TLSCert        string   `yaml:"tls_cert" flag:"tls_cert" usage:"certificate file of the grpc and http/json api, served in plaintext if empty, reloaded when changed"`
+	TLSKey         string   `yaml:"tls_key" flag:"tls_key" usage:"key file of -tls_cert, reloaded when changed"`
+	TLSClientCA    string   `yaml:"tls_client_ca" flag:"tls_client_ca" usage:"CA file verifying client certificates, clients need none if empty, reloaded when changed"`
+	APIKeys        string   `yaml:"api_keys" flag:"api_keys" usage:"file of tenant:key lines, accepting the keys as bearer tokens"`
+	JWKS           string   `yaml:"jwks" flag:"jwks" usage:"JWKS file of the keys verifying JWT bearer tokens, reloaded when changed"`
+	JWTIssuer      string   `yaml:"jwt_issuer" flag:"jwt_issuer" usage:"only accept JWTs issued by this issuer"`
+	JWTAudience    string   `yaml:"jwt_audience" flag:"jwt_audience" usage:"only accept JWTs issued for this audience"`
+	JWTTenantClaim string   `yaml:"jwt_tenant_claim" flag:"jwt_tenant_claim" usage:"JWT claim holding the tenant"`
+
+	Reflection      bool          `yaml:"reflection" flag:"reflection" usage:"serve the grpc reflection service, e.g. for grpcurl"`
+	HealthInterval  time.Duration `yaml:"health_interval" flag:"health_interval" usage:"how often the storage is checked for the grpc health service"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" flag:"shutdown_timeout" usage:"how long to wait for the calls in progress to complete on SIGINT or SIGTERM"`
+	MetricsAddr     string        `yaml:"metrics_addr" flag:"metrics_addr" usage:"address to serve prometheus metrics on /metrics without authentication, keep it private, disabled if empty"`
+}
+
+// Storage configures where the job application records are kept
+type Storage struct {
+	DSN      string `yaml:"dsn" flag:"db" usage:"sqlite database file of the job application records, kept in memory if empty"`
+	Snapshot string `yaml:"snapshot" flag:"snapshot" usage:"json file the records kept in memory are loaded from on start and saved to on exit"`
+}
+
+// Ghosted configures the detection of the applications without a response
+type Ghosted struct {
+	AfterDays int           `yaml:"after_days" flag:"ghosted_after_days" usage:"mark applications without a response for this many days as ghosted, disabled if 0"`
+	Interval  time.Duration `yaml:"interval" flag:"ghosted_interval" usage:"how often the server looks for ghosted applications"`
+}
+
+// Ingest configures the ingestion of the job application emails
+type Ingest struct {
+	Source         string `yaml:"source" flag:"source" usage:"source of the emails, the name of a registered source, e.g. appscript, gmail, imap or mailbox"`
+	Mbox           string `yaml:"mbox" flag:"mbox" usage:"mbox file read by the mailbox source, e.g. of Google Takeout, selects the mailbox source if set"`
+	EmlDir         string `yaml:"eml_dir" flag:"eml-dir" usage:"directory of .eml files or Maildir read by the mailbox source, selects the mailbox source if set"`
+	CheckpointFile string `yaml:"checkpoint_file" flag:"checkpoint_file" usage:"file of the checkpoints of the sources, where -incremental resumes reading their emails"`
+}
+
+// Built-in email sources of Ingest.Source, the names the source package registers them with
+const (
+	SourceAppScript = "appscript"
+	SourceGmail     = "gmail"
+	SourceImap      = "imap"
+	SourceMailbox   = "mailbox"
+)
+
+// Google configures the Google Apps Script or Gmail api reading the Gmail inbox
+type Google struct {
+	CredentialsFile       string `yaml:"credentials_file" flag:"google_credentials" usage:"OAuth client credentials file of the Google Apps Script"`
+	TokenFile             string `yaml:"token_file" flag:"google_token" usage:"file caching the OAuth token of the Google account"`
+	OAuthRedirectPort     int    `yaml:"oauth_redirect_port" flag:"oauth_redirect_port" usage:"local port receiving the OAuth redirect"`
+	AppScriptDeploymentID string `yaml:"app_script_deployment_id" flag:"app_script_deployment_id" env:"APP_SCRIPT_DEPLOYMENT_ID" usage:"deployment id of the Google Apps Script"`
+	GmailQuery            string `yaml:"gmail_query" flag:"gmail_query" usage:"Gmail search query of the job application emails read with the gmail source, a built-in query if empty"`
+}
+
+// Imap configures the IMAP mailbox read by the imap source, e.g. of Outlook or Fastmail
+type Imap struct {
+	Addr     string `yaml:"addr" flag:"imap_addr" usage:"host:port of the IMAP server, e.g. outlook.office365.com:993"`
+	User     string `yaml:"user" flag:"imap_user" usage:"user of the IMAP mailbox, usually its email address"`
+	Password string `yaml:"password" flag:"imap_password" env:"IMAP_PASSWORD" usage:"password of the IMAP user, e.g. an app password"`
+	Token    string `yaml:"token" flag:"imap_token" env:"IMAP_OAUTH_TOKEN" usage:"OAuth2 access token of the IMAP user, authenticating with XOAUTH2 instead of the password"`
+	Folder   string `yaml:"folder" flag:"imap_folder" usage:"folder of the job application emails"`
+	TLS      bool   `yaml:"tls" flag:"imap_tls" usage:"connect over TLS, otherwise upgrade the connection with STARTTLS"`
+
+	AllowPlaintext bool `yaml:"allow_plaintext" flag:"imap_allow_plaintext" usage:"without TLS, send the credentials in clear if the server does not support STARTTLS, e.g. a local server"`
+}
+
+// LLM configures the model analyzing the emails
+type LLM struct {
+	Provider    string        `yaml:"provider" flag:"llm_provider" usage:"LLM provider analyzing the emails, one of: openai, anthropic, ollama, fake"`
+	Model       string        `yaml:"model" flag:"llm_model" usage:"model of the LLM provider, the provider default if empty"`
+	BaseURL     string        `yaml:"base_url" flag:"llm_base_url" usage:"url of the LLM provider api, e.g. of an OpenAI compatible server, the provider default if empty"`
+	Timeout     time.Duration `yaml:"timeout" flag:"llm_timeout" usage:"timeout of analyzing one email"`
+	Concurrency int           `yaml:"concurrency" flag:"llm_concurrency" usage:"maximum number of emails analyzed at the same time"`
+	Retries     int           `yaml:"retries" flag:"llm_retries" usage:"number of times a failed LLM call is retried"`
+}
+
+// Rules configures the rules classifying and cleaning up the analyzed emails
+type Rules struct {
+	InvalidCompanyWords []string `yaml:"invalid_company_words" flag:"invalid_company_words" usage:"comma separated words of company names that were not extracted correctly"`
+	NoReplyPrefixes     []string `yaml:"no_reply_prefixes" flag:"no_reply_prefixes" usage:"comma separated prefixes of automated sender addresses, followed by the company domain"`
+	ATSRules            string   `yaml:"ats_rules" flag:"ats_rules" usage:"YAML file of the rules classifying the emails of the applicant tracking systems, the built-in rules if empty"`
+	MinConfidence       float64  `yaml:"min_confidence" flag:"rules_min_confidence" usage:"confidence from 0 to 1 of the rules below which emails are analyzed by the LLM"`
+}
+
+// Telemetry configures the metrics and traces of the binaries
+type Telemetry struct {
+	OTLPEndpoint string `yaml:"otlp_endpoint" flag:"otlp_endpoint" usage:"OTLP/HTTP url receiving the traces, e.g. http://localhost:4318/v1/traces, disabled if empty"`
+	MetricsFile  string `yaml:"metrics_file" flag:"metrics_file" usage:"file the prometheus metrics are written to on exit, e.g. for the node exporter textfile collector"`
+}
+
+// Log configures the logs of the binaries
+type Log struct {
+	Level  string `yaml:"level" flag:"log_level" usage:"minimum level of the logs, one of: debug, info, warn, error"`
+	Format string `yaml:"format" flag:"log_format" usage:"output format of the logs, one of: text, json"`
+	Debug  bool   `yaml:"debug" flag:"log_debug" usage:"log email bodies, sender addresses and applicant names instead of redacting them"`
+}
+
+// NewLogger returns the logger of the settings writing to w, which redacts the applicant names of
+// $APPLICANT_FIRST_NAME and $APPLICANT_LAST_NAME unless Debug is set
+func (l Log) NewLogger(w io.Writer) (*slog.Logger, error) {
+	level, err := logging.ParseLevel(l.Level)
+	if err != nil {
+		return nil, err
+	}
+	return logging.New(w,
+		logging.WithLevel(level),
+		logging.WithFormat(l.Format),
+		logging.WithDebug(l.Debug),
+		logging.WithApplicantNames(os.Getenv("APPLICANT_FIRST_NAME"), os.Getenv("APPLICANT_LAST_NAME")))
+}
+
+// LLM providers of LLM.Provider, the names the analyzer package supports
+const (
+	LLMProviderOpenAI    = "openai"
+	LLMProviderAnthropic = "anthropic"
+	LLMProviderOllama    = "ollama"
+	LLMProviderFake      = "fake"
+)
+
+// LLMProviders are the supported values of LLM.Provider
+var LLMProviders = []string{LLMProviderOpenAI, LLMProviderAnthropic, LLMProviderOllama, LLMProviderFake}
+
+// Default returns the configuration used when nothing is set
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Addr:            ":9000",
+			HTTPAddr:        ":8080",
+			Dashboard:       true,
+			JWTTenantClaim:  "sub",
+			HealthInterval:  10 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			MetricsAddr:     "localhost:9464",
+		},
+		Ghosted: Ghosted{
+			AfterDays: 30,
+			Interval:  24 * time.Hour,
+		},
+		Ingest: Ingest{
+			Source:         SourceAppScript,
+			CheckpointFile: "ingest_checkpoint.json",
+		},
+		Google: Google{
+			CredentialsFile:   "configs/gcp_app_script_credentials.json",
+			TokenFile:         "configs/gcp_oauth_token.json",
+			OAuthRedirectPort: 8085,
+		},
+		Imap: Imap{
+			Folder: "INBOX",
+			TLS:    true,
+		},
+		LLM: LLM{
+			Provider:    LLMProviderOpenAI,
+			Timeout:     15 * time.Second,
+			Concurrency: 5,
+			Retries:     2,
+		},
+		Rules: Rules{
+			InvalidCompanyWords: slices.Clone(gcp.InvalidCompanyWords),
+			NoReplyPrefixes:     slices.Clone(gcp.NoReplyPrefixes),
+			MinConfidence:       0.7,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: logging.FormatText,
+		},
+	}
+}
+
+// stringList is a flag of comma separated values
+type stringList struct {
+	values *[]string
+}
+
+func (l stringList) String() string {
+	if l.values == nil {
+		return ""
+	}
+	return strings.Join(*l.values, ",")
+}
+
+func (l stringList) Set(s string) error {
+	values := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	*l.values = values
+	return nil
+}
+
+// setting is a field of the configuration with a flag
+type setting struct {
+	// section of the setting, empty at the top level
+	section string
+	flag    string
+	env     string
+	usage   string
+	value   reflect.Value
+}
+
+// settings returns the settings of the sections, and those of the top level
+func (c *Config) settings(sections ...string) []setting {
+	var res []setting
+	var walk func(v reflect.Value, section string)
+	walk = func(v reflect.Value, section string) {
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if field.Type.Kind() == reflect.Struct {
+				if name := field.Tag.Get("yaml"); section == "" && slices.Contains(sections, name) {
+					walk(v.Field(i), name)
+				}
+				continue
+			}
+			name := field.Tag.Get("flag")
+			env := field.Tag.Get("env")
+			if env == "" {
+				env = EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+			}
+			res = append(res, setting{section: section, flag: name, env: env, usage: field.Tag.Get("usage"), value: v.Field(i)})
+		}
+	}
+	walk(reflect.ValueOf(c).Elem(), "")
+	return res
+}
+
+// Register adds the flags of the settings in the sections to fs, and the -config flag. The flags are
+// applied to the configuration by Load.
+func Register(fs *flag.FlagSet, c *Config, sections ...string) {
+	fs.String("config", "", "YAML configuration file, $"+EnvPrefix+"CONFIG if empty")
+	for _, s := range c.settings(sections...) {
+		usage := fmt.Sprintf("%s ($%s)", s.usage, s.env)
+		switch p := s.value.Addr().Interface().(type) {
+		case *string:
+			fs.StringVar(p, s.flag, *p, usage)
+		case *int:
+			fs.IntVar(p, s.flag, *p, usage)
+		case *float64:
+			fs.Float64Var(p, s.flag, *p, usage)
+		case *bool:
+			fs.BoolVar(p, s.flag, *p, usage)
+		case *time.Duration:
+			fs.DurationVar(p, s.flag, *p, usage)
+		case *[]string:
+			fs.Var(stringList{p}, s.flag, usage)
+		default:
+			panic(fmt.Sprintf("unsupported type %T of setting %s", p, s.flag))
+		}
+	}
+}
+
+// Load sets the configuration registered on the parsed fs: the defaults are overridden by the
+// configuration file, then by the environment variables and then by the flags set on the command
+// line. The env file is loaded before reading the environment variables. The sections registered on
+// fs are validated once loaded.
+func Load(fs *flag.FlagSet, c *Config) error {
+	// flags are applied last, but they were parsed into c already
+	set := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = f.Value.String()
+	})
+	*c = *Default()
+
+	path, ok := set["config"]
+	if !ok {
+		path = os.Getenv(EnvPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := c.readFile(path); err != nil {
+			return err
+		}
+	}
+
+	envFile := c.EnvFile
+	if v, ok := os.LookupEnv(EnvPrefix + "ENV_FILE"); ok {
+		envFile = v
+	}
+	if v, ok := set["env_file"]; ok {
+		envFile = v
+	}
+	if envFile != "" {
+		if err := godotenv.Load(envFile); err != nil {
+			return fmt.Errorf("error loading env file %s, error: %w", envFile, err)
+		}
+	}
+
+	registered := []string{}
+	for _, s := range c.settings(sections...) {
+		if fs.Lookup(s.flag) == nil {
+			continue
+		}
+		if s.section != "" && !slices.Contains(registered, s.section) {
+			registered = append(registered, s.section)
+		}
+		if v, ok := os.LookupEnv(s.env); ok {
+			if err := fs.Set(s.flag, v); err != nil {
+				return fmt.Errorf("invalid %s, error: %w", s.env, err)
+			}
+		}
+	}
+
+	for name, v := range set {
+		if err := fs.Set(name, v); err != nil {
+			return fmt.Errorf("invalid -%s, error: %w", name, err)
+		}
+	}
+
+	// the mailbox files are read instead of the configured source
+	if c.Ingest.Mbox != "" || c.Ingest.EmlDir != "" {
+		c.Ingest.Source = SourceMailbox
+	}
+
+	return c.Validate(registered...)
+}
+
+func (c *Config) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid configuration file %s, error: %w", path, err)
+	}
+	return nil
+}
+
+func validAddr(name, addr string) error {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return fmt.Errorf("invalid %s %q, expecting host:port", name, addr)
+	}
+	return nil
+}
+
+// Validate returns the errors of the settings of the sections that can't be used, the other sections
+// are not used by the binary
+func (c *Config) Validate(sections ...string) error {
+	errs := []error{}
+	validates := func(section string) bool {
+		return slices.Contains(sections, section)
+	}
+
+	if validates(SectionServer) {
+		if err := validAddr("server.addr", c.Server.Addr); err != nil {
+			errs = append(errs, err)
+		}
+		if c.Server.HTTPAddr != "" {
+			if err := validAddr("server.http_addr", c.Server.HTTPAddr); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if c.Server.MetricsAddr != "" {
+			if err := validAddr("server.metrics_addr", c.Server.MetricsAddr); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if (c.Server.TLSCert == "") != (c.Server.TLSKey == "") {
+			errs = append(errs, fmt.Errorf("server.tls_cert and server.tls_key must be set together"))
+		}
+		if c.Server.TLSClientCA != "" && c.Server.TLSCert == "" {
+			errs = append(errs, fmt.Errorf("server.tls_client_ca requires server.tls_cert"))
+		}
+		if c.Server.JWKS != "" && c.Server.JWTTenantClaim == "" {
+			errs = append(errs, fmt.Errorf("server.jwt_tenant_claim is required with server.jwks"))
+		}
+		if c.Server.HealthInterval <= 0 {
+			errs = append(errs, fmt.Errorf("invalid server.health_interval %s, expecting a positive duration", c.Server.HealthInterval))
+		}
+		if c.Server.ShutdownTimeout <= 0 {
+			errs = append(errs, fmt.Errorf("invalid server.shutdown_timeout %s, expecting a positive duration", c.Server.ShutdownTimeout))
+		}
+	}
+
+	if validates(SectionStorage) && c.Storage.DSN != "" && c.Storage.Snapshot != "" {
+		errs = append(errs, fmt.Errorf("storage.snapshot is only used without storage.dsn"))
+	}
+
+	if validates(SectionGhosted) {
+		if c.Ghosted.AfterDays < 0 {
+			errs = append(errs, fmt.Errorf("invalid ghosted.after_days %d, expecting 0 to disable or more", c.Ghosted.AfterDays))
+		}
+		if c.Ghosted.Interval <= 0 {
+			errs = append(errs, fmt.Errorf("invalid ghosted.interval %s, expecting a positive duration", c.Ghosted.Interval))
+		}
+	}
+
+	if validates(SectionGoogle) && (c.Google.OAuthRedirectPort < 1 || c.Google.OAuthRedirectPort > 65535) {
+		errs = append(errs, fmt.Errorf("invalid google.oauth_redirect_port %d", c.Google.OAuthRedirectPort))
+	}
+
+	if validates(SectionLLM) {
+		if !slices.Contains(LLMProviders, c.LLM.Provider) {
+			errs = append(errs, fmt.Errorf("invalid llm.provider %q, expecting one of: %s", c.LLM.Provider, strings.Join(LLMProviders, ", ")))
+		}
+		if c.LLM.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("invalid llm.timeout %s, expecting a positive duration", c.LLM.Timeout))
+		}
+		if c.LLM.Concurrency < 1 {
+			errs = append(errs, fmt.Errorf("invalid llm.concurrency %d, expecting at least 1", c.LLM.Concurrency))
+		}
+		if c.LLM.Retries < 0 {
+			errs = append(errs, fmt.Errorf("invalid llm.retries %d, expecting 0 or more", c.LLM.Retries))
+		}
+	}
+
+	if validates(SectionIngest) {
+		if c.Ingest.Source == SourceImap {
+			if err := validAddr("imap.addr", c.Imap.Addr); err != nil {
+				errs = append(errs, err)
+			}
+			if c.Imap.User == "" || (c.Imap.Password == "" && c.Imap.Token == "") {
+				errs = append(errs, errors.New("missing imap.user, and imap.password or imap.token"))
+			}
+		}
+		if c.Ingest.Source == SourceMailbox && c.Ingest.Mbox == "" && c.Ingest.EmlDir == "" {
+			errs = append(errs, errors.New("missing ingest.mbox or ingest.eml_dir"))
+		}
+		if c.Ingest.CheckpointFile == "" {
+			errs = append(errs, errors.New("missing ingest.checkpoint_file"))
+		}
+	}
+
+	if validates(SectionRules) && (c.Rules.MinConfidence < 0 || c.Rules.MinConfidence > 1) {
+		errs = append(errs, fmt.Errorf("invalid rules.min_confidence %g, expecting 0 to 1", c.Rules.MinConfidence))
+	}
+
+	if validates(SectionTelemetry) && c.Telemetry.OTLPEndpoint != "" {
+		if u, err := url.Parse(c.Telemetry.OTLPEndpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid telemetry.otlp_endpoint %q, expecting an http or https url", c.Telemetry.OTLPEndpoint))
+		}
+	}
+
+	if validates(SectionLog) {
+		if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+			errs = append(errs, fmt.Errorf("invalid log.level %q, expecting one of: debug, info, warn, error", c.Log.Level))
+		}
+		if !slices.Contains(logging.Formats, c.Log.Format) {
+			errs = append(errs, fmt.Errorf("invalid log.format %q, expecting one of: %s", c.Log.Format, strings.Join(logging.Formats, ", ")))
+		}
+	}
+
+	return errors.Join(errs...)
+}
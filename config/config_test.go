@@ -0,0 +1,158 @@
+package config
+
+import (
+	"flag"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func load(t *testing.T, args ...string) (*Config, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := Default()
+	Register(fs, c, SectionServer, SectionStorage, SectionGhosted, SectionIngest, SectionLLM, SectionRules, SectionLog)
+	require.NoError(t, fs.Parse(args))
+	return c, Load(fs, c)
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	c, err := load(t)
+	require.NoError(t, err)
+	assert.Equal(t, Default(), c)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "maxhire.yaml", `
+server:
+  addr: ":9001"
+  http_addr: ":8081"
+  cors_origins: [https://a.example.com, https://b.example.com]
+storage:
+  dsn: file.db
+ghosted:
+  after_days: 10
+  interval: 1h
+llm:
+  concurrency: 2
+`)
+	t.Setenv("MAXHIRE_HTTP", ":8082")
+	t.Setenv("MAXHIRE_DB", "env.db")
+	t.Setenv("MAXHIRE_DASHBOARD", "false")
+	t.Setenv("MAXHIRE_LLM_CONCURRENCY", "3")
+
+	c, err := load(t, "-config", path, "-db", "flag.db", "-llm_concurrency", "4")
+	require.NoError(t, err)
+
+	assert.Equal(t, ":9001", c.Server.Addr)
+	assert.Equal(t, ":8082", c.Server.HTTPAddr)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, c.Server.CORSOrigins)
+	assert.False(t, c.Server.Dashboard)
+	assert.Equal(t, "flag.db", c.Storage.DSN)
+	assert.Equal(t, 10, c.Ghosted.AfterDays)
+	assert.Equal(t, time.Hour, c.Ghosted.Interval)
+	assert.Equal(t, 4, c.LLM.Concurrency)
+	// not registered
+	assert.Equal(t, Default().Google, c.Google)
+}
+
+func TestLoad_EnvFile(t *testing.T) {
+	envFile := writeFile(t, ".env", "MAXHIRE_ADDR=:9002\nMAXHIRE_HTTP=:8083\n")
+	path := writeFile(t, "maxhire.yaml", "env_file: "+envFile+"\n")
+	t.Setenv("MAXHIRE_CONFIG", path)
+	t.Setenv("MAXHIRE_HTTP", ":8084")
+	t.Cleanup(func() { os.Unsetenv("MAXHIRE_ADDR") })
+
+	c, err := load(t)
+	require.NoError(t, err)
+	assert.Equal(t, ":9002", c.Server.Addr)
+	// set variables are kept
+	assert.Equal(t, ":8084", c.Server.HTTPAddr)
+
+	_, err = load(t, "-env_file", filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		file string
+		args []string
+		env  map[string]string
+	}{
+		"unknown setting":  {file: "server:\n  port: 9000\n"},
+		"invalid yaml":     {file: "server: [\n"},
+		"invalid address":  {args: []string{"-addr", "9000"}},
+		"metrics address":  {args: []string{"-metrics_addr", "9464"}},
+		"tls key missing":  {args: []string{"-tls_cert", "cert.pem"}},
+		"invalid env":      {env: map[string]string{"MAXHIRE_GHOSTED_AFTER_DAYS": "ten"}},
+		"negative days":    {args: []string{"-ghosted_after_days", "-1"}},
+		"unknown provider": {file: "llm:\n  provider: other\n"},
+		"no concurrency":   {args: []string{"-llm_concurrency", "0"}},
+		"min confidence":   {args: []string{"-rules_min_confidence", "1.5"}},
+		"no mailbox files": {file: "ingest:\n  source: mailbox\n"},
+		"log level":        {args: []string{"-log_level", "verbose"}},
+		"log format":       {env: map[string]string{"MAXHIRE_LOG_FORMAT": "xml"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			args := tc.args
+			if tc.file != "" {
+				args = append([]string{"-config", writeFile(t, "maxhire.yaml", tc.file)}, args...)
+			}
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			_, err := load(t, args...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoad_UnregisteredSections(t *testing.T) {
+	// the settings of the other binary are not validated
+	path := writeFile(t, "maxhire.yaml", "llm:\n  provider: other\ningest:\n  source: mailbox\n")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := Default()
+	Register(fs, c, SectionServer, SectionLog)
+	require.NoError(t, fs.Parse([]string{"-config", path}))
+	require.NoError(t, Load(fs, c))
+	assert.Equal(t, "other", c.LLM.Provider)
+
+	assert.Error(t, c.Validate(SectionLLM))
+
+	// the sources are registered in the source package
+	c, err := load(t, "-source", "fake")
+	require.NoError(t, err)
+	assert.Equal(t, "fake", c.Ingest.Source)
+}
+
+func TestDefault_Ports(t *testing.T) {
+	// the OAuth redirect of ingest can run next to the server
+	c := Default()
+	_, port, err := net.SplitHostPort(c.Server.HTTPAddr)
+	require.NoError(t, err)
+	assert.NotEqual(t, port, strconv.Itoa(c.Google.OAuthRedirectPort))
+}
+
+func TestLoad_MailboxEnv(t *testing.T) {
+	t.Setenv("MAXHIRE_MBOX", "mail.mbox")
+	t.Setenv("MAXHIRE_EML_DIR", "mail")
+
+	c, err := load(t)
+	require.NoError(t, err)
+	assert.Equal(t, "mail.mbox", c.Ingest.Mbox)
+	assert.Equal(t, "mail", c.Ingest.EmlDir)
+	assert.Equal(t, SourceMailbox, c.Ingest.Source)
+}
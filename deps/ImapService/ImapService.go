@@ -0,0 +1,320 @@
+// Package ImapService reads the job application emails of any IMAP mailbox, e.g. Outlook or Fastmail.
+package ImapService
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	"github.com/emersion/go-sasl"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/deps/mailtext"
+	"github.com/MaxBear/maxhire/source"
+)
+
+// Source is the name of the source of the emails
+const Source = "imap"
+
+// DefaultFolder is the folder searched by default
+const DefaultFolder = "INBOX"
+
+var tracer = otel.Tracer("github.com/MaxBear/maxhire/deps/ImapService")
+
+type ImapService struct {
+	ctx           context.Context
+	withAddr      string
+	withUser      string
+	withPassword  string
+	withToken     string
+	withFolder    string
+	withTLS       bool
+	withPlaintext bool
+	withTLSConfig *tls.Config
+	withTimeout   time.Duration
+	logger        *slog.Logger
+}
+
+type ImapServiceOpt func(*ImapService)
+
+// WithAddr sets the host:port of the server, e.g. outlook.office365.com:993
+func WithAddr(addr string) ImapServiceOpt {
+	return func(s *ImapService) {
+		s.withAddr = addr
+	}
+}
+
+// WithLogin authenticates with the password of the user, e.g. an app password
+func WithLogin(user, password string) ImapServiceOpt {
+	return func(s *ImapService) {
+		s.withUser = user
+		s.withPassword = password
+	}
+}
+
+// WithXOAuth2 authenticates with the OAuth2 access token of the user, instead of a password
+func WithXOAuth2(user, token string) ImapServiceOpt {
+	return func(s *ImapService) {
+		s.withUser = user
+		s.withToken = token
+	}
+}
+
+// WithFolder sets the folder searched, defaults to DefaultFolder
+func WithFolder(folder string) ImapServiceOpt {
+	return func(s *ImapService) {
+		s.withFolder = folder
+	}
+}
+
+// WithTLS connects over TLS if enabled, the default, otherwise upgrades the connection with STARTTLS if
+// the server supports it
+func WithTLS(enabled bool) ImapServiceOpt {
+	return func(s *ImapService) {
+		s.withTLS = enabled
+	}
+}
+
+// WithAllowPlaintext allows sending the credentials in clear when TLS is disabled and the server does
+// not support STARTTLS, e.g. to a local server, otherwise the connection fails
+func WithAllowPlaintext(allowed bool) ImapServiceOpt {
+	return func(s *ImapService) {
+		s.withPlaintext = allowed
+	}
+}
+
+// WithTLSConfig sets the TLS configuration, e.g. the CAs of the server certificate
+func WithTLSConfig(config *tls.Config) ImapServiceOpt {
+	return func(s *ImapService) {
+		s.withTLSConfig = config
+	}
+}
+
+// WithTimeout sets the timeout of the connection and of each command, defaults to 1 minute
+func WithTimeout(timeout time.Duration) ImapServiceOpt {
+	return func(s *ImapService) {
+		s.withTimeout = timeout
+	}
+}
+
+// WithLogger sets the logger of the service, defaults to slog.Default()
+func WithLogger(logger *slog.Logger) ImapServiceOpt {
+	return func(s *ImapService) {
+		s.logger = logger
+	}
+}
+
+func New(ctx context.Context, opts ...ImapServiceOpt) (*ImapService, error) {
+	s := &ImapService{
+		ctx:         ctx,
+		withFolder:  DefaultFolder,
+		withTLS:     true,
+		withTimeout: time.Minute,
+		logger:      slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if _, _, err := net.SplitHostPort(s.withAddr); err != nil {
+		return nil, fmt.Errorf("invalid imap address %q, expecting host:port", s.withAddr)
+	}
+	if s.withUser == "" || (s.withPassword == "" && s.withToken == "") {
+		return nil, errors.New("missing imap user, and password or token")
+	}
+
+	return s, nil
+}
+
+// Name returns the name of the source
+func (s *ImapService) Name() string {
+	return Source
+}
+
+// ID identifies the mailbox read, by its user, server and folder
+func (s *ImapService) ID() string {
+	return s.withUser + "@" + s.withAddr + "/" + s.withFolder
+}
+
+// GetApplicationEmails returns the emails of the folder received from start_date to end_date included,
+// formatted as 2006-01-02, either can be empty
+func (s *ImapService) GetApplicationEmails(start_date, end_date string) (models.RawEmailRecords, error) {
+	start, end, err := models.DateRange(start_date, end_date)
+	if err != nil {
+		return models.RawEmailRecords{}, err
+	}
+	return models.CollectRecords(s.Emails(s.ctx, start, end))
+}
+
+// Emails returns the emails of the folder sent from start to end excluded, either is unbounded if zero, as
+// they are fetched. The emails without Message-ID are identified by their folder and uid.
+func (s *ImapService) Emails(ctx context.Context, start, end time.Time) iter.Seq2[*models.RawEmailRecord, error] {
+	return source.Stream(ctx, Source, start, end, func(ctx context.Context, add func(*models.RawEmailRecord) bool) error {
+		return s.emails(ctx, start, end, add)
+	}, attribute.String("folder", s.withFolder))
+}
+
+// criteria returns the search criteria of the emails received the days of start to end
+func criteria(start, end time.Time) *imap.SearchCriteria {
+	c := imap.NewSearchCriteria()
+	// the server searches by day, before is exclusive
+	c.Since, c.Before = models.DayRange(start, end)
+	return c
+}
+
+// connect returns the client logged in the server
+func (s *ImapService) connect(ctx context.Context) (*client.Client, error) {
+	tlsConfig := s.withTLSConfig
+	if tlsConfig == nil {
+		host, _, _ := net.SplitHostPort(s.withAddr)
+		tlsConfig = &tls.Config{ServerName: host}
+	}
+
+	dialer := &net.Dialer{Timeout: s.withTimeout}
+	var (
+		c   *client.Client
+		err error
+	)
+	if s.withTLS {
+		c, err = client.DialWithDialerTLS(dialer, s.withAddr, tlsConfig)
+	} else {
+		c, err = client.DialWithDialer(dialer, s.withAddr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Timeout = s.withTimeout
+
+	// the connection is closed when ctx is canceled, failing the command in progress
+	stop := context.AfterFunc(ctx, func() { c.Terminate() })
+	go func() {
+		<-c.LoggedOut()
+		stop()
+	}()
+
+	if !s.withTLS {
+		ok, err := c.SupportStartTLS()
+		if err != nil {
+			c.Logout()
+			return nil, err
+		}
+		switch {
+		case ok:
+			if err := c.StartTLS(tlsConfig); err != nil {
+				c.Logout()
+				return nil, err
+			}
+		case s.withPlaintext:
+			s.logger.WarnContext(ctx, "imap server does not support STARTTLS, the credentials are sent in clear", "addr", s.withAddr)
+		default:
+			c.Logout()
+			return nil, fmt.Errorf("imap server %s does not support STARTTLS, not sending the credentials in clear", s.withAddr)
+		}
+	}
+
+	if s.withToken != "" {
+		err = c.Authenticate(newXOAuth2Client(s.withUser, s.withToken))
+	} else {
+		err = c.Login(s.withUser, s.withPassword)
+	}
+	if err != nil {
+		c.Logout()
+		return nil, fmt.Errorf("imap authentication failed: %w", err)
+	}
+
+	return c, nil
+}
+
+// emails passes the emails of the folder to add until it returns false
+func (s *ImapService) emails(ctx context.Context, start, end time.Time, add func(*models.RawEmailRecord) bool) error {
+	c, err := s.connect(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "unable to connect to imap server", "addr", s.withAddr, "error", err)
+		return err
+	}
+	defer c.Logout()
+
+	if _, err := c.Select(s.withFolder, true); err != nil {
+		s.logger.ErrorContext(ctx, "unable to select imap folder", "folder", s.withFolder, "error", err)
+		return err
+	}
+
+	uids, err := c.UidSearch(criteria(start, end))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "unable to search imap folder", "folder", s.withFolder, "error", err)
+		return err
+	}
+	s.logger.DebugContext(ctx, "searched imap folder", "folder", s.withFolder, "messages", len(uids))
+	if len(uids) == 0 {
+		return nil
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uids...)
+	section := &imap.BodySectionName{Peek: true}
+	messages := make(chan *imap.Message, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.UidFetch(seqset, []imap.FetchItem{imap.FetchUid, imap.FetchInternalDate, section.FetchItem()}, messages)
+	}()
+
+	stopped := false
+	for msg := range messages {
+		if stopped {
+			// drains the messages fetched until the connection is closed
+			continue
+		}
+		body := msg.GetBody(section)
+		if body == nil {
+			s.logger.WarnContext(ctx, "imap message without body", "uid", msg.Uid)
+			continue
+		}
+		record, err := mailtext.Parse(body, msg.InternalDate)
+		if err != nil {
+			s.logger.WarnContext(ctx, "unable to parse imap message", "uid", msg.Uid, "error", err)
+			continue
+		}
+		if record.MessageId == "" {
+			record.MessageId = fmt.Sprintf("%s/%d", s.withFolder, msg.Uid)
+		}
+		if !add(record) {
+			// the fetch of the other messages is aborted
+			stopped = true
+			c.Terminate()
+		}
+	}
+	if err := <-done; err != nil && !stopped {
+		s.logger.ErrorContext(ctx, "unable to fetch imap messages", "folder", s.withFolder, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// xoauth2Client is the sasl.Client of the XOAUTH2 mechanism of Gmail and Outlook
+type xoauth2Client struct {
+	user, token string
+}
+
+func newXOAuth2Client(user, token string) sasl.Client {
+	return &xoauth2Client{user: user, token: token}
+}
+
+func (a *xoauth2Client) Start() (string, []byte, error) {
+	return "XOAUTH2", []byte("user=" + a.user + "\x01auth=Bearer " + a.token + "\x01\x01"), nil
+}
+
+// Next answers the error challenge with an empty response, the server then fails the authentication
+func (a *xoauth2Client) Next(challenge []byte) ([]byte, error) {
+	return []byte{}, nil
+}
@@ -0,0 +1,225 @@
+package ImapService
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/server"
+	"github.com/emersion/go-sasl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selfSigned returns the server certificate of 127.0.0.1 and the pool trusting it
+func selfSigned(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "imap"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+// xoauth2Server accepts the token "secret" of the user "username" of the memory backend
+type xoauth2Server struct {
+	be   *memory.Backend
+	conn server.Conn
+}
+
+func (a *xoauth2Server) Next(response []byte) ([]byte, bool, error) {
+	if string(response) != "user=username\x01auth=Bearer secret\x01\x01" {
+		return nil, true, errors.New("invalid token")
+	}
+	user, err := a.be.Login(a.conn.Info(), "username", "password")
+	if err != nil {
+		return nil, true, err
+	}
+	a.conn.Context().State = imap.AuthenticatedState
+	a.conn.Context().User = user
+	return nil, true, nil
+}
+
+type message struct {
+	received time.Time
+	body     string
+}
+
+// newServer returns an IMAP server with the messages in the inbox of the memory backend user "username",
+// and the pool trusting its certificate
+func newServer(t *testing.T, messages []message) (*server.Server, *x509.CertPool) {
+	t.Helper()
+	be := memory.New()
+	user, err := be.Login(nil, "username", "password")
+	require.NoError(t, err)
+	mbox, err := user.GetMailbox("INBOX")
+	require.NoError(t, err)
+	inbox := mbox.(*memory.Mailbox)
+	// the sample message of the backend is received when it starts
+	inbox.Messages = nil
+	for _, m := range messages {
+		require.NoError(t, inbox.CreateMessage(nil, m.received, strings.NewReader(strings.ReplaceAll(m.body, "\n", "\r\n"))))
+	}
+
+	cert, pool := selfSigned(t)
+	s := server.New(be)
+	s.ErrorLog = log.New(io.Discard, "", 0)
+	s.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	s.EnableAuth("XOAUTH2", func(conn server.Conn) sasl.Server {
+		return &xoauth2Server{be: be, conn: conn}
+	})
+	return s, pool
+}
+
+// serve starts an in-process IMAP server over TLS, see newServer, and returns its address and the TLS
+// configuration of its clients
+func serve(t *testing.T, messages []message) (string, *tls.Config) {
+	t.Helper()
+	s, pool := newServer(t, messages)
+
+	l, err := tls.Listen("tcp", "127.0.0.1:0", s.TLSConfig)
+	require.NoError(t, err)
+	go s.Serve(l)
+	t.Cleanup(func() { s.Close() })
+
+	return l.Addr().String(), &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}
+}
+
+func TestGetApplicationEmails(t *testing.T) {
+	addr, tlsConfig := serve(t, []message{
+		{time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC), `From: Lyft <no-reply@us.greenhouse-mail.io>
+Subject: =?UTF-8?Q?Thank_you_for_applying_=E2=80=93_Lyft?=
+Date: Sun, 02 Mar 2025 09:00:00 +0000
+Message-ID: <lyft-1@greenhouse.io>
+Content-Type: multipart/alternative; boundary="b"
+
+--b
+Content-Type: text/plain; charset=iso-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+Thank you for your interest in Lyft! Caf=E9 included.
+--b
+Content-Type: text/html
+
+<p>ignored</p>
+--b--
+`},
+		{time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC), `From: jobs@acme.com
+Subject: Your application
+Date: Tue, 04 Mar 2025 09:00:00 +0000
+Content-Type: text/html; charset=utf-8
+Content-Transfer-Encoding: base64
+
+PHA+VW5mb3J0dW5hdGVseSw8L3A+PHA+d2Ugd2VudCB3aXRoIDxiPm90aGVyIGNhbmRpZGF0ZXM8L2I+LjwvcD4=
+`},
+		{time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC), `From: later@example.com
+Subject: Out of range
+
+Too late.
+`},
+	})
+
+	s, err := New(context.Background(), WithAddr(addr), WithLogin("username", "password"), WithTLSConfig(tlsConfig))
+	require.NoError(t, err)
+
+	emails, err := s.GetApplicationEmails("2025-03-01", "2025-03-04")
+	require.NoError(t, err)
+	require.Len(t, emails, 2)
+
+	assert.Equal(t, "Lyft <no-reply@us.greenhouse-mail.io>", emails[0].FullSender)
+	assert.Equal(t, "us.greenhouse-mail.io", emails[0].Domain)
+	assert.Equal(t, "Thank you for applying – Lyft", emails[0].Subject)
+	assert.Equal(t, "lyft-1@greenhouse.io", emails[0].MessageId)
+	assert.True(t, time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC).Equal(emails[0].SentTime))
+	assert.Equal(t, "Thank you for your interest in Lyft! Café included.", emails[0].Msg)
+
+	assert.Equal(t, "acme.com", emails[1].Domain)
+	assert.Equal(t, "Unfortunately,\n\nwe went with other candidates.", emails[1].Msg)
+	// without Message-ID, the id is the uid of the message in the folder
+	assert.Equal(t, "INBOX/2", emails[1].MessageId)
+
+	// the fetch is aborted once the emails are no longer iterated
+	n := 0
+	for _, err := range s.Emails(context.Background(), time.Time{}, time.Time{}) {
+		require.NoError(t, err)
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+
+	s, err = New(context.Background(), WithAddr(addr), WithXOAuth2("username", "secret"), WithTLSConfig(tlsConfig))
+	require.NoError(t, err)
+	emails, err = s.GetApplicationEmails("2025-03-03", "")
+	require.NoError(t, err)
+	assert.Len(t, emails, 2)
+
+	s, err = New(context.Background(), WithAddr(addr), WithXOAuth2("username", "expired"), WithTLSConfig(tlsConfig))
+	require.NoError(t, err)
+	_, err = s.GetApplicationEmails("", "")
+	assert.Error(t, err)
+
+	s, err = New(context.Background(), WithAddr(addr), WithLogin("username", "password"), WithTLSConfig(tlsConfig), WithFolder("Missing"))
+	require.NoError(t, err)
+	_, err = s.GetApplicationEmails("", "")
+	assert.Error(t, err)
+
+	// the server certificate is not trusted by default
+	s, err = New(context.Background(), WithAddr(addr), WithLogin("username", "password"), WithTimeout(5*time.Second))
+	require.NoError(t, err)
+	_, err = s.GetApplicationEmails("", "")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), WithAddr("localhost"), WithLogin("username", "password"))
+	assert.Error(t, err)
+	_, err = New(context.Background(), WithAddr(addr))
+	assert.Error(t, err)
+}
+
+func TestPlaintext(t *testing.T) {
+	// a server without TLS nor STARTTLS
+	srv, _ := newServer(t, []message{{time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC), "From: jobs@acme.com\nSubject: Your application\n\nThanks.\n"}})
+	srv.TLSConfig = nil
+	srv.AllowInsecureAuth = true
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+
+	s, err := New(context.Background(), WithAddr(l.Addr().String()), WithLogin("username", "password"), WithTLS(false))
+	require.NoError(t, err)
+	_, err = s.GetApplicationEmails("", "")
+	assert.ErrorContains(t, err, "does not support STARTTLS")
+
+	s, err = New(context.Background(), WithAddr(l.Addr().String()), WithLogin("username", "password"), WithTLS(false), WithAllowPlaintext(true))
+	require.NoError(t, err)
+	emails, err := s.GetApplicationEmails("", "")
+	require.NoError(t, err)
+	assert.Len(t, emails, 1)
+	assert.Equal(t, "username@"+l.Addr().String()+"/INBOX", s.ID())
+}
@@ -0,0 +1,279 @@
+// Package MailboxService reads the job application emails of exported mail archives: mbox files, e.g. of
+// Google Takeout, directories of .eml files and Maildir directories, without any cloud credentials.
+package MailboxService
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/deps/mailtext"
+	"github.com/MaxBear/maxhire/source"
+)
+
+// Source is the name of the source of the emails
+const Source = "mailbox"
+
+var tracer = otel.Tracer("github.com/MaxBear/maxhire/deps/MailboxService")
+
+type MailboxService struct {
+	ctx        context.Context
+	withMbox   []string
+	withEmlDir []string
+	logger     *slog.Logger
+}
+
+type MailboxServiceOpt func(*MailboxService)
+
+// WithMbox adds an mbox file
+func WithMbox(path string) MailboxServiceOpt {
+	return func(s *MailboxService) {
+		s.withMbox = append(s.withMbox, path)
+	}
+}
+
+// WithEmlDir adds a directory of .eml files or a Maildir, read recursively
+func WithEmlDir(dir string) MailboxServiceOpt {
+	return func(s *MailboxService) {
+		s.withEmlDir = append(s.withEmlDir, dir)
+	}
+}
+
+// WithLogger sets the logger of the service, defaults to slog.Default()
+func WithLogger(logger *slog.Logger) MailboxServiceOpt {
+	return func(s *MailboxService) {
+		s.logger = logger
+	}
+}
+
+func New(ctx context.Context, opts ...MailboxServiceOpt) (*MailboxService, error) {
+	s := &MailboxService{
+		ctx:    ctx,
+		logger: slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if len(s.withMbox) == 0 && len(s.withEmlDir) == 0 {
+		return nil, errors.New("missing mbox file or eml directory")
+	}
+
+	return s, nil
+}
+
+// Name returns the name of the source
+func (s *MailboxService) Name() string {
+	return Source
+}
+
+// ID identifies the files read, by their absolute paths
+func (s *MailboxService) ID() string {
+	paths := []string{}
+	for _, path := range append(slices.Clone(s.withMbox), s.withEmlDir...) {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		paths = append(paths, path)
+	}
+	return strings.Join(paths, ",")
+}
+
+// GetApplicationEmails returns the emails of the files sent from start_date to end_date included,
+// formatted as 2006-01-02, either can be empty
+func (s *MailboxService) GetApplicationEmails(start_date, end_date string) (models.RawEmailRecords, error) {
+	start, end, err := models.DateRange(start_date, end_date)
+	if err != nil {
+		return models.RawEmailRecords{}, err
+	}
+	return models.CollectRecords(s.Emails(s.ctx, start, end))
+}
+
+// Emails returns the emails of the files sent from start to end excluded, either is unbounded if zero, as
+// the files are read. The emails without Message-ID are identified by their file, and their index in the
+// mbox files.
+func (s *MailboxService) Emails(ctx context.Context, start, end time.Time) iter.Seq2[*models.RawEmailRecord, error] {
+	return source.Stream(ctx, Source, start, end, s.emails)
+}
+
+// errStop stops reading the files once the emails are no longer iterated
+var errStop = errors.New("stop")
+
+// emails passes the emails of the files to add until it returns false
+func (s *MailboxService) emails(ctx context.Context, add func(*models.RawEmailRecord) bool) error {
+	for _, path := range s.withMbox {
+		if err := s.readMbox(ctx, path, add); err != nil {
+			if err == errStop {
+				return nil
+			}
+			s.logger.ErrorContext(ctx, "unable to read mbox", "file", path, "error", err)
+			return err
+		}
+	}
+	for _, dir := range s.withEmlDir {
+		if err := s.readDir(ctx, dir, add); err != nil {
+			if err == errStop {
+				return nil
+			}
+			s.logger.ErrorContext(ctx, "unable to read eml directory", "dir", dir, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fromLayouts are the layouts of the date of the separator lines of the mbox files
+var fromLayouts = []string{
+	"Mon Jan _2 15:04:05 -0700 2006",
+	"Mon Jan _2 15:04:05 2006",
+	time.ANSIC,
+}
+
+// separatorDate returns the date of a "From sender date" separator line, zero if it has none
+func separatorDate(line string) time.Time {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return time.Time{}
+	}
+	date := strings.Join(fields[2:], " ")
+	for _, layout := range fromLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// readMbox parses the messages of an mbox file, separated by lines starting with "From ", whose body
+// lines starting with "From " are escaped with >
+func (s *MailboxService) readMbox(ctx context.Context, path string, add func(*models.RawEmailRecord) bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var (
+		msg      bytes.Buffer
+		received time.Time
+		n        int
+		started  bool
+	)
+	// flush returns false once add does
+	flush := func() bool {
+		if !started {
+			return true
+		}
+		defer msg.Reset()
+		n++
+		record, err := mailtext.Parse(&msg, received)
+		if err != nil {
+			s.logger.WarnContext(ctx, "unable to parse mbox message", "file", path, "index", n, "error", err)
+			return true
+		}
+		if record.MessageId == "" {
+			record.MessageId = fmt.Sprintf("%s#%d", filepath.Base(path), n)
+		}
+		return add(record)
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, err := r.ReadString('\n')
+		if len(line) > 0 {
+			switch {
+			case strings.HasPrefix(line, "From "):
+				if !flush() {
+					return errStop
+				}
+				started = true
+				received = separatorDate(strings.TrimRight(line, "\r\n"))
+			case started:
+				// mboxrd escapes every >*From line, unescaped for mboxo too
+				if trimmed := strings.TrimLeft(line, ">"); len(trimmed) < len(line) && strings.HasPrefix(trimmed, "From ") {
+					line = line[1:]
+				}
+				msg.WriteString(line)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !flush() {
+		return errStop
+	}
+
+	return nil
+}
+
+// maildir tells whether path is a message of a Maildir, in its cur or new directory
+func maildir(path string) bool {
+	dir := filepath.Dir(path)
+	switch filepath.Base(dir) {
+	case "cur", "new":
+		_, err := os.Stat(filepath.Join(filepath.Dir(dir), "tmp"))
+		return err == nil
+	}
+	return false
+}
+
+// readDir parses the .eml files and Maildir messages of dir and its sub directories
+func (s *MailboxService) readDir(ctx context.Context, dir string, add func(*models.RawEmailRecord) bool) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || (!strings.EqualFold(filepath.Ext(path), ".eml") && !maildir(path)) {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		record, err := mailtext.Parse(f, info.ModTime())
+		if err != nil {
+			s.logger.WarnContext(ctx, "unable to parse email file", "file", path, "error", err)
+			return nil
+		}
+		if record.MessageId == "" {
+			record.MessageId, _ = filepath.Rel(dir, path)
+		}
+		if !add(record) {
+			return errStop
+		}
+		return nil
+	})
+}
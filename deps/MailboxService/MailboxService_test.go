@@ -0,0 +1,136 @@
+package MailboxService
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mbox = `From 1826483520384 Sun Mar 02 09:00:00 +0000 2025
+X-GM-THRID: 1826483520384
+From: Lyft <no-reply@us.greenhouse-mail.io>
+Subject: =?UTF-8?Q?Thank_you_for_applying_=E2=80=93_Lyft?=
+Date: Sun, 02 Mar 2025 09:00:00 +0000
+Message-ID: <lyft-1@greenhouse.io>
+Content-Type: multipart/alternative; boundary="b"
+
+--b
+Content-Type: text/plain; charset=iso-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+Thank you for your interest in Lyft! Caf=E9 included.
+>From now on, we will review your application.
+--b
+Content-Type: text/html
+
+<p>ignored</p>
+--b--
+
+From jobs@acme.com Tue Mar 04 09:00:00 2025
+From: jobs@acme.com
+Subject: Your application
+Date: Tue, 04 Mar 2025 09:00:00 +0000
+Content-Type: text/html; charset=utf-8
+Content-Transfer-Encoding: base64
+
+PHA+VW5mb3J0dW5hdGVseSw8L3A+PHA+d2Ugd2VudCB3aXRoIDxiPm90aGVyIGNhbmRpZGF0ZXM8L2I+LjwvcD4=
+
+From later@example.com Tue Apr 01 09:00:00 2025
+From: later@example.com
+Subject: Out of range
+Date: Tue, 01 Apr 2025 09:00:00 +0000
+
+Too late.
+`
+
+func write(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(strings.ReplaceAll(content, "\n", "\r\n")), 0o644))
+}
+
+func TestMbox(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "takeout.mbox")
+	write(t, path, mbox)
+
+	s, err := New(context.Background(), WithMbox(path))
+	require.NoError(t, err)
+
+	emails, err := s.GetApplicationEmails("2025-03-01", "2025-03-04")
+	require.NoError(t, err)
+	require.Len(t, emails, 2)
+
+	assert.Equal(t, "Lyft <no-reply@us.greenhouse-mail.io>", emails[0].FullSender)
+	assert.Equal(t, "us.greenhouse-mail.io", emails[0].Domain)
+	assert.Equal(t, "Thank you for applying – Lyft", emails[0].Subject)
+	assert.Equal(t, "lyft-1@greenhouse.io", emails[0].MessageId)
+	assert.Equal(t, "1826483520384", emails[0].ThreadId)
+	assert.True(t, time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC).Equal(emails[0].SentTime))
+	assert.Equal(t, "Thank you for your interest in Lyft! Café included.\nFrom now on, we will review your application.", emails[0].Msg)
+
+	assert.Equal(t, "acme.com", emails[1].Domain)
+	assert.Equal(t, "Unfortunately,\n\nwe went with other candidates.", emails[1].Msg)
+	// without Message-ID, the id is the index of the message in the file
+	assert.Equal(t, "takeout.mbox#2", emails[1].MessageId)
+
+	emails, err = s.GetApplicationEmails("", "")
+	require.NoError(t, err)
+	assert.Len(t, emails, 3)
+
+	_, err = s.GetApplicationEmails("03/01/2025", "")
+	assert.Error(t, err)
+
+	s, err = New(context.Background(), WithMbox(filepath.Join(t.TempDir(), "missing.mbox")))
+	require.NoError(t, err)
+	_, err = s.GetApplicationEmails("", "")
+	assert.Error(t, err)
+
+	_, err = New(context.Background())
+	assert.Error(t, err)
+}
+
+func TestEmlDir(t *testing.T) {
+	dir := t.TempDir()
+	write(t, filepath.Join(dir, "2025", "lyft.eml"), `From: Lyft <no-reply@us.greenhouse-mail.io>
+Subject: Thank you for applying
+Date: Sun, 02 Mar 2025 09:00:00 +0000
+Content-Type: text/plain; charset=windows-1252
+Content-Transfer-Encoding: quoted-printable
+
+Thank you for your interest in Lyft=92s Backend Engineer role.
+`)
+	// a Maildir message, without date, received at the modification time of the file
+	write(t, filepath.Join(dir, "Maildir", "cur", "1741165200.M1P1.host:2,S"), `From: jobs@acme.com
+Subject: Your application
+
+Unfortunately, we went with other candidates.
+`)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Maildir", "tmp"), 0o755))
+	modTime := time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "Maildir", "cur", "1741165200.M1P1.host:2,S"), modTime, modTime))
+	// neither an .eml file nor a Maildir message
+	write(t, filepath.Join(dir, "notes.txt"), "From: me@example.com\n\nnot an email\n")
+
+	s, err := New(context.Background(), WithEmlDir(dir))
+	require.NoError(t, err)
+
+	emails, err := s.GetApplicationEmails("2025-03-01", "")
+	require.NoError(t, err)
+	require.Len(t, emails, 2)
+
+	assert.Equal(t, "Thank you for your interest in Lyft’s Backend Engineer role.", emails[0].Msg)
+	assert.Equal(t, filepath.Join("2025", "lyft.eml"), emails[0].MessageId)
+
+	assert.Equal(t, "acme.com", emails[1].Domain)
+	assert.True(t, modTime.Equal(emails[1].SentTime))
+
+	emails, err = s.GetApplicationEmails("2025-03-03", "2025-03-04")
+	require.NoError(t, err)
+	assert.Empty(t, emails)
+}
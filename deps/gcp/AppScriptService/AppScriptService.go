@@ -2,20 +2,31 @@ package AppScriptService
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"log"
+	"iter"
+	"log/slog"
 	"net/http"
-	"os"
+	"time"
 
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"google.golang.org/api/option"
 	"google.golang.org/api/script/v1"
 
 	"github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/deps/gcp/oauth"
+	"github.com/MaxBear/maxhire/telemetry"
 )
 
+// Source is the name of the source of the emails
+const Source = "appscript"
+
+var tracer = otel.Tracer("github.com/MaxBear/maxhire/deps/gcp/AppScriptService")
+
 type AppScriptService struct {
 	ctx                       context.Context
 	withCredFile              string
@@ -25,6 +36,7 @@ type AppScriptService struct {
 	withAppScriptDeploymentId string
 	oAuthClient               *http.Client
 	scriptService             *script.Service
+	logger                    *slog.Logger
 }
 
 type AppScriptServiceOpt func(*AppScriptService)
@@ -59,44 +71,33 @@ func WithAppScriptDeploymentId(id string) AppScriptServiceOpt {
 	}
 }
 
+// WithLogger sets the logger of the service, defaults to slog.Default()
+func WithLogger(logger *slog.Logger) AppScriptServiceOpt {
+	return func(s *AppScriptService) {
+		s.logger = logger
+	}
+}
+
 func New(ctx context.Context, opts ...AppScriptServiceOpt) (*AppScriptService, error) {
 	s := &AppScriptService{
-		ctx: ctx,
+		ctx:    ctx,
+		logger: slog.Default(),
 	}
 
 	for _, opt := range opts {
 		opt(s)
 	}
 
-	b, err := os.ReadFile(s.withCredFile)
-	if err != nil {
-		log.Printf("Unable to read client secret file: %v", err)
-		return nil, err
-	}
-
-	config, err := google.ConfigFromJSON(b,
-		"https://www.googleapis.com/auth/script.projects",
-		"https://www.googleapis.com/auth/script.scriptapp",
-		"https://mail.google.com/",
-		"https://www.googleapis.com/auth/spreadsheets")
-
-	if err != nil {
-		log.Printf("Unable to parse client secret file to config: %v", err)
-		return nil, err
-	}
-
-	config.RedirectURL = s.withOauthRedirectUrl
-
-	client, err := s.getClient(config)
+	client, err := oauth.Client(ctx, s.logger, s.withCredFile, s.withTokFile, s.withOauthRedirectUrl, s.withOauthRedirectPort)
 	if err != nil {
-		log.Printf("Unable to parse client secret file to config: %v", err)
+		s.logger.ErrorContext(ctx, "unable to get oauth client", "error", err)
 		return nil, err
 	}
 	s.oAuthClient = client
 
 	srv, err := script.NewService(ctx, option.WithHTTPClient(s.oAuthClient))
 	if err != nil {
-		log.Printf("Unable to retrieve Script client: %v", err)
+		s.logger.ErrorContext(ctx, "unable to create script client", "error", err)
 		return nil, err
 	}
 	s.scriptService = srv
@@ -104,89 +105,76 @@ func New(ctx context.Context, opts ...AppScriptServiceOpt) (*AppScriptService, e
 	return s, nil
 }
 
-// Request a token from the web, then returns the retrieved token.
-func (s *AppScriptService) getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	fmt.Printf("Go to the following link in your browser: \n%v\n", authURL)
-
-	codeCh := make(chan string)
-	server := &http.Server{Addr: fmt.Sprintf(":%d", s.withOauthRedirectPort)}
-
-	// Define the callback handler
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		code := r.URL.Query().Get("code")
-		if code != "" {
-			fmt.Fprintf(w, "Auth successful! You can return to the terminal.")
-			codeCh <- code
-		}
-	})
-
-	go server.ListenAndServe()
-
-	// Wait for the code from the browser
-	code := <-codeCh
-	server.Shutdown(s.ctx)
-
-	// Exchange the code for an actual Token
-	tok, err := config.Exchange(s.ctx, code)
-	if err != nil {
-		log.Printf("Unable to retrieve token from web: %v", err)
-		return nil, err
-	}
-
-	return tok, nil
+// Name returns the name of the source
+func (s *AppScriptService) Name() string {
+	return Source
 }
 
-// Retrieves a token from a local file.
-func (s *AppScriptService) tokenFromFile() (*oauth2.Token, error) {
-	f, err := os.Open(s.withTokFile)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	tok := &oauth2.Token{}
-	err = json.NewDecoder(f).Decode(tok)
-	return tok, err
+// ID identifies the mailbox read, by the token of the account and the script deployment
+func (s *AppScriptService) ID() string {
+	return s.withTokFile + "#" + s.withAppScriptDeploymentId
 }
 
-// Saves a token to a file path.
-func (s *AppScriptService) saveToken(token *oauth2.Token) error {
-	log.Printf("Saving credential file to: %s\n", s.withTokFile)
-	f, err := os.OpenFile(s.withTokFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+// GetApplicationEmails returns the emails filtered by the script from start_date to end_date included,
+// formatted as 2006-01-02, either can be empty
+func (s *AppScriptService) GetApplicationEmails(start_date, end_date string) (models.RawEmailRecords, error) {
+	start, end, err := models.DateRange(start_date, end_date)
 	if err != nil {
-		log.Printf("Unable to cache oauth token, error: %v", err)
-		return err
+		return models.RawEmailRecords{}, err
 	}
-	defer f.Close()
-	json.NewEncoder(f).Encode(token)
-	return nil
+	return models.CollectRecords(s.Emails(s.ctx, start, end))
 }
 
-// Retrieve a token, saves the token, then returns the generated client.
-func (s *AppScriptService) getClient(config *oauth2.Config) (*http.Client, error) {
-	// The file token.json stores the user's access and refresh tokens, and is
-	// created automatically when the authorization flow completes for the first
-	// time.
-	tok, err := s.tokenFromFile()
-	if err == nil {
-		log.Println("Using existing token. If you get AuthRequiredError, delete token.json and re-run to re-authenticate with new scopes.")
-		return config.Client(s.ctx, tok), nil
-	}
-
-	log.Println("No existing token founing OAuth flow...")
-	tok, err = s.getTokenFromWeb(config)
-	if err != nil {
-		return nil, err
-	}
+// Emails returns the emails filtered by the script sent from start to end excluded, either is unbounded if
+// zero. The script returns all the emails at once. The emails without id are identified by a hash of their
+// time, sender and subject.
+func (s *AppScriptService) Emails(ctx context.Context, start, end time.Time) iter.Seq2[*models.RawEmailRecord, error] {
+	return func(yield func(*models.RawEmailRecord, error) bool) {
+		ctx, span := tracer.Start(ctx, "AppScript.runFilterMyEmails")
+		defer span.End()
+
+		began := time.Now()
+		// the script filters by day, the end day is included
+		first, next := models.DayRange(start, end)
+		start_date, end_date := "", ""
+		if !first.IsZero() {
+			start_date = first.Format(time.DateOnly)
+		}
+		if !next.IsZero() {
+			end_date = next.AddDate(0, 0, -1).Format(time.DateOnly)
+		}
+		emails, err := s.getApplicationEmails(ctx, start_date, end_date)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attribute.Int("emails", len(emails)))
+		telemetry.ObserveAppScriptCall(began, len(emails), err)
 
-	if err := s.saveToken(tok); err != nil {
-		return nil, err
+		for _, email := range emails {
+			if !email.InRange(start, end) {
+				continue
+			}
+			if email.MessageId == "" {
+				email.MessageId = messageId(email)
+			}
+			if !yield(email, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
 	}
+}
 
-	return config.Client(s.ctx, tok), nil
+// messageId returns the id of an email returned by the script without one
+func messageId(email *models.RawEmailRecord) string {
+	h := sha256.Sum256([]byte(email.SentTime.UTC().Format(time.RFC3339Nano) + "\n" + email.FullSender + "\n" + email.Subject))
+	return hex.EncodeToString(h[:12])
 }
 
-func (s *AppScriptService) GetApplicationEmails(start_date, end_date string) (models.RawEmailRecords, error) {
+func (s *AppScriptService) getApplicationEmails(ctx context.Context, start_date, end_date string) (models.RawEmailRecords, error) {
 	emails := []*models.RawEmailRecord{}
 
 	req := &script.ExecutionRequest{
@@ -197,27 +185,19 @@ func (s *AppScriptService) GetApplicationEmails(start_date, end_date string) (mo
 		},
 	}
 
-	resp, err := s.scriptService.Scripts.Run(s.withAppScriptDeploymentId, req).Do()
+	resp, err := s.scriptService.Scripts.Run(s.withAppScriptDeploymentId, req).Context(ctx).Do()
 	if err != nil {
-		log.Printf("Unable to execute script: %v", err)
+		s.logger.ErrorContext(ctx, "unable to execute script", "error", err)
 		return emails, err
 	}
 
 	if resp.Error != nil {
-		log.Printf("Script error: %s\n", resp.Error.Message)
-		if resp.Error.Code != 0 {
-			log.Printf("Error code: %d\n", resp.Error.Code)
-		}
-		if len(resp.Error.Details) > 0 {
-			log.Println("Error details:")
-			for i, detail := range resp.Error.Details {
-				detailJSON, _ := json.MarshalIndent(detail, "  ", "  ")
-				log.Printf("  [%d]: %s\n", i+1, string(detailJSON))
-			}
+		s.logger.ErrorContext(ctx, "script error", "code", resp.Error.Code, "message", resp.Error.Message)
+		// the details may quote the emails, they are only logged at the debug level
+		if s.logger.Enabled(ctx, slog.LevelDebug) {
+			details, _ := json.Marshal(resp.Error.Details)
+			s.logger.DebugContext(ctx, "script error details", "details", string(details))
 		}
-		// Print full error object for debugging
-		errorJSON, _ := json.MarshalIndent(resp.Error, "", "  ")
-		log.Printf("\nFull error object:\n%s\n", string(errorJSON))
 
 		return emails, fmt.Errorf("error running script, err code : %d, err :  %s",
 			resp.Error.Code,
@@ -225,7 +205,6 @@ func (s *AppScriptService) GetApplicationEmails(start_date, end_date string) (mo
 	}
 
 	var res models.ApiResp
-	var parseError error
 
 	// Strategy 1: Try to unmarshal as wrapped response
 	if err := json.Unmarshal(resp.Response, &res); err == nil && res.Result != nil {
@@ -233,7 +212,7 @@ func (s *AppScriptService) GetApplicationEmails(start_date, end_date string) (mo
 		if resultStr, ok := res.Result.(string); ok {
 			// It's a JSON string, unmarshal it
 			if err = json.Unmarshal([]byte(resultStr), &emails); err != nil {
-				log.Printf("Error parsing response: %v", parseError)
+				s.logger.ErrorContext(ctx, "unable to parse script response", "error", err)
 				return emails, err
 			}
 		}
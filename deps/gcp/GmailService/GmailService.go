@@ -0,0 +1,288 @@
+// Package GmailService reads the job application emails with the Gmail api, without the Apps Script.
+package GmailService
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+
+	"github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/deps/gcp/oauth"
+	"github.com/MaxBear/maxhire/deps/mailtext"
+	"github.com/MaxBear/maxhire/source"
+)
+
+// Source is the name of the source of the emails
+const Source = "gmail"
+
+// DefaultQuery searches the confirmations and answers of job applications
+const DefaultQuery = `-in:chats -in:sent {"thank you for applying" "thanks for applying" "your application" "application received" "thank you for your interest" "received your application"}`
+
+var tracer = otel.Tracer("github.com/MaxBear/maxhire/deps/gcp/GmailService")
+
+type GmailService struct {
+	ctx                   context.Context
+	withCredFile          string
+	withTokFile           string
+	withOauthRedirectUrl  string
+	withOauthRedirectPort int
+	withQuery             string
+	withPageSize          int64
+	withHTTPClient        *http.Client
+	withEndpoint          string
+	gmailService          *gmail.Service
+	logger                *slog.Logger
+}
+
+type GmailServiceOpt func(*GmailService)
+
+func WithCredFile(credFile string) GmailServiceOpt {
+	return func(s *GmailService) {
+		s.withCredFile = credFile
+	}
+}
+
+func WithTokFile(tokFile string) GmailServiceOpt {
+	return func(s *GmailService) {
+		s.withTokFile = tokFile
+	}
+}
+
+func WithOauthRedirectUrl(url string) GmailServiceOpt {
+	return func(s *GmailService) {
+		s.withOauthRedirectUrl = url
+	}
+}
+
+func WithOauthRedirectPort(port int) GmailServiceOpt {
+	return func(s *GmailService) {
+		s.withOauthRedirectPort = port
+	}
+}
+
+// WithQuery sets the Gmail search query of the emails, defaults to DefaultQuery
+func WithQuery(query string) GmailServiceOpt {
+	return func(s *GmailService) {
+		s.withQuery = query
+	}
+}
+
+// WithPageSize sets the number of messages listed per call, defaults to 100
+func WithPageSize(size int64) GmailServiceOpt {
+	return func(s *GmailService) {
+		s.withPageSize = size
+	}
+}
+
+// WithHTTPClient sets the client calling the api instead of the OAuth client of the credentials
+func WithHTTPClient(client *http.Client) GmailServiceOpt {
+	return func(s *GmailService) {
+		s.withHTTPClient = client
+	}
+}
+
+// WithEndpoint sets the url of the api, e.g. of a local stand-in in tests
+func WithEndpoint(url string) GmailServiceOpt {
+	return func(s *GmailService) {
+		s.withEndpoint = url
+	}
+}
+
+// WithLogger sets the logger of the service, defaults to slog.Default()
+func WithLogger(logger *slog.Logger) GmailServiceOpt {
+	return func(s *GmailService) {
+		s.logger = logger
+	}
+}
+
+func New(ctx context.Context, opts ...GmailServiceOpt) (*GmailService, error) {
+	s := &GmailService{
+		ctx:          ctx,
+		withQuery:    DefaultQuery,
+		withPageSize: 100,
+		logger:       slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	client := s.withHTTPClient
+	if client == nil {
+		var err error
+		client, err = oauth.Client(ctx, s.logger, s.withCredFile, s.withTokFile, s.withOauthRedirectUrl, s.withOauthRedirectPort)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "unable to get oauth client", "error", err)
+			return nil, err
+		}
+	}
+
+	clientOpts := []option.ClientOption{option.WithHTTPClient(client)}
+	if s.withEndpoint != "" {
+		clientOpts = append(clientOpts, option.WithEndpoint(s.withEndpoint))
+	}
+	srv, err := gmail.NewService(ctx, clientOpts...)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "unable to create gmail client", "error", err)
+		return nil, err
+	}
+	s.gmailService = srv
+
+	return s, nil
+}
+
+// Name returns the name of the source
+func (s *GmailService) Name() string {
+	return Source
+}
+
+// ID identifies the emails read, by the token of the account and the search query
+func (s *GmailService) ID() string {
+	return s.withTokFile + "?" + s.withQuery
+}
+
+// GetApplicationEmails returns the emails matching the query received from start_date to end_date included,
+// formatted as 2006-01-02, either can be empty
+func (s *GmailService) GetApplicationEmails(start_date, end_date string) (models.RawEmailRecords, error) {
+	start, end, err := models.DateRange(start_date, end_date)
+	if err != nil {
+		return models.RawEmailRecords{}, err
+	}
+	return models.CollectRecords(s.Emails(s.ctx, start, end))
+}
+
+// Emails returns the emails matching the query sent from start to end excluded, either is unbounded if
+// zero, one page of the search at a time. The emails are identified by their Gmail message id.
+func (s *GmailService) Emails(ctx context.Context, start, end time.Time) iter.Seq2[*models.RawEmailRecord, error] {
+	return source.Stream(ctx, Source, start, end, func(ctx context.Context, add func(*models.RawEmailRecord) bool) error {
+		return s.emails(ctx, start, end, add)
+	})
+}
+
+// query returns the search query of the emails received the days of start to end
+func (s *GmailService) query(start, end time.Time) string {
+	terms := []string{s.withQuery}
+	// the api searches by day, before is exclusive
+	first, next := models.DayRange(start, end)
+	if !first.IsZero() {
+		terms = append(terms, "after:"+first.Format("2006/01/02"))
+	}
+	if !next.IsZero() {
+		terms = append(terms, "before:"+next.Format("2006/01/02"))
+	}
+	return strings.TrimSpace(strings.Join(terms, " "))
+}
+
+// errStop stops the search once the emails are no longer iterated
+var errStop = errors.New("stop")
+
+// emails passes the emails matching the query to add until it returns false
+func (s *GmailService) emails(ctx context.Context, start, end time.Time, add func(*models.RawEmailRecord) bool) error {
+	q := s.query(start, end)
+
+	err := s.gmailService.Users.Messages.List("me").Q(q).MaxResults(s.withPageSize).Pages(ctx, func(resp *gmail.ListMessagesResponse) error {
+		s.logger.DebugContext(ctx, "listed messages", "query", q, "messages", len(resp.Messages))
+		for _, m := range resp.Messages {
+			msg, err := s.gmailService.Users.Messages.Get("me", m.Id).Format("full").Context(ctx).Do()
+			if err != nil {
+				return fmt.Errorf("unable to get message %s: %w", m.Id, err)
+			}
+			if !add(record(msg)) {
+				return errStop
+			}
+		}
+		return nil
+	})
+	if err == errStop {
+		return nil
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "unable to read messages", "query", q, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// record returns the email record of a message
+func record(msg *gmail.Message) *models.RawEmailRecord {
+	r := &models.RawEmailRecord{
+		SentTime:  time.UnixMilli(msg.InternalDate),
+		MessageId: msg.Id,
+		ThreadId:  msg.ThreadId,
+	}
+	if msg.Payload == nil {
+		return r
+	}
+
+	dec := new(mime.WordDecoder)
+	for _, h := range msg.Payload.Headers {
+		value, err := dec.DecodeHeader(h.Value)
+		if err != nil {
+			value = h.Value
+		}
+		switch strings.ToLower(h.Name) {
+		case "from":
+			r.FullSender = value
+		case "subject":
+			r.Subject = value
+		}
+	}
+	if addr, err := mail.ParseAddress(r.FullSender); err == nil {
+		if _, domain, found := strings.Cut(addr.Address, "@"); found {
+			r.Domain = domain
+		}
+	}
+
+	plain, html := bodies(msg.Payload)
+	switch {
+	case plain != "":
+		r.Msg = mailtext.Clean(plain)
+	case html != "":
+		r.Msg = mailtext.FromHTML(html)
+	default:
+		r.Msg = msg.Snippet
+	}
+
+	return r
+}
+
+// bodies returns the first text/plain and text/html bodies of the part and its sub parts, attachments
+// excepted
+func bodies(part *gmail.MessagePart) (plain, html string) {
+	if part.Filename == "" && part.Body != nil && part.Body.Data != "" {
+		// the api encodes the bodies in base64url, with or without padding, once decoded from their
+		// transfer encoding and charset
+		if b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part.Body.Data, "=")); err == nil {
+			mediaType, _, _ := mime.ParseMediaType(part.MimeType)
+			switch mediaType {
+			case "text/plain":
+				plain = string(b)
+			case "text/html":
+				html = string(b)
+			}
+		}
+	}
+	for _, p := range part.Parts {
+		pp, ph := bodies(p)
+		if plain == "" {
+			plain = pp
+		}
+		if html == "" {
+			html = ph
+		}
+	}
+	return plain, html
+}
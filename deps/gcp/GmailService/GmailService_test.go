@@ -0,0 +1,118 @@
+package GmailService
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+func encode(body string) string {
+	return base64.URLEncoding.EncodeToString([]byte(body))
+}
+
+// standIn serves the messages of the Gmail api, one per page, and records the search queries
+func standIn(t *testing.T, messages map[string]*gmail.Message, order []string) (*httptest.Server, *[]string) {
+	t.Helper()
+	queries := []string{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /gmail/v1/users/me/messages", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		// the page token is the index of the message of the page
+		i, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+		resp := &gmail.ListMessagesResponse{}
+		if i < len(order) {
+			resp.Messages = []*gmail.Message{{Id: order[i]}}
+		}
+		if i+1 < len(order) {
+			resp.NextPageToken = strconv.Itoa(i + 1)
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("GET /gmail/v1/users/me/messages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "full", r.URL.Query().Get("format"))
+		msg, ok := messages[r.PathValue("id")]
+		if !ok {
+			http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(msg)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &queries
+}
+
+func TestGetApplicationEmails(t *testing.T) {
+	sent := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	messages := map[string]*gmail.Message{
+		"m1": {
+			Id:           "m1",
+			ThreadId:     "t1",
+			InternalDate: sent.UnixMilli(),
+			Payload: &gmail.MessagePart{
+				MimeType: "multipart/alternative",
+				Headers: []*gmail.MessagePartHeader{
+					{Name: "From", Value: "Lyft <no-reply@us.greenhouse-mail.io>"},
+					{Name: "Subject", Value: "=?UTF-8?Q?Thank_you_for_applying_=E2=80=93_Lyft?="},
+				},
+				Parts: []*gmail.MessagePart{
+					{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: encode("Thank you for your interest in Lyft!\r\n\r\nWe received   your application.")}},
+					{MimeType: "text/html", Body: &gmail.MessagePartBody{Data: encode("<p>ignored</p>")}},
+				},
+			},
+		},
+		"m2": {
+			Id:           "m2",
+			ThreadId:     "t2",
+			InternalDate: sent.Add(time.Hour).UnixMilli(),
+			Payload: &gmail.MessagePart{
+				MimeType: "multipart/mixed",
+				Headers:  []*gmail.MessagePartHeader{{Name: "from", Value: "jobs@acme.com"}, {Name: "subject", Value: "Your application"}},
+				Parts: []*gmail.MessagePart{
+					{MimeType: "text/html; charset=utf-8", Body: &gmail.MessagePartBody{Data: encode("<html><head><style>p {}</style></head><body><p>Unfortunately,</p><p>we went with <b>other candidates</b>.</p></body></html>")}},
+					{MimeType: "text/plain", Filename: "resume.txt", Body: &gmail.MessagePartBody{AttachmentId: "a1"}},
+				},
+			},
+		},
+	}
+	srv, queries := standIn(t, messages, []string{"m1", "m2"})
+
+	s, err := New(context.Background(), WithHTTPClient(srv.Client()), WithEndpoint(srv.URL+"/"), WithQuery("label:jobs"), WithPageSize(1))
+	require.NoError(t, err)
+
+	emails, err := s.GetApplicationEmails("2025-03-01", "2025-03-04")
+	require.NoError(t, err)
+	require.Len(t, emails, 2)
+	assert.Equal(t, []string{"label:jobs after:2025/03/01 before:2025/03/05", "label:jobs after:2025/03/01 before:2025/03/05"}, *queries)
+
+	assert.Equal(t, "m1", emails[0].MessageId)
+	assert.Equal(t, "t1", emails[0].ThreadId)
+	assert.True(t, sent.Equal(emails[0].SentTime))
+	assert.Equal(t, "Lyft <no-reply@us.greenhouse-mail.io>", emails[0].FullSender)
+	assert.Equal(t, "us.greenhouse-mail.io", emails[0].Domain)
+	assert.Equal(t, "Thank you for applying – Lyft", emails[0].Subject)
+	assert.Equal(t, "Thank you for your interest in Lyft!\n\nWe received your application.", emails[0].Msg)
+
+	assert.Equal(t, "t2", emails[1].ThreadId)
+	assert.Equal(t, "acme.com", emails[1].Domain)
+	assert.Equal(t, "Unfortunately,\n\nwe went with other candidates.", emails[1].Msg)
+
+	_, err = s.GetApplicationEmails("03/01/2025", "")
+	assert.Error(t, err)
+
+	// a message missing after being listed fails the call
+	delete(messages, "m2")
+	_, err = s.GetApplicationEmails("", "")
+	assert.Error(t, err)
+}
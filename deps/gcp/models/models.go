@@ -1,15 +1,21 @@
 package models
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"log"
+	"log/slog"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/MaxBear/maxhire/logging"
 )
 
 type ApiResp struct {
@@ -24,6 +30,8 @@ type RawEmailRecord struct {
 	FullSender string    `json:"FullSender"`
 	Domain     string    `json:"Domain"`
 	Msg        string    `json:"Msg"`
+	MessageId  string    `json:"MessageId,omitempty"` // Id of the email in the source mailbox, if known
+	ThreadId   string    `json:"ThreadId,omitempty"`  // Id of the conversation of the email in the source mailbox, if known
 }
 
 type RawEmailRecords []*RawEmailRecord
@@ -38,6 +46,67 @@ func (ut *RawEmailRecords) UnmarshalJSON(dat []byte) error {
 	return nil
 }
 
+// CollectRecords returns the records of seq, and the ones read before its error if it fails
+func CollectRecords(seq iter.Seq2[*RawEmailRecord, error]) (RawEmailRecords, error) {
+	records := RawEmailRecords{}
+	for record, err := range seq {
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// DateRange returns the times from start_date to end_date included, formatted as 2006-01-02, the end being
+// the start of the next day. Either is zero if empty.
+func DateRange(start_date, end_date string) (start, end time.Time, err error) {
+	if start_date != "" {
+		if start, err = time.Parse(time.DateOnly, start_date); err != nil {
+			return start, end, fmt.Errorf("invalid start date %q: %w", start_date, err)
+		}
+	}
+	if end_date != "" {
+		if end, err = time.Parse(time.DateOnly, end_date); err != nil {
+			return start, end, fmt.Errorf("invalid end date %q: %w", end_date, err)
+		}
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, nil
+}
+
+// DayRange returns the days including start to end, the start of the day of start and the start of the day
+// after end, unless end starts a day, for the sources searching by day. Either is zero if zero.
+func DayRange(start, end time.Time) (first, next time.Time) {
+	day := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+	if !start.IsZero() {
+		first = day(start)
+	}
+	if !end.IsZero() {
+		next = day(end)
+		if next.Before(end) {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return first, next
+}
+
+// Key returns the id of the record in its source, or its time, sender and subject for the records without
+// id, e.g. saved before the sources had ids
+func (r *RawEmailRecord) Key() string {
+	if r.MessageId != "" {
+		return r.MessageId
+	}
+	return r.SentTime.UTC().Format(time.RFC3339Nano) + "\n" + r.FullSender + "\n" + r.Subject
+}
+
+// InRange tells whether the record was sent from start to end excluded, either is unbounded if zero
+func (r *RawEmailRecord) InRange(start, end time.Time) bool {
+	return (start.IsZero() || !r.SentTime.Before(start)) && (end.IsZero() || r.SentTime.Before(end))
+}
+
 func (in RawEmailRecords) ToEmails() Emails {
 	res := []*Email{}
 	for _, email := range in {
@@ -50,21 +119,30 @@ func (in RawEmailRecords) ToEmails() Emails {
 
 type Company string
 
+// InvalidCompanyWords are the words of a company name that was not extracted correctly, besides the
+// name of the applicant
+var InvalidCompanyWords = []string{
+	"jane",
+	"jane!",
+	"senior",
+	"engineer",
+	"thank you",
+	"application",
+	"applying",
+	"your company",
+	"sentaur",
+	"interest",
+	"infra",
+}
+
 func (c Company) Invalid() bool {
-	substrings := []string{
-		fmt.Sprintf("%s", os.Getenv("APPLICANT_FIRST_NAME")),
-		fmt.Sprintf("%s!", os.Getenv("APPLICANT_LAST_NAME")),
-		"jane",
-		"jane!",
-		"senior",
-		"engineer",
-		"thank you",
-		"application",
-		"applying",
-		"your company",
-		"sentaur",
-		"interest",
-		"infra",
+	substrings := slices.Clone(InvalidCompanyWords)
+	// an empty name would match every company
+	if first := os.Getenv("APPLICANT_FIRST_NAME"); first != "" {
+		substrings = append(substrings, first)
+	}
+	if last := os.Getenv("APPLICANT_LAST_NAME"); last != "" {
+		substrings = append(substrings, last+"!")
 	}
 
 	found := false
@@ -84,12 +162,14 @@ func (c Company) Invalid() bool {
 
 type Sender string
 
+// NoReplyPrefixes are the prefixes of automated sender addresses, followed by the domain of the company
+var NoReplyPrefixes = []string{
+	"no-reply@",
+	"gh-no-reply@",
+}
+
 func (s Sender) Domain() (string, bool) {
-	prefixes := []string{
-		"no-reply@",
-		"gh-no-reply@",
-	}
-	for _, prefix := range prefixes {
+	for _, prefix := range NoReplyPrefixes {
 		domain, found := strings.CutPrefix(string(s), prefix)
 		if found {
 			return domain, true
@@ -101,23 +181,88 @@ func (s Sender) Domain() (string, bool) {
 type Status int
 
 const (
-	Pending Status = iota // 0
-	Reject                // 1
-	Success               // 2
-	Applied               // 3
+	Pending       Status = iota // 0
+	Reject                      // 1
+	Success                     // 2
+	Applied                     // 3
+	Interviewing                // 4
+	Offer                       // 5
+	OfferAccepted               // 6
+	OfferDeclined               // 7
+	Withdrawn                   // 8
+	Ghosted                     // 9
 )
 
 // String method for general printing (fmt.Println)
 func (s Status) String() string {
-	return [...]string{"Pending", "Reject", "Success", "Applied"}[s]
+	if !s.Valid() {
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+	return [...]string{
+		"Pending",
+		"Reject",
+		"Success",
+		"Applied",
+		"Interviewing",
+		"Offer",
+		"OfferAccepted",
+		"OfferDeclined",
+		"Withdrawn",
+		"Ghosted"}[s]
+}
+
+// Valid reports whether s is one of the known statuses
+func (s Status) Valid() bool {
+	return s >= Pending && s <= Ghosted
+}
+
+// transitions lists the statuses an application can move to from each status,
+// Reject, OfferAccepted, OfferDeclined and Withdrawn are final
+var transitions = map[Status][]Status{
+	Pending:      {Applied, Reject, Success, Interviewing, Offer, Withdrawn, Ghosted},
+	Applied:      {Reject, Success, Interviewing, Offer, Withdrawn, Ghosted},
+	Success:      {Reject, Interviewing, Offer, Withdrawn, Ghosted},
+	Interviewing: {Reject, Success, Offer, Withdrawn, Ghosted},
+	Offer:        {Reject, OfferAccepted, OfferDeclined, Withdrawn},
+	// a ghosted application is revived by any later response
+	Ghosted: {Reject, Success, Interviewing, Offer, Withdrawn},
+}
+
+// CanTransitionTo reports whether an application can move from status s to next,
+// staying in the same status is always allowed
+func (s Status) CanTransitionTo(next Status) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsResponse reports whether the status means the company got back to the applicant
+func (s Status) IsResponse() bool {
+	switch s {
+	case Reject, Success, Interviewing, Offer, OfferAccepted, OfferDeclined:
+		return true
+	}
+	return false
 }
 
 func ParseStatus(s string) (Status, error) {
 	statusMap := map[string]Status{
-		"pending": Pending,
-		"reject":  Reject,
-		"accept":  Success,
-		"applied": Applied,
+		"pending":        Pending,
+		"reject":         Reject,
+		"accept":         Success,
+		"applied":        Applied,
+		"interviewing":   Interviewing,
+		"offer":          Offer,
+		"offer_accepted": OfferAccepted,
+		"offer_declined": OfferDeclined,
+		"withdrawn":      Withdrawn,
+		"ghosted":        Ghosted,
 	}
 
 	if val, ok := statusMap[s]; ok {
@@ -148,6 +293,18 @@ func (s *Status) UnmarshalJSON(data []byte) error {
 		*s = Success
 	case "Applied":
 		*s = Applied
+	case "Interviewing":
+		*s = Interviewing
+	case "Offer":
+		*s = Offer
+	case "OfferAccepted":
+		*s = OfferAccepted
+	case "OfferDeclined":
+		*s = OfferDeclined
+	case "Withdrawn":
+		*s = Withdrawn
+	case "Ghosted":
+		*s = Ghosted
 	}
 	return nil
 }
@@ -161,6 +318,29 @@ type Email struct {
 
 type Emails []*Email
 
+// Merge returns the emails of in and the ones of more whose record is not in in, newest first, and the
+// number of emails of more added
+func (in Emails) Merge(more Emails) (Emails, int) {
+	keys := map[string]bool{}
+	merged := slices.Clone(in)
+	for _, email := range in {
+		keys[email.EmailRecord.Key()] = true
+	}
+	added := 0
+	for _, email := range more {
+		if key := email.EmailRecord.Key(); !keys[key] {
+			keys[key] = true
+			merged = append(merged, email)
+			added++
+		}
+	}
+	// UpdateStatus expects the emails by descending sent time
+	slices.SortStableFunc(merged, func(a, b *Email) int {
+		return b.EmailRecord.SentTime.Compare(a.EmailRecord.SentTime)
+	})
+	return merged, added
+}
+
 func (in Emails) ToJson(jsonFile string) error {
 	fileData, err := json.MarshalIndent(in, "", "  ")
 	if err != nil {
@@ -279,16 +459,19 @@ func FromCsv(csvFile string) (Emails, error) {
 	return apps, nil
 }
 
-func (in Emails) Print() {
+// Log logs the emails, their bodies and senders are redacted unless the logger is in debug mode
+func (in Emails) Log(ctx context.Context, logger *slog.Logger) {
 	for i, email := range in {
-		fmt.Printf("\n[%d] Subject: %s\n", i+1, email.EmailRecord.Subject)
-		fmt.Printf("%10s: %s\n", "SentTime", email.EmailRecord.SentTime.Local().Format(time.RFC1123Z))
-		fmt.Printf("%10s: %s\n", "Sender", email.EmailRecord.FullSender)
-		fmt.Printf("%10s: %s\n", "Domain", email.EmailRecord.Domain)
-		fmt.Printf("%10s: %s\n", "Msg", email.EmailRecord.Msg)
-		fmt.Printf("%10s: %s\n", "Company", email.Company)
-		fmt.Printf("%10s: %s\n", "Position", email.Position)
-		fmt.Printf("%10s: %s\n", "Status", email.Status)
+		logger.InfoContext(ctx, "email",
+			"index", i+1,
+			"subject", email.EmailRecord.Subject,
+			"sent_time", email.EmailRecord.SentTime,
+			logging.KeySender, email.EmailRecord.FullSender,
+			"domain", email.EmailRecord.Domain,
+			logging.KeyBody, email.EmailRecord.Msg,
+			"company", email.Company,
+			"position", email.Position,
+			"status", email.Status.String())
 	}
 }
 
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -49,6 +50,8 @@ func TestCompany(t *testing.T) {
 		c := Company(tc)
 		assert.Equal(t, true, c.Invalid())
 	}
+
+	assert.Equal(t, false, Company("Lyft").Invalid())
 }
 
 func TestSender(t *testing.T) {
@@ -337,3 +340,111 @@ func TestUpdateStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusJson(t *testing.T) {
+	for status := Pending; status <= Ghosted; status++ {
+		t.Run(status.String(), func(t *testing.T) {
+			b, err := json.Marshal(status)
+			require.Nil(t, err)
+			assert.Equal(t, fmt.Sprintf("%q", status.String()), string(b))
+
+			var res Status
+			require.Nil(t, json.Unmarshal(b, &res))
+			assert.Equal(t, status, res)
+		})
+	}
+}
+
+func TestStatusValid(t *testing.T) {
+	assert.True(t, Ghosted.Valid())
+	assert.False(t, Status(42).Valid())
+	assert.False(t, Status(-1).Valid())
+	assert.Equal(t, "Status(42)", Status(42).String())
+}
+
+func TestParseStatus(t *testing.T) {
+	tcs := map[string]Status{
+		"accept":         Success,
+		"interviewing":   Interviewing,
+		"offer":          Offer,
+		"offer_accepted": OfferAccepted,
+		"withdrawn":      Withdrawn,
+	}
+
+	for in, expected := range tcs {
+		status, err := ParseStatus(in)
+		require.Nil(t, err)
+		assert.Equal(t, expected, status)
+	}
+
+	_, err := ParseStatus("hired")
+	assert.NotNil(t, err)
+}
+
+func TestStatusTransitions(t *testing.T) {
+	tcs := []struct {
+		from, to Status
+		allowed  bool
+	}{
+		{Pending, Interviewing, true},
+		{Applied, Ghosted, true},
+		{Interviewing, Offer, true},
+		{Offer, OfferAccepted, true},
+		{Ghosted, Interviewing, true},
+		{Reject, Reject, true},
+		{Reject, Interviewing, false},
+		{OfferAccepted, Withdrawn, false},
+		{Interviewing, Pending, false},
+		{Pending, OfferAccepted, false},
+		{Offer, Ghosted, false},
+	}
+
+	for _, tc := range tcs {
+		assert.Equal(t, tc.allowed, tc.from.CanTransitionTo(tc.to), "%s -> %s", tc.from, tc.to)
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	start, end, err := DateRange("2025-03-01", "2025-03-04")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = DateRange("", "")
+	require.NoError(t, err)
+	assert.True(t, start.IsZero() && end.IsZero())
+
+	_, _, err = DateRange("03/01/2025", "")
+	assert.Error(t, err)
+
+	first, next := DayRange(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), first)
+	assert.Equal(t, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), next)
+	_, next = DayRange(time.Time{}, end)
+	assert.Equal(t, end, next)
+
+	r := &RawEmailRecord{SentTime: time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)}
+	assert.True(t, r.InRange(start, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, r.InRange(start, r.SentTime))
+	assert.True(t, r.InRange(time.Time{}, time.Time{}))
+}
+
+func TestMerge(t *testing.T) {
+	at := func(d int) time.Time {
+		return time.Date(2025, 3, d, 9, 0, 0, 0, time.UTC)
+	}
+	existing := Emails{
+		{Company: "Lyft", EmailRecord: &RawEmailRecord{SentTime: at(2), MessageId: "m1"}},
+		{Company: "Acme", EmailRecord: &RawEmailRecord{SentTime: at(1), FullSender: "jobs@acme.com", Subject: "Applied"}},
+	}
+	merged, added := existing.Merge(Emails{
+		{Company: "Lyft again", EmailRecord: &RawEmailRecord{SentTime: at(2), MessageId: "m1"}},
+		{EmailRecord: &RawEmailRecord{SentTime: at(1), FullSender: "jobs@acme.com", Subject: "Applied"}},
+		{Company: "Plaid", EmailRecord: &RawEmailRecord{SentTime: at(4), MessageId: "m2"}},
+	})
+	assert.Equal(t, 1, added)
+	require.Len(t, merged, 3)
+	assert.Equal(t, []string{"Plaid", "Lyft", "Acme"}, []string{merged[0].Company, merged[1].Company, merged[2].Company})
+	// in is kept as is
+	assert.Equal(t, "Lyft", existing[0].Company)
+}
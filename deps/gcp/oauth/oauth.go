@@ -0,0 +1,119 @@
+// Package oauth authorizes the calls to the Google apis with the OAuth token of the Google account,
+// cached in a file once granted in the browser.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// Scopes of the token, shared by the Apps Script and Gmail services so they can use the same token file
+var Scopes = []string{
+	"https://www.googleapis.com/auth/script.projects",
+	"https://www.googleapis.com/auth/script.scriptapp",
+	"https://mail.google.com/",
+	"https://www.googleapis.com/auth/spreadsheets",
+}
+
+// Client returns the http client authorized by the token of tokFile for the OAuth client of credFile.
+// Without a token, the user grants it in the browser, redirected to redirectUrl served on redirectPort,
+// and it is saved to tokFile.
+func Client(ctx context.Context, logger *slog.Logger, credFile, tokFile, redirectUrl string, redirectPort int) (*http.Client, error) {
+	b, err := os.ReadFile(credFile)
+	if err != nil {
+		logger.ErrorContext(ctx, "unable to read client secret file", "file", credFile, "error", err)
+		return nil, err
+	}
+
+	config, err := google.ConfigFromJSON(b, Scopes...)
+	if err != nil {
+		logger.ErrorContext(ctx, "unable to parse client secret file", "file", credFile, "error", err)
+		return nil, err
+	}
+	config.RedirectURL = redirectUrl
+
+	// The token file stores the user's access and refresh tokens, and is created automatically when the
+	// authorization flow completes for the first time.
+	tok, err := tokenFromFile(tokFile)
+	if err == nil {
+		logger.InfoContext(ctx, "using existing oauth token, delete it and re-run to re-authenticate on AuthRequiredError", "file", tokFile)
+		return config.Client(ctx, tok), nil
+	}
+
+	logger.InfoContext(ctx, "no existing oauth token, starting oauth flow", "file", tokFile)
+	tok, err = tokenFromWeb(ctx, logger, config, redirectPort)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveToken(ctx, logger, tokFile, tok); err != nil {
+		return nil, err
+	}
+
+	return config.Client(ctx, tok), nil
+}
+
+// Request a token from the web, then returns the retrieved token.
+func tokenFromWeb(ctx context.Context, logger *slog.Logger, config *oauth2.Config, redirectPort int) (*oauth2.Token, error) {
+	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
+	fmt.Printf("Go to the following link in your browser: \n%v\n", authURL)
+
+	codeCh := make(chan string)
+	server := &http.Server{Addr: fmt.Sprintf(":%d", redirectPort)}
+
+	// Define the callback handler
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		code := r.URL.Query().Get("code")
+		if code != "" {
+			fmt.Fprintf(w, "Auth successful! You can return to the terminal.")
+			codeCh <- code
+		}
+	})
+
+	go server.ListenAndServe()
+
+	// Wait for the code from the browser
+	code := <-codeCh
+	server.Shutdown(ctx)
+
+	// Exchange the code for an actual Token
+	tok, err := config.Exchange(ctx, code)
+	if err != nil {
+		logger.ErrorContext(ctx, "unable to retrieve token from web", "error", err)
+		return nil, err
+	}
+
+	return tok, nil
+}
+
+// Retrieves a token from a local file.
+func tokenFromFile(path string) (*oauth2.Token, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tok := &oauth2.Token{}
+	err = json.NewDecoder(f).Decode(tok)
+	return tok, err
+}
+
+// Saves a token to a file path.
+func saveToken(ctx context.Context, logger *slog.Logger, path string, token *oauth2.Token) error {
+	logger.InfoContext(ctx, "saving oauth token", "file", path)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		logger.ErrorContext(ctx, "unable to cache oauth token", "file", path, "error", err)
+		return err
+	}
+	defer f.Close()
+	json.NewEncoder(f).Encode(token)
+	return nil
+}
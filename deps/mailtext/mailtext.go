@@ -0,0 +1,148 @@
+// Package mailtext parses emails and converts their bodies to the plain text analyzed by the classifier
+// and LLM.
+package mailtext
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-message"
+	// registers the decoders of the charsets other than utf-8 and us-ascii
+	_ "github.com/emersion/go-message/charset"
+	"github.com/emersion/go-message/mail"
+	"golang.org/x/net/html"
+
+	"github.com/MaxBear/maxhire/deps/gcp/models"
+)
+
+var (
+	spaces     = regexp.MustCompile(`[ \t\r\f\v\x{a0}]+`)
+	blankLines = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// blocks are the elements on their own lines
+var blocks = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "br": true, "div": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true,
+	"li": true, "ol": true, "p": true, "section": true, "table": true, "td": true, "th": true, "tr": true, "ul": true,
+}
+
+// FromHTML returns the text of an html body, without its tags, scripts and styles
+func FromHTML(body string) string {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return Clean(body)
+	}
+
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+		case html.ElementNode:
+			switch n.Data {
+			case "script", "style", "head", "title":
+				return
+			}
+			if blocks[n.Data] {
+				b.WriteString("\n")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && blocks[n.Data] {
+			b.WriteString("\n")
+		}
+	}
+	walk(doc)
+
+	return Clean(b.String())
+}
+
+// Clean collapses the spaces and blank lines of a text body
+func Clean(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaces.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// Parse parses an RFC 5322 email into an email record: its plain text body, or the text of its html body
+// without one, decoded from their transfer encoding and charset. SentTime is the Date header, received if
+// missing.
+func Parse(r io.Reader, received time.Time) (*models.RawEmailRecord, error) {
+	mr, err := mail.CreateReader(r)
+	if err != nil && !message.IsUnknownCharset(err) {
+		return nil, err
+	}
+	defer mr.Close()
+
+	record := &models.RawEmailRecord{SentTime: received}
+	if date, err := mr.Header.Date(); err == nil && !date.IsZero() {
+		record.SentTime = date
+	}
+	if subject, err := mr.Header.Subject(); err == nil {
+		record.Subject = subject
+	} else {
+		record.Subject = mr.Header.Get("Subject")
+	}
+	if id, err := mr.Header.MessageID(); err == nil {
+		record.MessageId = id
+	}
+	// the thread of the emails exported by Google Takeout
+	record.ThreadId = mr.Header.Get("X-GM-THRID")
+	record.FullSender = mr.Header.Get("From")
+	if from, err := mr.Header.AddressList("From"); err == nil && len(from) > 0 {
+		// the name is kept as is, String quotes and encodes it
+		record.FullSender = from[0].Address
+		if from[0].Name != "" {
+			record.FullSender = from[0].Name + " <" + from[0].Address + ">"
+		}
+		if _, domain, found := strings.Cut(from[0].Address, "@"); found {
+			record.Domain = domain
+		}
+	}
+
+	var plain, htmlBody string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil && !message.IsUnknownCharset(err) {
+			return nil, err
+		}
+		h, ok := part.Header.(*mail.InlineHeader)
+		if !ok {
+			continue
+		}
+		mediaType, _, _ := h.ContentType()
+		if mediaType != "text/plain" && mediaType != "text/html" {
+			continue
+		}
+		b, err := io.ReadAll(part.Body)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case mediaType == "text/plain" && plain == "":
+			plain = string(b)
+		case mediaType == "text/html" && htmlBody == "":
+			htmlBody = string(b)
+		}
+	}
+
+	switch {
+	case strings.TrimSpace(plain) != "":
+		record.Msg = Clean(plain)
+	case htmlBody != "":
+		record.Msg = FromHTML(htmlBody)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,391 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/MaxBear/maxhire/logging"
+	applicationspb "github.com/MaxBear/maxhire/proto/gen/go/applications/v1"
+)
+
+// forwardedHeaders are passed on to the grpc server as metadata
+var forwardedHeaders = []string{"Authorization"}
+
+var (
+	marshal = protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	}
+	unmarshal = protojson.UnmarshalOptions{
+		DiscardUnknown: true,
+	}
+)
+
+// route maps an http endpoint to an rpc
+type route struct {
+	method string
+	// net/http pattern, with the {id} wildcard when the rpc takes an id
+	path string
+	// Request field set from the {id} path wildcard
+	idField string
+	// Request field set from the request body, "*" for the whole request, the rest comes from the query string
+	body     string
+	input    protoreflect.MessageDescriptor
+	newInput func() proto.Message
+	output   protoreflect.MessageDescriptor
+	stream   bool
+	call     func(ctx context.Context, req proto.Message, w http.ResponseWriter) error
+	// logs the errors of the calls
+	logger *slog.Logger
+}
+
+// unary routes the endpoint to a unary rpc
+func unary[Req, Res proto.Message](method, path, idField, body string, rpc func(context.Context, Req, ...grpc.CallOption) (Res, error)) route {
+	var req Req
+	var res Res
+	return route{
+		method:  method,
+		path:    path,
+		idField: idField,
+		body:    body,
+		input:   req.ProtoReflect().Descriptor(),
+		newInput: func() proto.Message {
+			return req.ProtoReflect().Type().New().Interface()
+		},
+		output: res.ProtoReflect().Descriptor(),
+		call: func(ctx context.Context, req proto.Message, w http.ResponseWriter) error {
+			res, err := rpc(ctx, req.(Req))
+			if err != nil {
+				return err
+			}
+			b, err := marshal.Marshal(res)
+			if err != nil {
+				return err
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, err = w.Write(b)
+			return err
+		},
+	}
+}
+
+// serverStream routes the endpoint to a server streaming rpc, messages are written as newline delimited json
+// objects with the message in "result", or the error in "error" if the stream fails after it started.
+func serverStream[Req proto.Message, Res any, PRes interface {
+	*Res
+	proto.Message
+}](method, path string, rpc func(context.Context, Req, ...grpc.CallOption) (grpc.ServerStreamingClient[Res], error)) route {
+	var req Req
+	var res PRes
+	return route{
+		method: method,
+		path:   path,
+		input:  req.ProtoReflect().Descriptor(),
+		newInput: func() proto.Message {
+			return req.ProtoReflect().Type().New().Interface()
+		},
+		output: res.ProtoReflect().Descriptor(),
+		stream: true,
+		call: func(ctx context.Context, req proto.Message, w http.ResponseWriter) error {
+			stream, err := rpc(ctx, req.(Req))
+			if err != nil {
+				return err
+			}
+			// errors are only reported once the server got the request
+			res, err := stream.Recv()
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			flusher, _ := w.(http.Flusher)
+			for ; err == nil; res, err = stream.Recv() {
+				b, err := marshal.Marshal(PRes(res))
+				if err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(w, "{\"result\":%s}\n", b); err != nil {
+					return err
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				b, _ := json.Marshal(map[string]any{"error": errorBody(err)})
+				fmt.Fprintf(w, "%s\n", b)
+			}
+			return nil
+		},
+	}
+}
+
+func routes(client applicationspb.ApplicationsClient) []route {
+	return []route{
+		unary("GET", "/v1/applications", "", "", client.ListApplications),
+		unary("POST", "/v1/applications", "", "*", client.SetApplications),
+		unary("GET", "/v1/applications/{id}", "id", "", client.GetApplication),
+		unary("PATCH", "/v1/applications/{id}", "application.id", "application", client.UpdateApplication),
+		unary("DELETE", "/v1/applications/{id}", "id", "", client.DeleteApplication),
+		unary("GET", "/v1/applications/{id}/events", "id", "", client.ListApplicationEvents),
+		unary("PUT", "/v1/applications/{id}/interviews", "id", "*", client.SetInterviews),
+		unary("POST", "/v1/interviews", "", "*", client.SetInterviews),
+		unary("GET", "/v1/stats", "", "", client.GetStats),
+		serverStream("GET", "/v1/applications:watch", client.WatchApplications),
+	}
+}
+
+type Gateway struct {
+	mux            *http.ServeMux
+	allowedOrigins []string
+	logger         *slog.Logger
+}
+
+type GatewayOpt func(*Gateway)
+
+// WithAllowedOrigins allows browsers to call the api from the origins, "*" allows any origin.
+// Cross origin requests are not allowed by default.
+func WithAllowedOrigins(origins ...string) GatewayOpt {
+	return func(g *Gateway) {
+		g.allowedOrigins = origins
+	}
+}
+
+// WithLogger sets the logger of the failed calls, defaults to slog.Default()
+func WithLogger(logger *slog.Logger) GatewayOpt {
+	return func(g *Gateway) {
+		g.logger = logger
+	}
+}
+
+// New returns an http handler serving every rpc of the applications api as json,
+// with the OpenAPI document describing it at /openapi.json
+func New(client applicationspb.ApplicationsClient, opts ...GatewayOpt) *Gateway {
+	g := &Gateway{
+		mux:    http.NewServeMux(),
+		logger: slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	routes := routes(client)
+	for _, r := range routes {
+		r.logger = g.logger
+		g.mux.HandleFunc(r.method+" "+r.path, r.handle)
+	}
+
+	document, err := json.MarshalIndent(openAPI(routes), "", "  ")
+	if err != nil {
+		// only fails on a bug in openAPI
+		panic(err)
+	}
+	g.mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(document)
+	})
+
+	return g
+}
+
+func (g *Gateway) allowed(origin string) bool {
+	return slices.Contains(g.allowedOrigins, "*") || slices.Contains(g.allowedOrigins, origin)
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" && g.allowed(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-Id")
+
+		// Preflight request
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Request-Id")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	g.mux.ServeHTTP(w, r)
+}
+
+func (r *route) handle(w http.ResponseWriter, req *http.Request) {
+	msg, err := r.request(req)
+	if err != nil {
+		writeError(req.Context(), r.logger, w, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	// the grpc server logs the call with the request id of the client, or the one returned to the client
+	id := logging.NewRequestID(req.Header.Get(logging.RequestIDHeader))
+	w.Header().Set(logging.RequestIDHeader, id)
+
+	ctx := req.Context()
+	md := metadata.Pairs(logging.RequestIDHeader, id)
+	for _, header := range forwardedHeaders {
+		if value := req.Header.Get(header); value != "" {
+			md.Set(header, value)
+		}
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	if err := r.call(ctx, msg, w); err != nil {
+		writeError(ctx, r.logger, w, err)
+	}
+}
+
+// request builds the rpc request from the path wildcard, the query string and the body
+func (r *route) request(req *http.Request) (proto.Message, error) {
+	fields := map[string]any{}
+
+	if r.body != "" {
+		var body any
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid request body, error: %s", err.Error())
+		}
+		if r.body == "*" {
+			if obj, ok := body.(map[string]any); ok {
+				fields = obj
+			} else if body != nil {
+				return nil, fmt.Errorf("request body must be a json object")
+			}
+		} else if body != nil {
+			fields[r.body] = body
+		}
+	}
+
+	for key, values := range req.URL.Query() {
+		field := r.input.Fields().ByName(protoreflect.Name(key))
+		if field == nil {
+			field = r.input.Fields().ByJSONName(key)
+		}
+		if field == nil {
+			return nil, fmt.Errorf("unknown query parameter %q", key)
+		}
+		value, err := queryValue(field, values)
+		if err != nil {
+			return nil, err
+		}
+		fields[string(field.Name())] = value
+	}
+
+	if r.idField != "" {
+		setField(fields, r.idField, req.PathValue("id"))
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	msg := r.newInput()
+	if err := unmarshal.Unmarshal(b, msg); err != nil {
+		return nil, fmt.Errorf("invalid request, error: %s", err.Error())
+	}
+	return msg, nil
+}
+
+// queryValue converts query string values to the json value of the field,
+// protojson takes numbers, enums and timestamps as strings.
+func queryValue(field protoreflect.FieldDescriptor, values []string) (any, error) {
+	convert := func(value string) (any, error) {
+		if field.Kind() == protoreflect.BoolKind {
+			return strconv.ParseBool(value)
+		}
+		return value, nil
+	}
+
+	if !field.IsList() {
+		return convert(values[len(values)-1])
+	}
+
+	list := []any{}
+	for _, value := range values {
+		// both statuses=REJECT&statuses=APPLIED and statuses=REJECT,APPLIED
+		for _, v := range strings.Split(value, ",") {
+			converted, err := convert(v)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+	}
+	return list, nil
+}
+
+// setField sets the value at the dot separated path of json objects
+func setField(fields map[string]any, path string, value any) {
+	names := strings.Split(path, ".")
+	for _, name := range names[:len(names)-1] {
+		child, ok := fields[name].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			fields[name] = child
+		}
+		fields = child
+	}
+	fields[names[len(names)-1]] = value
+}
+
+// httpStatus maps grpc codes to http status codes, the same way grpc-gateway does
+var httpStatus = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+}
+
+func errorBody(err error) map[string]any {
+	s := status.Convert(err)
+	return map[string]any{
+		"code":    s.Code().String(),
+		"message": s.Message(),
+	}
+}
+
+func writeError(ctx context.Context, logger *slog.Logger, w http.ResponseWriter, err error) {
+	code, ok := httpStatus[status.Code(err)]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	if code == http.StatusInternalServerError {
+		logger.ErrorContext(ctx, "error calling applications api", "error", err)
+	}
+
+	b, _ := json.Marshal(errorBody(err))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(b)
+}
@@ -0,0 +1,248 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	applicationspb "github.com/MaxBear/maxhire/proto/gen/go/applications/v1"
+	"github.com/MaxBear/maxhire/server"
+	"github.com/MaxBear/maxhire/service"
+)
+
+func setup(t *testing.T, opts ...GatewayOpt) *httptest.Server {
+	ctx := context.Background()
+	svc, err := service.NewService(ctx, "")
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	applicationspb.RegisterApplicationsServer(grpcServer, server.New(svc))
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	ts := httptest.NewServer(New(applicationspb.NewApplicationsClient(conn), opts...))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func call(t *testing.T, ts *httptest.Server, method, path, body string) (int, map[string]any) {
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var obj map[string]any
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&obj))
+	return res.StatusCode, obj
+}
+
+func TestGateway(t *testing.T) {
+	ts := setup(t)
+
+	code, res := call(t, ts, "POST", "/v1/applications", `{
+		"applications": [
+			{"date": "2024-01-15T10:00:00Z", "company": "TestCompany", "position": "Software Engineer"},
+			{"date": "2024-01-16T10:00:00Z", "company": "OtherCompany", "status": "REJECT"}
+		],
+		"source": "SOURCE_LLM"
+	}`)
+	require.Equal(t, http.StatusOK, code, res)
+	results := res["results"].([]any)
+	require.Len(t, results, 2)
+	assert.Equal(t, "RESULT_CREATED", results[0].(map[string]any)["result"])
+	id := results[0].(map[string]any)["application"].(map[string]any)["id"].(string)
+
+	// Filters in the query string, enums as names
+	code, res = call(t, ts, "GET", "/v1/applications?statuses=PENDING,APPLIED&order_by=date&page_size=10", "")
+	require.Equal(t, http.StatusOK, code, res)
+	applications := res["applications"].([]any)
+	require.Len(t, applications, 1)
+	application := applications[0].(map[string]any)
+	assert.Equal(t, id, application["id"])
+	assert.Equal(t, "PENDING", application["status"])
+	assert.Equal(t, "2024-01-15T10:00:00Z", application["date"])
+	assert.Equal(t, float64(1), res["total_size"])
+
+	code, res = call(t, ts, "PATCH", "/v1/applications/"+id+"?update_mask=status", `{"status": "INTERVIEWING"}`)
+	require.Equal(t, http.StatusOK, code, res)
+	assert.Equal(t, "INTERVIEWING", res["application"].(map[string]any)["status"])
+
+	code, res = call(t, ts, "PUT", "/v1/applications/"+id+"/interviews", `{
+		"interviews": [{"datetime": "2024-01-20T14:00:00Z", "interview_type": "RECRUITER_SCREEN", "duration_min": 30}]
+	}`)
+	require.Equal(t, http.StatusOK, code, res)
+	assert.Len(t, res["application"].(map[string]any)["interviews"], 1)
+
+	code, res = call(t, ts, "GET", "/v1/applications/"+id+"/events", "")
+	require.Equal(t, http.StatusOK, code, res)
+	assert.Len(t, res["events"], 3)
+
+	code, res = call(t, ts, "GET", "/v1/stats?start_date=2024-01-01T00:00:00Z", "")
+	require.Equal(t, http.StatusOK, code, res)
+	assert.Equal(t, float64(2), res["total"])
+
+	code, _ = call(t, ts, "DELETE", "/v1/applications/"+id, "")
+	require.Equal(t, http.StatusOK, code)
+
+	// grpc errors are mapped to http status codes
+	code, res = call(t, ts, "GET", "/v1/applications/"+id, "")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "NotFound", res["code"])
+
+	code, res = call(t, ts, "GET", "/v1/applications?page_size=-1", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "InvalidArgument", res["code"])
+
+	code, res = call(t, ts, "POST", "/v1/applications", `{"applications": [{"date": "2024-01-17T10:00:00Z", "company": "ThirdCompany"}], "source": 42}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "InvalidArgument", res["code"])
+
+	code, res = call(t, ts, "GET", "/v1/applications?unknown=1", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, res["message"], "unknown query parameter")
+}
+
+func TestGateway_Watch(t *testing.T) {
+	ts := setup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", ts.URL+"/v1/applications:watch?company=TestCompany", nil)
+	require.NoError(t, err)
+
+	changes := make(chan string)
+	go func() {
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return
+		}
+		defer res.Body.Close()
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			changes <- scanner.Text()
+		}
+	}()
+
+	// Keep creating until the watch started
+	var line string
+	for line == "" {
+		code, res := call(t, ts, "POST", "/v1/applications", `{
+			"applications": [{"date": "2024-01-15T10:00:00Z", "company": "TestCompany"}]
+		}`)
+		require.Equal(t, http.StatusOK, code, res)
+		select {
+		case line = <-changes:
+		default:
+		}
+	}
+
+	var change map[string]any
+	require.NoError(t, json.Unmarshal([]byte(line), &change))
+	result := change["result"].(map[string]any)
+	assert.Equal(t, "CHANGE_CREATED", result["type"])
+	assert.Equal(t, "TestCompany", result["application"].(map[string]any)["company"])
+}
+
+func TestGateway_CORS(t *testing.T) {
+	ts := setup(t, WithAllowedOrigins("https://dashboard.example.com"))
+
+	preflight := func(origin string) *http.Response {
+		req, err := http.NewRequest("OPTIONS", ts.URL+"/v1/applications", nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		return res
+	}
+
+	res := preflight("https://dashboard.example.com")
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, "https://dashboard.example.com", res.Header.Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, res.Header.Get("Access-Control-Allow-Methods"), "PATCH")
+
+	res = preflight("https://evil.example.com")
+	assert.Empty(t, res.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestOpenAPI(t *testing.T) {
+	ts := setup(t)
+
+	res, err := http.Get(ts.URL + "/openapi.json")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	var document struct {
+		Paths      map[string]map[string]map[string]any
+		Components struct {
+			Schemas map[string]map[string]any
+		}
+	}
+	require.NoError(t, json.Unmarshal(b, &document))
+
+	// every rpc is served
+	operations := map[string]bool{}
+	for _, path := range document.Paths {
+		for _, operation := range path {
+			operations[operation["operationId"].(string)] = true
+		}
+	}
+	methods := applicationspb.File_proto_applications_v1_applications_proto.Services().ByName("Applications").Methods()
+	for i := range methods.Len() {
+		assert.True(t, operations[string(methods.Get(i).Name())], methods.Get(i).Name())
+	}
+
+	application := document.Components.Schemas["maxbear.maxhire.Application"]
+	require.NotNil(t, application)
+	properties := application["properties"].(map[string]any)
+	assert.Equal(t, "date-time", properties["date"].(map[string]any)["format"])
+	assert.Contains(t, properties["status"].(map[string]any)["enum"], "GHOSTED")
+}
+
+func TestWriteError(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	rec := httptest.NewRecorder()
+	writeError(context.Background(), logger, rec, errors.New("database is locked"))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, logs.String(), "database is locked")
+
+	// client errors are not logged
+	logs.Reset()
+	rec = httptest.NewRecorder()
+	writeError(context.Background(), logger, rec, status.Error(codes.NotFound, "not found"))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, logs.String())
+}
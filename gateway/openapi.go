@@ -0,0 +1,220 @@
+package gateway
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// wellKnownSchemas are the json representations of the well known types used by the api
+var wellKnownSchemas = map[protoreflect.FullName]map[string]any{
+	"google.protobuf.Timestamp": {"type": "string", "format": "date-time"},
+	"google.protobuf.Duration":  {"type": "string", "description": "Seconds with an s suffix, e.g. 1.5s"},
+	"google.protobuf.FieldMask": {"type": "string", "description": "Comma separated field names"},
+}
+
+func ref(message protoreflect.MessageDescriptor) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + string(message.FullName())}
+}
+
+// fieldSchema returns the schema of the field as encoded by protojson, adding the messages it refers to
+// to schemas
+func fieldSchema(field protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	var schema map[string]any
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		schema = map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		schema = map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64 bit integers are strings in json
+		schema = map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		schema = map[string]any{"type": "number"}
+	case protoreflect.StringKind:
+		schema = map[string]any{"type": "string"}
+	case protoreflect.BytesKind:
+		schema = map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range values.Len() {
+			names[i] = string(values.Get(i).Name())
+		}
+		schema = map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if wellKnown, ok := wellKnownSchemas[field.Message().FullName()]; ok {
+			schema = wellKnown
+		} else {
+			messageSchema(field.Message(), schemas)
+			schema = ref(field.Message())
+		}
+	}
+
+	if field.IsList() {
+		return map[string]any{"type": "array", "items": schema}
+	}
+	return schema
+}
+
+// messageSchema adds the schema of the message and of the messages it refers to to schemas
+func messageSchema(message protoreflect.MessageDescriptor, schemas map[string]any) {
+	name := string(message.FullName())
+	if _, ok := schemas[name]; ok {
+		return
+	}
+	properties := map[string]any{}
+	schema := map[string]any{"type": "object", "properties": properties}
+	// added before the fields, for recursive messages
+	schemas[name] = schema
+
+	fields := message.Fields()
+	for i := range fields.Len() {
+		properties[string(fields.Get(i).Name())] = fieldSchema(fields.Get(i), schemas)
+	}
+}
+
+// queryParameters returns the request fields which can be set in the query string
+func queryParameters(r *route, schemas map[string]any) []any {
+	parameters := []any{}
+	fields := r.input.Fields()
+	for i := range fields.Len() {
+		field := fields.Get(i)
+		name := string(field.Name())
+		if name == r.idField {
+			continue
+		}
+		if field.Kind() == protoreflect.MessageKind {
+			if _, ok := wellKnownSchemas[field.Message().FullName()]; !ok {
+				continue
+			}
+		}
+		parameters = append(parameters, map[string]any{
+			"name":   name,
+			"in":     "query",
+			"schema": fieldSchema(field, schemas),
+		})
+	}
+	return parameters
+}
+
+// openAPI returns the OpenAPI document of the routes, generated from the proto descriptors of their rpcs
+func openAPI(routes []route) map[string]any {
+	schemas := map[string]any{
+		"Error": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"code":    map[string]any{"type": "string", "description": "grpc status code"},
+				"message": map[string]any{"type": "string"},
+			},
+		},
+	}
+	paths := map[string]any{}
+
+	rpcRoutes := map[protoreflect.FullName]int{}
+	for _, r := range routes {
+		rpcRoutes[r.input.FullName()]++
+	}
+
+	for i := range routes {
+		r := &routes[i]
+		messageSchema(r.output, schemas)
+
+		parameters := []any{}
+		if r.idField != "" {
+			parameters = append(parameters, map[string]any{
+				"name":     "id",
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]any{"type": "string"},
+			})
+		}
+
+		// operation ids are the rpc names, suffixed with ById when the rpc is also served without an id
+		name := strings.TrimSuffix(string(r.input.Name()), "Request")
+		if rpcRoutes[r.input.FullName()] > 1 && r.idField != "" {
+			name += "ById"
+		}
+		operation := map[string]any{
+			"operationId": name,
+			"responses": map[string]any{
+				"default": map[string]any{
+					"description": "Error",
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Error"}},
+					},
+				},
+			},
+		}
+
+		switch r.body {
+		case "":
+			parameters = append(parameters, queryParameters(r, schemas)...)
+		case "*":
+			messageSchema(r.input, schemas)
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": ref(r.input)}},
+			}
+		default:
+			field := r.input.Fields().ByName(protoreflect.Name(r.body))
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": fieldSchema(field, schemas)}},
+			}
+			for i := range r.input.Fields().Len() {
+				field := r.input.Fields().Get(i)
+				if field.Name() != protoreflect.Name(r.body) {
+					parameters = append(parameters, map[string]any{
+						"name":   string(field.Name()),
+						"in":     "query",
+						"schema": fieldSchema(field, schemas),
+					})
+				}
+			}
+		}
+		operation["parameters"] = parameters
+
+		responses := operation["responses"].(map[string]any)
+		if r.stream {
+			responses["200"] = map[string]any{
+				"description": "Newline delimited json objects, each holding a message in result, or an error",
+				"content": map[string]any{
+					"application/x-ndjson": map[string]any{"schema": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"result": ref(r.output),
+							"error":  map[string]any{"$ref": "#/components/schemas/Error"},
+						},
+					}},
+				},
+			}
+		} else {
+			responses["200"] = map[string]any{
+				"description": "OK",
+				"content":     map[string]any{"application/json": map[string]any{"schema": ref(r.output)}},
+			}
+		}
+
+		path, ok := paths[r.path].(map[string]any)
+		if !ok {
+			path = map[string]any{}
+			paths[r.path] = path
+		}
+		path[strings.ToLower(r.method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "maxhire applications api",
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+		},
+	}
+}
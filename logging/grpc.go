@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader is the grpc metadata key, and http header, of the request id of a call
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLength limits the request ids accepted from the clients
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose logs have the request id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request id of the context, see WithRequestID
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns id if it can be used as a request id, or a new one
+func NewRequestID(id string) string {
+	if id == "" || len(id) > maxRequestIDLength {
+		return uuid.NewString()
+	}
+	return id
+}
+
+// incomingRequestID returns the request id sent by the client, or a new one
+func incomingRequestID(ctx context.Context) string {
+	var id string
+	if ids := metadata.ValueFromIncomingContext(ctx, RequestIDHeader); len(ids) > 0 {
+		id = ids[0]
+	}
+	return NewRequestID(id)
+}
+
+// level returns the level of the log of a call returning code
+func level(method string, code codes.Code, quiet []string) slog.Level {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable:
+		return slog.LevelError
+	}
+
+	// /package.service/method
+	service, _, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if slices.Contains(quiet, service) {
+		return slog.LevelDebug
+	}
+	return slog.LevelInfo
+}
+
+func logCall(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error, quiet []string) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	logger.LogAttrs(ctx, level(method, code, quiet), "grpc call", attrs...)
+}
+
+// UnaryServerInterceptor logs the calls with the request id sent by the client in the x-request-id
+// metadata, or a new one. The request id is returned in the header of the response, and added to the
+// logs of the context of the call. The calls of the quiet services, e.g. grpc.health.v1.Health, are
+// logged at the debug level unless they fail.
+func UnaryServerInterceptor(logger *slog.Logger, quiet ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := incomingRequestID(ctx)
+		ctx = WithRequestID(ctx, id)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+
+		start := time.Now()
+		res, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, start, err, quiet)
+		return res, err
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls, which are logged when they end
+func StreamServerInterceptor(logger *slog.Logger, quiet ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := incomingRequestID(ss.Context())
+		ctx := WithRequestID(ss.Context(), id)
+		ss.SetHeader(metadata.Pairs(RequestIDHeader, id))
+
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, start, err, quiet)
+		return err
+	}
+}
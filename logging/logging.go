@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keys of the attributes holding email content, their values are redacted unless WithDebug is set
+const (
+	KeyBody      = "body"
+	KeySender    = "sender"
+	KeyApplicant = "applicant"
+)
+
+// Keys of the attributes added from the context of the calls
+const (
+	KeyRequestID = "request_id"
+	KeyTraceID   = "trace_id"
+)
+
+// Redacted replaces the redacted values
+const Redacted = "[REDACTED]"
+
+// Output formats of the logs
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats are the supported output formats
+var Formats = []string{FormatText, FormatJSON}
+
+var emailAddress = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+type options struct {
+	level  slog.Level
+	format string
+	debug  bool
+	names  []string
+}
+
+type LoggerOpt func(*options)
+
+// WithLevel sets the minimum level of the logs, defaults to info
+func WithLevel(level slog.Level) LoggerOpt {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithFormat sets the output format, one of Formats, defaults to FormatText
+func WithFormat(format string) LoggerOpt {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// WithDebug logs email bodies, sender addresses and applicant names as they are
+func WithDebug(debug bool) LoggerOpt {
+	return func(o *options) {
+		o.debug = debug
+	}
+}
+
+// WithApplicantNames sets the names of the applicant, redacted wherever they appear in the logs
+func WithApplicantNames(names ...string) LoggerOpt {
+	return func(o *options) {
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name != "" {
+				o.names = append(o.names, name)
+			}
+		}
+	}
+}
+
+// ParseLevel parses a level name, e.g. debug or warn
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(s))
+	return level, err
+}
+
+// New returns a logger writing to w. Unless WithDebug is set, the values of the KeyBody, KeySender and
+// KeyApplicant attributes are redacted, as are the email addresses and applicant names found in the
+// message and the other string and error values. The request id and trace id of the context are added
+// to the logs.
+func New(w io.Writer, opts ...LoggerOpt) (*slog.Logger, error) {
+	o := &options{format: FormatText}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: o.level}
+	if !o.debug {
+		handlerOpts.ReplaceAttr = newRedactor(o.names).replace
+	}
+
+	var handler slog.Handler
+	switch o.format {
+	case FormatText:
+		handler = slog.NewTextHandler(w, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q, expecting one of: %s", o.format, strings.Join(Formats, ", "))
+	}
+
+	return slog.New(contextHandler{handler}), nil
+}
+
+type redactor struct {
+	names *regexp.Regexp
+}
+
+func newRedactor(names []string) *redactor {
+	r := &redactor{}
+	if len(names) > 0 {
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = regexp.QuoteMeta(name)
+		}
+		r.names = regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+	}
+	return r
+}
+
+func (r *redactor) redact(s string) string {
+	s = emailAddress.ReplaceAllString(s, Redacted)
+	if r.names != nil {
+		s = r.names.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+func (r *redactor) replace(groups []string, a slog.Attr) slog.Attr {
+	switch a.Key {
+	case KeyBody, KeySender, KeyApplicant:
+		return slog.String(a.Key, Redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, r.redact(err.Error()))
+		}
+	}
+	return a
+}
+
+// contextHandler adds the request id and trace id of the context to the records
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String(KeyRequestID, id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String(KeyTraceID, sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
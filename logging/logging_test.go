@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	records := []map[string]any{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		record := map[string]any{}
+		require.NoError(t, dec.Decode(&record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestNew_Redaction(t *testing.T) {
+	log := func(logger *slog.Logger) {
+		logger.With("job", "Jane's application").Info("email from jane.doe@example.com",
+			KeyBody, "Hi Jane, thanks for applying",
+			KeySender, "recruiting@company.com",
+			"subject", "Your application, Jane Doe",
+			"error", errors.New("invalid sender jane.doe@example.com"),
+			"count", 2)
+	}
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, WithFormat(FormatJSON), WithApplicantNames("Jane", " ", "Doe"))
+	require.NoError(t, err)
+	log(logger)
+
+	records := decode(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "email from [REDACTED]", records[0]["msg"])
+	assert.Equal(t, Redacted, records[0][KeyBody])
+	assert.Equal(t, Redacted, records[0][KeySender])
+	assert.Equal(t, "Your application, [REDACTED] [REDACTED]", records[0]["subject"])
+	assert.Equal(t, "invalid sender [REDACTED]", records[0]["error"])
+	assert.Equal(t, "[REDACTED]'s application", records[0]["job"])
+	assert.Equal(t, float64(2), records[0]["count"])
+
+	logger, err = New(&buf, WithFormat(FormatJSON), WithApplicantNames("Jane"), WithDebug(true))
+	require.NoError(t, err)
+	log(logger)
+
+	records = decode(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "email from jane.doe@example.com", records[0]["msg"])
+	assert.Equal(t, "Hi Jane, thanks for applying", records[0][KeyBody])
+	assert.Equal(t, "recruiting@company.com", records[0][KeySender])
+
+	_, err = New(&buf, WithFormat("xml"))
+	assert.Error(t, err)
+}
+
+func TestNew_Level(t *testing.T) {
+	level, err := ParseLevel("warn")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, WithLevel(level))
+	require.NoError(t, err)
+	logger.Info("skipped")
+	logger.Warn("logged")
+	assert.NotContains(t, buf.String(), "skipped")
+	assert.Contains(t, buf.String(), "level=WARN msg=logged")
+
+	_, err = ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, WithFormat(FormatJSON), WithLevel(slog.LevelDebug))
+	require.NoError(t, err)
+	interceptor := UnaryServerInterceptor(logger, "grpc.health.v1.Health")
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		logger.InfoContext(ctx, "handling")
+		return RequestID(ctx), nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "request-1"))
+	res, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/maxbear.maxhire.Applications/GetApplication"}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "request-1", res)
+
+	records := decode(t, &buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, "request-1", records[0][KeyRequestID])
+	assert.Equal(t, "grpc call", records[1]["msg"])
+	assert.Equal(t, "INFO", records[1]["level"])
+	assert.Equal(t, "OK", records[1]["code"])
+	assert.Equal(t, "request-1", records[1][KeyRequestID])
+
+	// a request id is generated when the client sends none
+	res, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	require.NoError(t, err)
+	assert.NotEmpty(t, res)
+	records = decode(t, &buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, res, records[1][KeyRequestID])
+	assert.Equal(t, "DEBUG", records[1]["level"])
+
+	failing := func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.Internal, "failed")
+	}
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, failing)
+	require.Error(t, err)
+	records = decode(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.Equal(t, "failed", records[0]["error"])
+}
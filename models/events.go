@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	applicationspb "github.com/MaxBear/maxhire/proto/gen/go/applications/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type EventType int
+
+const (
+	StatusChanged      EventType = iota // 0
+	InterviewScheduled                  // 1
+	EmailLinked                         // 2
+)
+
+// String method for general printing (fmt.Println)
+func (t EventType) String() string {
+	if t < StatusChanged || t > EmailLinked {
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+	return [...]string{
+		"StatusChanged",
+		"InterviewScheduled",
+		"EmailLinked"}[t]
+}
+
+// EventSource tells what made a change to an application
+type EventSource int
+
+const (
+	SourceManual EventSource = iota // 0
+	SourceLlm                       // 1
+	SourceRule                      // 2
+)
+
+// String method for general printing (fmt.Println)
+func (s EventSource) String() string {
+	if !s.Valid() {
+		return fmt.Sprintf("EventSource(%d)", int(s))
+	}
+	return [...]string{
+		"Manual",
+		"Llm",
+		"Rule"}[s]
+}
+
+// Valid reports whether s is one of the known sources
+func (s EventSource) Valid() bool {
+	return s >= SourceManual && s <= SourceRule
+}
+
+// Event is an entry of the application timeline
+type Event struct {
+	// When it happened, e.g. the sent time of the email which changed the status
+	Time   time.Time   `json:"time"`
+	Type   EventType   `json:"type"`
+	Source EventSource `json:"source"`
+
+	// StatusChanged
+	PreviousStatus gcp.Status `json:"previousStatus"`
+	Status         gcp.Status `json:"status"`
+
+	// InterviewScheduled
+	InterviewType InterviewType `json:"interviewType"`
+	InterviewTime time.Time     `json:"interviewTime"`
+
+	// EmailLinked
+	MessageID string `json:"messageId,omitempty"`
+}
+
+func (e *Event) Pb() *applicationspb.ApplicationEvent {
+	res := &applicationspb.ApplicationEvent{
+		Time:           timestamppb.New(e.Time),
+		Type:           applicationspb.EventType(e.Type),
+		Source:         applicationspb.EventSource(e.Source),
+		PreviousStatus: applicationspb.StatusType(e.PreviousStatus),
+		Status:         applicationspb.StatusType(e.Status),
+		InterviewType:  applicationspb.InterviewType(e.InterviewType),
+		MessageId:      e.MessageID,
+	}
+	if !e.InterviewTime.IsZero() {
+		res.InterviewTime = timestamppb.New(e.InterviewTime)
+	}
+	return res
+}
+
+// IsResponse reports whether the event is the company getting back to the applicant
+func (e *Event) IsResponse() bool {
+	switch e.Type {
+	case StatusChanged:
+		return e.Status.IsResponse()
+	case InterviewScheduled:
+		return true
+	}
+	return false
+}
+
+// RecordChanges appends the events describing how the application changed since before, nil
+// for a new application: a status change and the interviews which were not scheduled before.
+// Events happen at time at, interviews are scheduled at the latest at the time they take place,
+// which is all we know about the interviews of a new application.
+func (application *Application) RecordChanges(before *Application, at time.Time, source EventSource) {
+	if before == nil || before.Status != application.Status {
+		e := Event{
+			Time:   at,
+			Type:   StatusChanged,
+			Source: source,
+			Status: application.Status,
+		}
+		if before != nil {
+			e.PreviousStatus = before.Status
+		}
+		application.Events = append(application.Events, e)
+	}
+
+	for _, interview := range application.Interviews {
+		if before != nil && before.HasInterview(interview) {
+			continue
+		}
+		scheduled := at
+		if before == nil || interview.DateTime.Before(scheduled) {
+			scheduled = interview.DateTime
+		}
+		application.Events = append(application.Events, Event{
+			Time:          scheduled,
+			Type:          InterviewScheduled,
+			Source:        source,
+			InterviewType: interview.InterviewType,
+			InterviewTime: interview.DateTime,
+		})
+	}
+}
+
+// LinkEmail records that the email with the given id is about the application, returns false
+// if it was already linked
+func (application *Application) LinkEmail(messageID string, at time.Time, source EventSource) bool {
+	if messageID == "" {
+		return false
+	}
+	for _, e := range application.Events {
+		if e.Type == EmailLinked && e.MessageID == messageID {
+			return false
+		}
+	}
+	if application.MessageID == "" {
+		application.MessageID = messageID
+	}
+	application.Events = append(application.Events, Event{
+		Time:      at,
+		Type:      EmailLinked,
+		Source:    source,
+		MessageID: messageID,
+	})
+	return true
+}
+
+// HasMessage reports whether the email with the given id is linked to the application
+func (application *Application) HasMessage(messageID string) bool {
+	if messageID == "" {
+		return false
+	}
+	if application.MessageID == messageID {
+		return true
+	}
+	for _, e := range application.Events {
+		if e.Type == EmailLinked && e.MessageID == messageID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasInterview reports whether an interview of the same type at the same time exists
+func (application *Application) HasInterview(interview Interview) bool {
+	for _, existing := range application.Interviews {
+		if existing.DateTime.Equal(interview.DateTime) && existing.InterviewType == interview.InterviewType {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
@@ -10,11 +11,16 @@ import (
 )
 
 type Application struct {
+	ID         string      `json:"id"`
 	Date       time.Time   `json:"date"`
 	Company    string      `json:"company"`
 	Position   string      `json:"position"`
 	Status     gcp.Status  `json:"status"`
 	Interviews []Interview `json:"interviews"`
+	MessageID  string      `json:"messageId,omitempty"` // Id of the source email, used to deduplicate
+	Subject    string      `json:"subject,omitempty"`   // Subject of the source email
+	Events     []Event     `json:"events,omitempty"`    // Timeline of the application, in the order recorded
+	Tenant     string      `json:"tenant,omitempty"`    // User owning the application, empty without authentication
 }
 
 type InterviewType int
@@ -30,6 +36,9 @@ const (
 
 // String method for general printing (fmt.Println)
 func (t InterviewType) String() string {
+	if !t.Valid() {
+		return fmt.Sprintf("InterviewType(%d)", int(t))
+	}
 	return [...]string{
 		"Unspecified",
 		"RecruiterScreen",
@@ -39,6 +48,11 @@ func (t InterviewType) String() string {
 		"TeamMatch"}[t]
 }
 
+// Valid reports whether t is one of the known interview types
+func (t InterviewType) Valid() bool {
+	return t >= Unspecified && t <= TeamMatch
+}
+
 func ParseInterviewType(s string) (InterviewType, error) {
 	statusMap := map[string]InterviewType{
 		"Unspecified":      Unspecified,
@@ -66,13 +80,20 @@ func NewApplication(a *applicationspb.Application) *Application {
 	for _, pbInterview := range a.GetInterviews() {
 		interviews = append(interviews, InterviewFromPb(pbInterview))
 	}
-	return &Application{
-		Date:       a.GetDate().AsTime(),
+	application := &Application{
+		ID:         a.GetId(),
 		Company:    a.GetCompany(),
 		Position:   a.GetPosition(),
 		Status:     gcp.Status(a.GetStatus()),
 		Interviews: interviews,
+		MessageID:  a.GetMessageId(),
+		Subject:    a.GetSubject(),
+	}
+	// Keep the zero time for a missing date so Validate rejects it, AsTime would return the unix epoch
+	if a.GetDate() != nil {
+		application.Date = a.GetDate().AsTime()
 	}
+	return application
 }
 
 func InterviewFromPb(pb *applicationspb.Interview) Interview {
@@ -103,6 +124,14 @@ func (application *Application) Validate() error {
 	if application.Company == "" {
 		return fmt.Errorf("invalid company name")
 	}
+	if !application.Status.Valid() {
+		return fmt.Errorf("invalid status %d", application.Status)
+	}
+	for _, interview := range application.Interviews {
+		if !interview.InterviewType.Valid() {
+			return fmt.Errorf("invalid interview type %d", interview.InterviewType)
+		}
+	}
 	return nil
 }
 
@@ -112,20 +141,134 @@ func (application *Application) Pb() *applicationspb.Application {
 		interviews = append(interviews, interview.Pb())
 	}
 	res := &applicationspb.Application{
+		Id:         application.ID,
 		Date:       timestamppb.New(application.Date),
 		Company:    application.Company,
 		Position:   application.Position,
 		Status:     applicationspb.StatusType(application.Status),
 		Interviews: interviews,
+		MessageId:  application.MessageID,
+		Subject:    application.Subject,
 	}
 	return res
 }
 
+// LastActivity is the time of the latest thing that happened to the application
+func (application *Application) LastActivity() time.Time {
+	last := application.Date
+	for _, interview := range application.Interviews {
+		if interview.DateTime.After(last) {
+			last = interview.DateTime
+		}
+	}
+	for _, e := range application.Events {
+		if e.Time.After(last) {
+			last = e.Time
+		}
+	}
+	return last
+}
+
+// Responded reports whether the company got back to the applicant, with a decision or an interview
+func (application *Application) Responded() bool {
+	return application.Status.IsResponse() || len(application.Interviews) > 0
+}
+
+// FirstResponse returns the time of the first response received after applying, from the
+// timeline. Applications stored before timelines were recorded fall back to the earliest interview.
+func (application *Application) FirstResponse() (time.Time, bool) {
+	var first time.Time
+	if len(application.Events) > 0 {
+		for _, e := range application.Events {
+			// a record created with a decision is the response itself, not the application
+			if !e.IsResponse() || !e.Time.After(application.Date) {
+				continue
+			}
+			if first.IsZero() || e.Time.Before(first) {
+				first = e.Time
+			}
+		}
+		return first, !first.IsZero()
+	}
+
+	for _, interview := range application.Interviews {
+		if first.IsZero() || interview.DateTime.Before(first) {
+			first = interview.DateTime
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// Clone returns a deep copy of the application, so callers can modify it without
+// affecting the stored record.
+func (application *Application) Clone() *Application {
+	res := *application
+	res.Interviews = make([]Interview, len(application.Interviews))
+	copy(res.Interviews, application.Interviews)
+	if application.Events != nil {
+		res.Events = make([]Event, len(application.Events))
+		copy(res.Events, application.Events)
+	}
+	return &res
+}
+
+// SameAs reports whether both records describe the same application: other comes from an
+// email linked to the application, or they have the same company and position and dates at
+// most window apart.
+func (application *Application) SameAs(other *Application, window time.Duration) bool {
+	if application.HasMessage(other.MessageID) {
+		return true
+	}
+	return application.SamePosition(other) && application.Date.Sub(other.Date).Abs() <= window
+}
+
+// SamePosition reports whether both records have the same company and position, case-insensitive
+func (application *Application) SamePosition(other *Application) bool {
+	return strings.EqualFold(strings.TrimSpace(application.Company), strings.TrimSpace(other.Company)) &&
+		strings.EqualFold(strings.TrimSpace(application.Position), strings.TrimSpace(other.Position))
+}
+
+// Merge copies the fields set in other into the application and reports whether anything changed.
+// The earliest date is kept, the status is only taken if it is not Pending and interviews are
+// added unless one with the same time and type already exists. The source email of other is
+// not linked, see LinkEmail.
+func (application *Application) Merge(other *Application) bool {
+	changed := false
+
+	if !other.Date.IsZero() && other.Date.Before(application.Date) {
+		application.Date = other.Date
+		changed = true
+	}
+	if other.Position != "" && other.Position != application.Position {
+		application.Position = other.Position
+		changed = true
+	}
+	if other.Status != gcp.Pending && other.Status != application.Status {
+		application.Status = other.Status
+		changed = true
+	}
+	if other.Subject != "" && application.Subject == "" {
+		application.Subject = other.Subject
+		changed = true
+	}
+
+	for _, interview := range other.Interviews {
+		if !application.HasInterview(interview) {
+			application.Interviews = append(application.Interviews, interview)
+			changed = true
+		}
+	}
+
+	return changed
+}
+
 func ToApplication(email *gcp.Email) *Application {
 	return &Application{
-		Date:     email.EmailRecord.SentTime,
-		Company:  email.Company,
-		Position: email.Position,
-		Status:   email.Status,
+		Date:      email.EmailRecord.SentTime,
+		Company:   email.Company,
+		Position:  email.Position,
+		Status:    email.Status,
+		MessageID: email.EmailRecord.MessageId,
+		Subject:   email.EmailRecord.Subject,
 	}
 }
@@ -119,3 +119,21 @@ func TestNewApplication_WithInterviews(t *testing.T) {
 	assert.Equal(t, int32(15), application.Interviews[0].DurationMin, "first interview should default to 15 minutes")
 	assert.Equal(t, int32(60), application.Interviews[1].DurationMin, "second interview should use explicit value")
 }
+
+func TestEventSource(t *testing.T) {
+	assert.True(t, SourceRule.Valid())
+	assert.Equal(t, "Rule", SourceRule.String())
+	assert.False(t, EventSource(42).Valid())
+	assert.Equal(t, "EventSource(42)", EventSource(42).String())
+	assert.False(t, EventSource(-1).Valid())
+	assert.Equal(t, "EventType(42)", EventType(42).String())
+}
+
+func TestInterviewType(t *testing.T) {
+	assert.True(t, TeamMatch.Valid())
+	assert.Equal(t, "TeamMatch", TeamMatch.String())
+	assert.False(t, InterviewType(42).Valid())
+	assert.Equal(t, "InterviewType(42)", InterviewType(42).String())
+	assert.False(t, InterviewType(-1).Valid())
+	assert.Equal(t, "InterviewType(-1)", InterviewType(-1).String())
+}
@@ -9,6 +9,8 @@ package applicationspb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -25,10 +27,16 @@ const (
 type StatusType int32
 
 const (
-	StatusType_PENDING StatusType = 0 // Must be the first element and 0
-	StatusType_REJECT  StatusType = 1
-	StatusType_SUCCESS StatusType = 2
-	StatusType_APPLIED StatusType = 3
+	StatusType_PENDING        StatusType = 0 // Must be the first element and 0
+	StatusType_REJECT         StatusType = 1
+	StatusType_SUCCESS        StatusType = 2
+	StatusType_APPLIED        StatusType = 3
+	StatusType_INTERVIEWING   StatusType = 4
+	StatusType_OFFER          StatusType = 5
+	StatusType_OFFER_ACCEPTED StatusType = 6
+	StatusType_OFFER_DECLINED StatusType = 7
+	StatusType_WITHDRAWN      StatusType = 8
+	StatusType_GHOSTED        StatusType = 9
 )
 
 // Enum value maps for StatusType.
@@ -38,12 +46,24 @@ var (
 		1: "REJECT",
 		2: "SUCCESS",
 		3: "APPLIED",
+		4: "INTERVIEWING",
+		5: "OFFER",
+		6: "OFFER_ACCEPTED",
+		7: "OFFER_DECLINED",
+		8: "WITHDRAWN",
+		9: "GHOSTED",
 	}
 	StatusType_value = map[string]int32{
-		"PENDING": 0,
-		"REJECT":  1,
-		"SUCCESS": 2,
-		"APPLIED": 3,
+		"PENDING":        0,
+		"REJECT":         1,
+		"SUCCESS":        2,
+		"APPLIED":        3,
+		"INTERVIEWING":   4,
+		"OFFER":          5,
+		"OFFER_ACCEPTED": 6,
+		"OFFER_DECLINED": 7,
+		"WITHDRAWN":      8,
+		"GHOSTED":        9,
 	}
 )
 
@@ -132,11 +152,214 @@ func (InterviewType) EnumDescriptor() ([]byte, []int) {
 	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{1}
 }
 
+type EventType int32
+
+const (
+	EventType_EVENT_STATUS_CHANGED      EventType = 0 // Must be the first element and 0
+	EventType_EVENT_INTERVIEW_SCHEDULED EventType = 1
+	EventType_EVENT_EMAIL_LINKED        EventType = 2
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_STATUS_CHANGED",
+		1: "EVENT_INTERVIEW_SCHEDULED",
+		2: "EVENT_EMAIL_LINKED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_STATUS_CHANGED":      0,
+		"EVENT_INTERVIEW_SCHEDULED": 1,
+		"EVENT_EMAIL_LINKED":        2,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_applications_v1_applications_proto_enumTypes[2].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_proto_applications_v1_applications_proto_enumTypes[2]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{2}
+}
+
+// What made a change to an application
+type EventSource int32
+
+const (
+	EventSource_SOURCE_MANUAL EventSource = 0 // Must be the first element and 0
+	EventSource_SOURCE_LLM    EventSource = 1
+	EventSource_SOURCE_RULE   EventSource = 2
+)
+
+// Enum value maps for EventSource.
+var (
+	EventSource_name = map[int32]string{
+		0: "SOURCE_MANUAL",
+		1: "SOURCE_LLM",
+		2: "SOURCE_RULE",
+	}
+	EventSource_value = map[string]int32{
+		"SOURCE_MANUAL": 0,
+		"SOURCE_LLM":    1,
+		"SOURCE_RULE":   2,
+	}
+)
+
+func (x EventSource) Enum() *EventSource {
+	p := new(EventSource)
+	*p = x
+	return p
+}
+
+func (x EventSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_applications_v1_applications_proto_enumTypes[3].Descriptor()
+}
+
+func (EventSource) Type() protoreflect.EnumType {
+	return &file_proto_applications_v1_applications_proto_enumTypes[3]
+}
+
+func (x EventSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventSource.Descriptor instead.
+func (EventSource) EnumDescriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{3}
+}
+
+type SetApplicationResultType int32
+
+const (
+	SetApplicationResultType_RESULT_UNSPECIFIED SetApplicationResultType = 0 // Must be the first element and 0
+	SetApplicationResultType_RESULT_CREATED     SetApplicationResultType = 1
+	SetApplicationResultType_RESULT_UPDATED     SetApplicationResultType = 2
+	SetApplicationResultType_RESULT_UNCHANGED   SetApplicationResultType = 3
+	SetApplicationResultType_RESULT_REJECTED    SetApplicationResultType = 4
+)
+
+// Enum value maps for SetApplicationResultType.
+var (
+	SetApplicationResultType_name = map[int32]string{
+		0: "RESULT_UNSPECIFIED",
+		1: "RESULT_CREATED",
+		2: "RESULT_UPDATED",
+		3: "RESULT_UNCHANGED",
+		4: "RESULT_REJECTED",
+	}
+	SetApplicationResultType_value = map[string]int32{
+		"RESULT_UNSPECIFIED": 0,
+		"RESULT_CREATED":     1,
+		"RESULT_UPDATED":     2,
+		"RESULT_UNCHANGED":   3,
+		"RESULT_REJECTED":    4,
+	}
+)
+
+func (x SetApplicationResultType) Enum() *SetApplicationResultType {
+	p := new(SetApplicationResultType)
+	*p = x
+	return p
+}
+
+func (x SetApplicationResultType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SetApplicationResultType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_applications_v1_applications_proto_enumTypes[4].Descriptor()
+}
+
+func (SetApplicationResultType) Type() protoreflect.EnumType {
+	return &file_proto_applications_v1_applications_proto_enumTypes[4]
+}
+
+func (x SetApplicationResultType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SetApplicationResultType.Descriptor instead.
+func (SetApplicationResultType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{4}
+}
+
+type ChangeType int32
+
+const (
+	ChangeType_CHANGE_CREATED ChangeType = 0 // Must be the first element and 0
+	ChangeType_CHANGE_UPDATED ChangeType = 1
+	ChangeType_CHANGE_DELETED ChangeType = 2
+)
+
+// Enum value maps for ChangeType.
+var (
+	ChangeType_name = map[int32]string{
+		0: "CHANGE_CREATED",
+		1: "CHANGE_UPDATED",
+		2: "CHANGE_DELETED",
+	}
+	ChangeType_value = map[string]int32{
+		"CHANGE_CREATED": 0,
+		"CHANGE_UPDATED": 1,
+		"CHANGE_DELETED": 2,
+	}
+)
+
+func (x ChangeType) Enum() *ChangeType {
+	p := new(ChangeType)
+	*p = x
+	return p
+}
+
+func (x ChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_applications_v1_applications_proto_enumTypes[5].Descriptor()
+}
+
+func (ChangeType) Type() protoreflect.EnumType {
+	return &file_proto_applications_v1_applications_proto_enumTypes[5]
+}
+
+func (x ChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeType.Descriptor instead.
+func (ChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{5}
+}
+
 type Interview struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Datetime      *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=datetime,proto3" json:"datetime,omitempty"`
 	InterviewType InterviewType          `protobuf:"varint,2,opt,name=interview_type,json=interviewType,proto3,enum=maxbear.maxhire.InterviewType" json:"interview_type,omitempty"`
-	DurationMin   int32                  `protobuf:"varint,3,opt,name=duration_min,json=durationMin,proto3" json:"duration_min,omitempty"`
+	DurationMin   int32                  `protobuf:"varint,3,opt,name=duration_min,json=durationMin,proto3" json:"duration_min,omitempty"` // Duration in minutes, default is 15 if not specified
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -199,6 +422,9 @@ type Application struct {
 	Position      string                 `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
 	Status        StatusType             `protobuf:"varint,4,opt,name=status,proto3,enum=maxbear.maxhire.StatusType" json:"status,omitempty"`
 	Interviews    []*Interview           `protobuf:"bytes,5,rep,name=interviews,proto3" json:"interviews,omitempty"`
+	Id            string                 `protobuf:"bytes,6,opt,name=id,proto3" json:"id,omitempty"`                                // Assigned by the server, ignored by SetApplications
+	MessageId     string                 `protobuf:"bytes,7,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // Id of the source email, used to deduplicate applications
+	Subject       string                 `protobuf:"bytes,8,opt,name=subject,proto3" json:"subject,omitempty"`                      // Subject of the source email
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -268,9 +494,32 @@ func (x *Application) GetInterviews() []*Interview {
 	return nil
 }
 
+func (x *Application) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Application) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *Application) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
 type SetApplicationsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Applications  []*Application         `protobuf:"bytes,1,rep,name=applications,proto3" json:"applications,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Applications []*Application         `protobuf:"bytes,1,rep,name=applications,proto3" json:"applications,omitempty"`
+	// Recorded in the timeline of the created and updated applications
+	Source        EventSource `protobuf:"varint,2,opt,name=source,proto3,enum=maxbear.maxhire.EventSource" json:"source,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -312,27 +561,40 @@ func (x *SetApplicationsRequest) GetApplications() []*Application {
 	return nil
 }
 
-type ApplicationsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Applications  []*Application         `protobuf:"bytes,1,rep,name=applications,proto3" json:"applications,omitempty"`
+func (x *SetApplicationsRequest) GetSource() EventSource {
+	if x != nil {
+		return x.Source
+	}
+	return EventSource_SOURCE_MANUAL
+}
+
+type SetApplicationResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Index of the record in SetApplicationsRequest.applications
+	Index  int32                    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Result SetApplicationResultType `protobuf:"varint,2,opt,name=result,proto3,enum=maxbear.maxhire.SetApplicationResultType" json:"result,omitempty"`
+	// Why the record was rejected
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// The stored application, unset for rejected records
+	Application   *Application `protobuf:"bytes,4,opt,name=application,proto3" json:"application,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ApplicationsResponse) Reset() {
-	*x = ApplicationsResponse{}
+func (x *SetApplicationResult) Reset() {
+	*x = SetApplicationResult{}
 	mi := &file_proto_applications_v1_applications_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ApplicationsResponse) String() string {
+func (x *SetApplicationResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ApplicationsResponse) ProtoMessage() {}
+func (*SetApplicationResult) ProtoMessage() {}
 
-func (x *ApplicationsResponse) ProtoReflect() protoreflect.Message {
+func (x *SetApplicationResult) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_applications_v1_applications_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -344,48 +606,63 @@ func (x *ApplicationsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ApplicationsResponse.ProtoReflect.Descriptor instead.
-func (*ApplicationsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetApplicationResult.ProtoReflect.Descriptor instead.
+func (*SetApplicationResult) Descriptor() ([]byte, []int) {
 	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ApplicationsResponse) GetApplications() []*Application {
+func (x *SetApplicationResult) GetIndex() int32 {
 	if x != nil {
-		return x.Applications
+		return x.Index
+	}
+	return 0
+}
+
+func (x *SetApplicationResult) GetResult() SetApplicationResultType {
+	if x != nil {
+		return x.Result
+	}
+	return SetApplicationResultType_RESULT_UNSPECIFIED
+}
+
+func (x *SetApplicationResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SetApplicationResult) GetApplication() *Application {
+	if x != nil {
+		return x.Application
 	}
 	return nil
 }
 
-type ListApplicationsRequest struct {
+type SetApplicationsResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Optional filter by application status type
-	Status StatusType `protobuf:"varint,1,opt,name=status,proto3,enum=maxbear.maxhire.StatusType" json:"status,omitempty"`
-	// Optional filter by date range
-	// If start_date is provided, only applications on or after this date are returned
-	StartDate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
-	// Optional filter by date range
-	// If end_date is provided, only applications on or before this date are returned
-	EndDate *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
-	// Optional filter by company name (exact match)
-	Company       string `protobuf:"bytes,4,opt,name=company,proto3" json:"company,omitempty"`
+	// The stored applications, in request order, rejected records are omitted
+	Applications []*Application `protobuf:"bytes,1,rep,name=applications,proto3" json:"applications,omitempty"`
+	// One result per record in the request
+	Results       []*SetApplicationResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListApplicationsRequest) Reset() {
-	*x = ListApplicationsRequest{}
+func (x *SetApplicationsResponse) Reset() {
+	*x = SetApplicationsResponse{}
 	mi := &file_proto_applications_v1_applications_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListApplicationsRequest) String() string {
+func (x *SetApplicationsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListApplicationsRequest) ProtoMessage() {}
+func (*SetApplicationsResponse) ProtoMessage() {}
 
-func (x *ListApplicationsRequest) ProtoReflect() protoreflect.Message {
+func (x *SetApplicationsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_applications_v1_applications_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -397,65 +674,53 @@ func (x *ListApplicationsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListApplicationsRequest.ProtoReflect.Descriptor instead.
-func (*ListApplicationsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetApplicationsResponse.ProtoReflect.Descriptor instead.
+func (*SetApplicationsResponse) Descriptor() ([]byte, []int) {
 	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ListApplicationsRequest) GetStatus() StatusType {
-	if x != nil {
-		return x.Status
-	}
-	return StatusType_PENDING
-}
-
-func (x *ListApplicationsRequest) GetStartDate() *timestamppb.Timestamp {
+func (x *SetApplicationsResponse) GetApplications() []*Application {
 	if x != nil {
-		return x.StartDate
+		return x.Applications
 	}
 	return nil
 }
 
-func (x *ListApplicationsRequest) GetEndDate() *timestamppb.Timestamp {
+func (x *SetApplicationsResponse) GetResults() []*SetApplicationResult {
 	if x != nil {
-		return x.EndDate
+		return x.Results
 	}
 	return nil
 }
 
-func (x *ListApplicationsRequest) GetCompany() string {
-	if x != nil {
-		return x.Company
-	}
-	return ""
-}
-
-type SetInterviewsRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Date to identify the application
-	Date *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
-	// Company name to identify the application
-	Company string `protobuf:"bytes,2,opt,name=company,proto3" json:"company,omitempty"`
-	// Interviews to set for the application (replaces existing interviews)
-	Interviews    []*Interview `protobuf:"bytes,3,rep,name=interviews,proto3" json:"interviews,omitempty"`
+type ApplicationsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Applications []*Application         `protobuf:"bytes,1,rep,name=applications,proto3" json:"applications,omitempty"`
+	// Token to retrieve the next page with ListApplicationsRequest.page_token, empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of applications matching the ListApplications filters across all pages
+	TotalSize int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	// Revision of the applications when they were listed, WatchApplicationsRequest.from_revision
+	// to watch the changes made afterwards
+	Revision      int64 `protobuf:"varint,4,opt,name=revision,proto3" json:"revision,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetInterviewsRequest) Reset() {
-	*x = SetInterviewsRequest{}
+func (x *ApplicationsResponse) Reset() {
+	*x = ApplicationsResponse{}
 	mi := &file_proto_applications_v1_applications_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetInterviewsRequest) String() string {
+func (x *ApplicationsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetInterviewsRequest) ProtoMessage() {}
+func (*ApplicationsResponse) ProtoMessage() {}
 
-func (x *SetInterviewsRequest) ProtoReflect() protoreflect.Message {
+func (x *ApplicationsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_applications_v1_applications_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -467,54 +732,88 @@ func (x *SetInterviewsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetInterviewsRequest.ProtoReflect.Descriptor instead.
-func (*SetInterviewsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ApplicationsResponse.ProtoReflect.Descriptor instead.
+func (*ApplicationsResponse) Descriptor() ([]byte, []int) {
 	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *SetInterviewsRequest) GetDate() *timestamppb.Timestamp {
+func (x *ApplicationsResponse) GetApplications() []*Application {
 	if x != nil {
-		return x.Date
+		return x.Applications
 	}
 	return nil
 }
 
-func (x *SetInterviewsRequest) GetCompany() string {
+func (x *ApplicationsResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Company
+		return x.NextPageToken
 	}
 	return ""
 }
 
-func (x *SetInterviewsRequest) GetInterviews() []*Interview {
+func (x *ApplicationsResponse) GetTotalSize() int32 {
 	if x != nil {
-		return x.Interviews
+		return x.TotalSize
 	}
-	return nil
+	return 0
 }
 
-type SetInterviewsResponse struct {
+func (x *ApplicationsResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type ListApplicationsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The updated application with the set interviews
-	Application   *Application `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// Optional filter by application status type, only applied for statuses other than PENDING
+	// since PENDING can't be told apart from unset, use statuses instead
+	Status StatusType `protobuf:"varint,1,opt,name=status,proto3,enum=maxbear.maxhire.StatusType" json:"status,omitempty"`
+	// Optional filter by date range
+	// If start_date is provided, only applications on or after this date are returned
+	StartDate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// Optional filter by date range
+	// If end_date is provided, only applications on or before this date are returned
+	EndDate *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// Optional filter by company name (case-insensitive match)
+	Company string `protobuf:"bytes,4,opt,name=company,proto3" json:"company,omitempty"`
+	// Maximum number of applications to return (at most 1000), 100 if 0
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous response, the other fields must not change between pages
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Sort order: "date", "company", "status" or "last_activity", optionally followed by " desc"
+	// Applications are returned in the order they were added if empty
+	OrderBy string `protobuf:"bytes,7,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Optional filter by any of the application status types
+	Statuses []StatusType `protobuf:"varint,8,rep,packed,name=statuses,proto3,enum=maxbear.maxhire.StatusType" json:"statuses,omitempty"`
+	// Match company as a prefix of the company name instead of the whole name
+	CompanyPrefix bool `protobuf:"varint,9,opt,name=company_prefix,json=companyPrefix,proto3" json:"company_prefix,omitempty"`
+	// Optional free text search, case-insensitive, every word must appear in the position or the
+	// subject of the source email
+	Query string `protobuf:"bytes,10,opt,name=query,proto3" json:"query,omitempty"`
+	// Optional filter on whether applications have interviews
+	HasInterviews *bool `protobuf:"varint,11,opt,name=has_interviews,json=hasInterviews,proto3,oneof" json:"has_interviews,omitempty"`
+	// Optional filter by applications having an interview of any of these types
+	InterviewTypes []InterviewType `protobuf:"varint,12,rep,packed,name=interview_types,json=interviewTypes,proto3,enum=maxbear.maxhire.InterviewType" json:"interview_types,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *SetInterviewsResponse) Reset() {
-	*x = SetInterviewsResponse{}
+func (x *ListApplicationsRequest) Reset() {
+	*x = ListApplicationsRequest{}
 	mi := &file_proto_applications_v1_applications_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetInterviewsResponse) String() string {
+func (x *ListApplicationsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetInterviewsResponse) ProtoMessage() {}
+func (*ListApplicationsRequest) ProtoMessage() {}
 
-func (x *SetInterviewsResponse) ProtoReflect() protoreflect.Message {
+func (x *ListApplicationsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_applications_v1_applications_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -526,27 +825,1349 @@ func (x *SetInterviewsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetInterviewsResponse.ProtoReflect.Descriptor instead.
-func (*SetInterviewsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListApplicationsRequest.ProtoReflect.Descriptor instead.
+func (*ListApplicationsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *SetInterviewsResponse) GetApplication() *Application {
+func (x *ListApplicationsRequest) GetStatus() StatusType {
 	if x != nil {
-		return x.Application
+		return x.Status
 	}
-	return nil
+	return StatusType_PENDING
 }
 
-var File_proto_applications_v1_applications_proto protoreflect.FileDescriptor
+func (x *ListApplicationsRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *ListApplicationsRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *ListApplicationsRequest) GetCompany() string {
+	if x != nil {
+		return x.Company
+	}
+	return ""
+}
+
+func (x *ListApplicationsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListApplicationsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListApplicationsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListApplicationsRequest) GetStatuses() []StatusType {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListApplicationsRequest) GetCompanyPrefix() bool {
+	if x != nil {
+		return x.CompanyPrefix
+	}
+	return false
+}
+
+func (x *ListApplicationsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListApplicationsRequest) GetHasInterviews() bool {
+	if x != nil && x.HasInterviews != nil {
+		return *x.HasInterviews
+	}
+	return false
+}
+
+func (x *ListApplicationsRequest) GetInterviewTypes() []InterviewType {
+	if x != nil {
+		return x.InterviewTypes
+	}
+	return nil
+}
+
+type SetInterviewsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Id to identify the application, date and company are ignored if set
+	Id string `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	// Date to identify the application
+	Date *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// Company name to identify the application
+	Company string `protobuf:"bytes,2,opt,name=company,proto3" json:"company,omitempty"`
+	// Interviews to set for the application (replaces existing interviews)
+	Interviews []*Interview `protobuf:"bytes,3,rep,name=interviews,proto3" json:"interviews,omitempty"`
+	// Recorded in the timeline of the application
+	Source        EventSource `protobuf:"varint,5,opt,name=source,proto3,enum=maxbear.maxhire.EventSource" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetInterviewsRequest) Reset() {
+	*x = SetInterviewsRequest{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetInterviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetInterviewsRequest) ProtoMessage() {}
+
+func (x *SetInterviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetInterviewsRequest.ProtoReflect.Descriptor instead.
+func (*SetInterviewsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SetInterviewsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetInterviewsRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *SetInterviewsRequest) GetCompany() string {
+	if x != nil {
+		return x.Company
+	}
+	return ""
+}
+
+func (x *SetInterviewsRequest) GetInterviews() []*Interview {
+	if x != nil {
+		return x.Interviews
+	}
+	return nil
+}
+
+func (x *SetInterviewsRequest) GetSource() EventSource {
+	if x != nil {
+		return x.Source
+	}
+	return EventSource_SOURCE_MANUAL
+}
+
+type SetInterviewsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated application with the set interviews
+	Application   *Application `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetInterviewsResponse) Reset() {
+	*x = SetInterviewsResponse{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetInterviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetInterviewsResponse) ProtoMessage() {}
+
+func (x *SetInterviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetInterviewsResponse.ProtoReflect.Descriptor instead.
+func (*SetInterviewsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetInterviewsResponse) GetApplication() *Application {
+	if x != nil {
+		return x.Application
+	}
+	return nil
+}
+
+type GetApplicationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetApplicationRequest) Reset() {
+	*x = GetApplicationRequest{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetApplicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetApplicationRequest) ProtoMessage() {}
+
+func (x *GetApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetApplicationRequest.ProtoReflect.Descriptor instead.
+func (*GetApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetApplicationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetApplicationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Application   *Application           `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetApplicationResponse) Reset() {
+	*x = GetApplicationResponse{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetApplicationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetApplicationResponse) ProtoMessage() {}
+
+func (x *GetApplicationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetApplicationResponse.ProtoReflect.Descriptor instead.
+func (*GetApplicationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetApplicationResponse) GetApplication() *Application {
+	if x != nil {
+		return x.Application
+	}
+	return nil
+}
+
+type UpdateApplicationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The application to update, identified by its id
+	Application *Application `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
+	// Fields to update: date, company, position, status, interviews
+	// All of them are updated if the mask is empty
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Recorded in the timeline of the application
+	Source        EventSource `protobuf:"varint,3,opt,name=source,proto3,enum=maxbear.maxhire.EventSource" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateApplicationRequest) Reset() {
+	*x = UpdateApplicationRequest{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateApplicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateApplicationRequest) ProtoMessage() {}
+
+func (x *UpdateApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateApplicationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateApplicationRequest) GetApplication() *Application {
+	if x != nil {
+		return x.Application
+	}
+	return nil
+}
+
+func (x *UpdateApplicationRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateApplicationRequest) GetSource() EventSource {
+	if x != nil {
+		return x.Source
+	}
+	return EventSource_SOURCE_MANUAL
+}
+
+type UpdateApplicationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The application after the update
+	Application   *Application `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateApplicationResponse) Reset() {
+	*x = UpdateApplicationResponse{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateApplicationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateApplicationResponse) ProtoMessage() {}
+
+func (x *UpdateApplicationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateApplicationResponse.ProtoReflect.Descriptor instead.
+func (*UpdateApplicationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateApplicationResponse) GetApplication() *Application {
+	if x != nil {
+		return x.Application
+	}
+	return nil
+}
+
+type DeleteApplicationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteApplicationRequest) Reset() {
+	*x = DeleteApplicationRequest{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteApplicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteApplicationRequest) ProtoMessage() {}
+
+func (x *DeleteApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteApplicationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteApplicationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteApplicationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteApplicationResponse) Reset() {
+	*x = DeleteApplicationResponse{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteApplicationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteApplicationResponse) ProtoMessage() {}
+
+func (x *DeleteApplicationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteApplicationResponse.ProtoReflect.Descriptor instead.
+func (*DeleteApplicationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{14}
+}
+
+type GetStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional date range, only applications sent on or after start_date
+	// and on or before end_date are aggregated
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetStatsRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *GetStatsRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+type StatusCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        StatusType             `protobuf:"varint,1,opt,name=status,proto3,enum=maxbear.maxhire.StatusType" json:"status,omitempty"`
+	Applications  int32                  `protobuf:"varint,2,opt,name=applications,proto3" json:"applications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusCount) Reset() {
+	*x = StatusCount{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusCount) ProtoMessage() {}
+
+func (x *StatusCount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusCount.ProtoReflect.Descriptor instead.
+func (*StatusCount) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StatusCount) GetStatus() StatusType {
+	if x != nil {
+		return x.Status
+	}
+	return StatusType_PENDING
+}
+
+func (x *StatusCount) GetApplications() int32 {
+	if x != nil {
+		return x.Applications
+	}
+	return 0
+}
+
+type InterviewTypeStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InterviewType InterviewType          `protobuf:"varint,1,opt,name=interview_type,json=interviewType,proto3,enum=maxbear.maxhire.InterviewType" json:"interview_type,omitempty"`
+	// Applications with at least one interview of this type
+	Applications int32 `protobuf:"varint,2,opt,name=applications,proto3" json:"applications,omitempty"`
+	// Share of all applications reaching an interview of this type
+	ConversionRate float64 `protobuf:"fixed64,3,opt,name=conversion_rate,json=conversionRate,proto3" json:"conversion_rate,omitempty"`
+	// Share of the applications reaching an interview of this type which reached SUCCESS or OFFER_ACCEPTED
+	SuccessRate   float64 `protobuf:"fixed64,4,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InterviewTypeStats) Reset() {
+	*x = InterviewTypeStats{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InterviewTypeStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InterviewTypeStats) ProtoMessage() {}
+
+func (x *InterviewTypeStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InterviewTypeStats.ProtoReflect.Descriptor instead.
+func (*InterviewTypeStats) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *InterviewTypeStats) GetInterviewType() InterviewType {
+	if x != nil {
+		return x.InterviewType
+	}
+	return InterviewType_UNSPECIFIED
+}
+
+func (x *InterviewTypeStats) GetApplications() int32 {
+	if x != nil {
+		return x.Applications
+	}
+	return 0
+}
+
+func (x *InterviewTypeStats) GetConversionRate() float64 {
+	if x != nil {
+		return x.ConversionRate
+	}
+	return 0
+}
+
+func (x *InterviewTypeStats) GetSuccessRate() float64 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+type WeeklyVolume struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Monday 00:00 UTC of the week
+	WeekStart     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=week_start,json=weekStart,proto3" json:"week_start,omitempty"`
+	Applications  int32                  `protobuf:"varint,2,opt,name=applications,proto3" json:"applications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeeklyVolume) Reset() {
+	*x = WeeklyVolume{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeeklyVolume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeeklyVolume) ProtoMessage() {}
+
+func (x *WeeklyVolume) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeeklyVolume.ProtoReflect.Descriptor instead.
+func (*WeeklyVolume) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WeeklyVolume) GetWeekStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WeekStart
+	}
+	return nil
+}
+
+func (x *WeeklyVolume) GetApplications() int32 {
+	if x != nil {
+		return x.Applications
+	}
+	return 0
+}
+
+type FunnelStage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of applied, responded, interviewed, offer, accepted
+	Stage         string `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Applications  int32  `protobuf:"varint,2,opt,name=applications,proto3" json:"applications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FunnelStage) Reset() {
+	*x = FunnelStage{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FunnelStage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FunnelStage) ProtoMessage() {}
+
+func (x *FunnelStage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FunnelStage.ProtoReflect.Descriptor instead.
+func (*FunnelStage) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FunnelStage) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *FunnelStage) GetApplications() int32 {
+	if x != nil {
+		return x.Applications
+	}
+	return 0
+}
+
+type ResponseTimeBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Response times up to this bound, unset for the last bucket
+	UpperBound    *durationpb.Duration `protobuf:"bytes,1,opt,name=upper_bound,json=upperBound,proto3" json:"upper_bound,omitempty"`
+	Applications  int32                `protobuf:"varint,2,opt,name=applications,proto3" json:"applications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseTimeBucket) Reset() {
+	*x = ResponseTimeBucket{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseTimeBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseTimeBucket) ProtoMessage() {}
+
+func (x *ResponseTimeBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseTimeBucket.ProtoReflect.Descriptor instead.
+func (*ResponseTimeBucket) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ResponseTimeBucket) GetUpperBound() *durationpb.Duration {
+	if x != nil {
+		return x.UpperBound
+	}
+	return nil
+}
+
+func (x *ResponseTimeBucket) GetApplications() int32 {
+	if x != nil {
+		return x.Applications
+	}
+	return 0
+}
+
+type GetStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Total int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	// One entry per status type, including the ones without applications
+	StatusCounts []*StatusCount `protobuf:"bytes,2,rep,name=status_counts,json=statusCounts,proto3" json:"status_counts,omitempty"`
+	// Applications which got a decision or an interview
+	Responses    int32   `protobuf:"varint,3,opt,name=responses,proto3" json:"responses,omitempty"`
+	ResponseRate float64 `protobuf:"fixed64,4,opt,name=response_rate,json=responseRate,proto3" json:"response_rate,omitempty"`
+	// Time between applying and the first response
+	MedianTimeToFirstResponse *durationpb.Duration  `protobuf:"bytes,5,opt,name=median_time_to_first_response,json=medianTimeToFirstResponse,proto3" json:"median_time_to_first_response,omitempty"`
+	P90TimeToFirstResponse    *durationpb.Duration  `protobuf:"bytes,6,opt,name=p90_time_to_first_response,json=p90TimeToFirstResponse,proto3" json:"p90_time_to_first_response,omitempty"`
+	Interviews                []*InterviewTypeStats `protobuf:"bytes,7,rep,name=interviews,proto3" json:"interviews,omitempty"`
+	// Applications per week, weeks without applications included, at most the last 520 weeks
+	Weekly []*WeeklyVolume `protobuf:"bytes,8,rep,name=weekly,proto3" json:"weekly,omitempty"`
+	// Applications reaching each stage, from applied to accepted
+	Funnel []*FunnelStage `protobuf:"bytes,9,rep,name=funnel,proto3" json:"funnel,omitempty"`
+	// Histogram of the time between applying and the first response
+	ResponseTimes []*ResponseTimeBucket `protobuf:"bytes,10,rep,name=response_times,json=responseTimes,proto3" json:"response_times,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetStatsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetStatusCounts() []*StatusCount {
+	if x != nil {
+		return x.StatusCounts
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetResponses() int32 {
+	if x != nil {
+		return x.Responses
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetResponseRate() float64 {
+	if x != nil {
+		return x.ResponseRate
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetMedianTimeToFirstResponse() *durationpb.Duration {
+	if x != nil {
+		return x.MedianTimeToFirstResponse
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetP90TimeToFirstResponse() *durationpb.Duration {
+	if x != nil {
+		return x.P90TimeToFirstResponse
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetInterviews() []*InterviewTypeStats {
+	if x != nil {
+		return x.Interviews
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetWeekly() []*WeeklyVolume {
+	if x != nil {
+		return x.Weekly
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetFunnel() []*FunnelStage {
+	if x != nil {
+		return x.Funnel
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetResponseTimes() []*ResponseTimeBucket {
+	if x != nil {
+		return x.ResponseTimes
+	}
+	return nil
+}
+
+type ApplicationEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// When it happened, e.g. the sent time of the email which changed the status
+	Time   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Type   EventType              `protobuf:"varint,2,opt,name=type,proto3,enum=maxbear.maxhire.EventType" json:"type,omitempty"`
+	Source EventSource            `protobuf:"varint,3,opt,name=source,proto3,enum=maxbear.maxhire.EventSource" json:"source,omitempty"`
+	// Set for EVENT_STATUS_CHANGED
+	PreviousStatus StatusType `protobuf:"varint,4,opt,name=previous_status,json=previousStatus,proto3,enum=maxbear.maxhire.StatusType" json:"previous_status,omitempty"`
+	Status         StatusType `protobuf:"varint,5,opt,name=status,proto3,enum=maxbear.maxhire.StatusType" json:"status,omitempty"`
+	// Set for EVENT_INTERVIEW_SCHEDULED
+	InterviewType InterviewType          `protobuf:"varint,6,opt,name=interview_type,json=interviewType,proto3,enum=maxbear.maxhire.InterviewType" json:"interview_type,omitempty"`
+	InterviewTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=interview_time,json=interviewTime,proto3" json:"interview_time,omitempty"`
+	// Set for EVENT_EMAIL_LINKED
+	MessageId     string `protobuf:"bytes,8,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplicationEvent) Reset() {
+	*x = ApplicationEvent{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplicationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplicationEvent) ProtoMessage() {}
+
+func (x *ApplicationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplicationEvent.ProtoReflect.Descriptor instead.
+func (*ApplicationEvent) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ApplicationEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *ApplicationEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_STATUS_CHANGED
+}
+
+func (x *ApplicationEvent) GetSource() EventSource {
+	if x != nil {
+		return x.Source
+	}
+	return EventSource_SOURCE_MANUAL
+}
+
+func (x *ApplicationEvent) GetPreviousStatus() StatusType {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return StatusType_PENDING
+}
+
+func (x *ApplicationEvent) GetStatus() StatusType {
+	if x != nil {
+		return x.Status
+	}
+	return StatusType_PENDING
+}
+
+func (x *ApplicationEvent) GetInterviewType() InterviewType {
+	if x != nil {
+		return x.InterviewType
+	}
+	return InterviewType_UNSPECIFIED
+}
+
+func (x *ApplicationEvent) GetInterviewTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InterviewTime
+	}
+	return nil
+}
+
+func (x *ApplicationEvent) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type ListApplicationEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApplicationEventsRequest) Reset() {
+	*x = ListApplicationEventsRequest{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApplicationEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApplicationEventsRequest) ProtoMessage() {}
+
+func (x *ListApplicationEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApplicationEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListApplicationEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListApplicationEventsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListApplicationEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ordered by time
+	Events        []*ApplicationEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApplicationEventsResponse) Reset() {
+	*x = ListApplicationEventsResponse{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApplicationEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApplicationEventsResponse) ProtoMessage() {}
+
+func (x *ListApplicationEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApplicationEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListApplicationEventsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListApplicationEventsResponse) GetEvents() []*ApplicationEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// Same filters as ListApplicationsRequest
+type WatchApplicationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional filter by application status type, only applied for statuses other than PENDING
+	// since PENDING can't be told apart from unset, use statuses instead
+	Status StatusType `protobuf:"varint,1,opt,name=status,proto3,enum=maxbear.maxhire.StatusType" json:"status,omitempty"`
+	// Optional filter by date range
+	StartDate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// Optional filter by company name (case-insensitive match)
+	Company string `protobuf:"bytes,4,opt,name=company,proto3" json:"company,omitempty"`
+	// Optional filter by any of the application status types
+	Statuses []StatusType `protobuf:"varint,5,rep,packed,name=statuses,proto3,enum=maxbear.maxhire.StatusType" json:"statuses,omitempty"`
+	// Match company as a prefix of the company name instead of the whole name
+	CompanyPrefix bool `protobuf:"varint,6,opt,name=company_prefix,json=companyPrefix,proto3" json:"company_prefix,omitempty"`
+	// Optional free text search over the position and the subject of the source email
+	Query string `protobuf:"bytes,7,opt,name=query,proto3" json:"query,omitempty"`
+	// Optional filter on whether applications have interviews
+	HasInterviews *bool `protobuf:"varint,8,opt,name=has_interviews,json=hasInterviews,proto3,oneof" json:"has_interviews,omitempty"`
+	// Optional filter by applications having an interview of any of these types
+	InterviewTypes []InterviewType `protobuf:"varint,9,rep,packed,name=interview_types,json=interviewTypes,proto3,enum=maxbear.maxhire.InterviewType" json:"interview_types,omitempty"`
+	// Stream the changes made after this revision, as returned by ListApplications or the last
+	// received change, to resume without missing any. Changes are streamed from now on if 0.
+	// Revisions don't survive a server restart, see WatchApplications.
+	FromRevision  int64 `protobuf:"varint,10,opt,name=from_revision,json=fromRevision,proto3" json:"from_revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchApplicationsRequest) Reset() {
+	*x = WatchApplicationsRequest{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchApplicationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchApplicationsRequest) ProtoMessage() {}
+
+func (x *WatchApplicationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchApplicationsRequest.ProtoReflect.Descriptor instead.
+func (*WatchApplicationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *WatchApplicationsRequest) GetStatus() StatusType {
+	if x != nil {
+		return x.Status
+	}
+	return StatusType_PENDING
+}
+
+func (x *WatchApplicationsRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *WatchApplicationsRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *WatchApplicationsRequest) GetCompany() string {
+	if x != nil {
+		return x.Company
+	}
+	return ""
+}
+
+func (x *WatchApplicationsRequest) GetStatuses() []StatusType {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *WatchApplicationsRequest) GetCompanyPrefix() bool {
+	if x != nil {
+		return x.CompanyPrefix
+	}
+	return false
+}
+
+func (x *WatchApplicationsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *WatchApplicationsRequest) GetHasInterviews() bool {
+	if x != nil && x.HasInterviews != nil {
+		return *x.HasInterviews
+	}
+	return false
+}
+
+func (x *WatchApplicationsRequest) GetInterviewTypes() []InterviewType {
+	if x != nil {
+		return x.InterviewTypes
+	}
+	return nil
+}
+
+func (x *WatchApplicationsRequest) GetFromRevision() int64 {
+	if x != nil {
+		return x.FromRevision
+	}
+	return 0
+}
+
+type WatchApplicationsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Revision of the change, increasing by 1 with every change
+	Revision int64      `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Type     ChangeType `protobuf:"varint,2,opt,name=type,proto3,enum=maxbear.maxhire.ChangeType" json:"type,omitempty"`
+	// The application after the change, or before it was deleted
+	Application   *Application `protobuf:"bytes,3,opt,name=application,proto3" json:"application,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchApplicationsResponse) Reset() {
+	*x = WatchApplicationsResponse{}
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchApplicationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchApplicationsResponse) ProtoMessage() {}
+
+func (x *WatchApplicationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_applications_v1_applications_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchApplicationsResponse.ProtoReflect.Descriptor instead.
+func (*WatchApplicationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_applications_v1_applications_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *WatchApplicationsResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *WatchApplicationsResponse) GetType() ChangeType {
+	if x != nil {
+		return x.Type
+	}
+	return ChangeType_CHANGE_CREATED
+}
+
+func (x *WatchApplicationsResponse) GetApplication() *Application {
+	if x != nil {
+		return x.Application
+	}
+	return nil
+}
+
+var File_proto_applications_v1_applications_proto protoreflect.FileDescriptor
 
 const file_proto_applications_v1_applications_proto_rawDesc = "" +
 	"\n" +
-	"(proto/applications/v1/applications.proto\x12\x0fmaxbear.maxhire\x1a\x1fgoogle/protobuf/timestamp.proto\"\xad\x01\n" +
+	"(proto/applications/v1/applications.proto\x12\x0fmaxbear.maxhire\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xad\x01\n" +
 	"\tInterview\x126\n" +
 	"\bdatetime\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\bdatetime\x12E\n" +
 	"\x0einterview_type\x18\x02 \x01(\x0e2\x1e.maxbear.maxhire.InterviewTypeR\rinterviewType\x12!\n" +
-	"\fduration_min\x18\x03 \x01(\x05R\vdurationMin\"\xe4\x01\n" +
+	"\fduration_min\x18\x03 \x01(\x05R\vdurationMin\"\xad\x02\n" +
 	"\vApplication\x12.\n" +
 	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x18\n" +
 	"\acompany\x18\x02 \x01(\tR\acompany\x12\x1a\n" +
@@ -554,32 +2175,151 @@ const file_proto_applications_v1_applications_proto_rawDesc = "" +
 	"\x06status\x18\x04 \x01(\x0e2\x1b.maxbear.maxhire.StatusTypeR\x06status\x12:\n" +
 	"\n" +
 	"interviews\x18\x05 \x03(\v2\x1a.maxbear.maxhire.InterviewR\n" +
-	"interviews\"Z\n" +
+	"interviews\x12\x0e\n" +
+	"\x02id\x18\x06 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\a \x01(\tR\tmessageId\x12\x18\n" +
+	"\asubject\x18\b \x01(\tR\asubject\"\x90\x01\n" +
 	"\x16SetApplicationsRequest\x12@\n" +
-	"\fapplications\x18\x01 \x03(\v2\x1c.maxbear.maxhire.ApplicationR\fapplications\"X\n" +
+	"\fapplications\x18\x01 \x03(\v2\x1c.maxbear.maxhire.ApplicationR\fapplications\x124\n" +
+	"\x06source\x18\x02 \x01(\x0e2\x1c.maxbear.maxhire.EventSourceR\x06source\"\xc7\x01\n" +
+	"\x14SetApplicationResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12A\n" +
+	"\x06result\x18\x02 \x01(\x0e2).maxbear.maxhire.SetApplicationResultTypeR\x06result\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12>\n" +
+	"\vapplication\x18\x04 \x01(\v2\x1c.maxbear.maxhire.ApplicationR\vapplication\"\x9c\x01\n" +
+	"\x17SetApplicationsResponse\x12@\n" +
+	"\fapplications\x18\x01 \x03(\v2\x1c.maxbear.maxhire.ApplicationR\fapplications\x12?\n" +
+	"\aresults\x18\x02 \x03(\v2%.maxbear.maxhire.SetApplicationResultR\aresults\"\xbb\x01\n" +
 	"\x14ApplicationsResponse\x12@\n" +
-	"\fapplications\x18\x01 \x03(\v2\x1c.maxbear.maxhire.ApplicationR\fapplications\"\xda\x01\n" +
+	"\fapplications\x18\x01 \x03(\v2\x1c.maxbear.maxhire.ApplicationR\fapplications\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\x12\x1a\n" +
+	"\brevision\x18\x04 \x01(\x03R\brevision\"\xaf\x04\n" +
 	"\x17ListApplicationsRequest\x123\n" +
 	"\x06status\x18\x01 \x01(\x0e2\x1b.maxbear.maxhire.StatusTypeR\x06status\x129\n" +
 	"\n" +
 	"start_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
 	"\bend_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x18\n" +
-	"\acompany\x18\x04 \x01(\tR\acompany\"\x9c\x01\n" +
-	"\x14SetInterviewsRequest\x12.\n" +
+	"\acompany\x18\x04 \x01(\tR\acompany\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\a \x01(\tR\aorderBy\x127\n" +
+	"\bstatuses\x18\b \x03(\x0e2\x1b.maxbear.maxhire.StatusTypeR\bstatuses\x12%\n" +
+	"\x0ecompany_prefix\x18\t \x01(\bR\rcompanyPrefix\x12\x14\n" +
+	"\x05query\x18\n" +
+	" \x01(\tR\x05query\x12*\n" +
+	"\x0ehas_interviews\x18\v \x01(\bH\x00R\rhasInterviews\x88\x01\x01\x12G\n" +
+	"\x0finterview_types\x18\f \x03(\x0e2\x1e.maxbear.maxhire.InterviewTypeR\x0einterviewTypesB\x11\n" +
+	"\x0f_has_interviews\"\xe2\x01\n" +
+	"\x14SetInterviewsRequest\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\x12.\n" +
 	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x18\n" +
 	"\acompany\x18\x02 \x01(\tR\acompany\x12:\n" +
 	"\n" +
 	"interviews\x18\x03 \x03(\v2\x1a.maxbear.maxhire.InterviewR\n" +
-	"interviews\"W\n" +
+	"interviews\x124\n" +
+	"\x06source\x18\x05 \x01(\x0e2\x1c.maxbear.maxhire.EventSourceR\x06source\"W\n" +
 	"\x15SetInterviewsResponse\x12>\n" +
-	"\vapplication\x18\x01 \x01(\v2\x1c.maxbear.maxhire.ApplicationR\vapplication*?\n" +
+	"\vapplication\x18\x01 \x01(\v2\x1c.maxbear.maxhire.ApplicationR\vapplication\"'\n" +
+	"\x15GetApplicationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"X\n" +
+	"\x16GetApplicationResponse\x12>\n" +
+	"\vapplication\x18\x01 \x01(\v2\x1c.maxbear.maxhire.ApplicationR\vapplication\"\xcd\x01\n" +
+	"\x18UpdateApplicationRequest\x12>\n" +
+	"\vapplication\x18\x01 \x01(\v2\x1c.maxbear.maxhire.ApplicationR\vapplication\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x124\n" +
+	"\x06source\x18\x03 \x01(\x0e2\x1c.maxbear.maxhire.EventSourceR\x06source\"[\n" +
+	"\x19UpdateApplicationResponse\x12>\n" +
+	"\vapplication\x18\x01 \x01(\v2\x1c.maxbear.maxhire.ApplicationR\vapplication\"*\n" +
+	"\x18DeleteApplicationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1b\n" +
+	"\x19DeleteApplicationResponse\"\x83\x01\n" +
+	"\x0fGetStatsRequest\x129\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\"f\n" +
+	"\vStatusCount\x123\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x1b.maxbear.maxhire.StatusTypeR\x06status\x12\"\n" +
+	"\fapplications\x18\x02 \x01(\x05R\fapplications\"\xcb\x01\n" +
+	"\x12InterviewTypeStats\x12E\n" +
+	"\x0einterview_type\x18\x01 \x01(\x0e2\x1e.maxbear.maxhire.InterviewTypeR\rinterviewType\x12\"\n" +
+	"\fapplications\x18\x02 \x01(\x05R\fapplications\x12'\n" +
+	"\x0fconversion_rate\x18\x03 \x01(\x01R\x0econversionRate\x12!\n" +
+	"\fsuccess_rate\x18\x04 \x01(\x01R\vsuccessRate\"m\n" +
+	"\fWeeklyVolume\x129\n" +
+	"\n" +
+	"week_start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tweekStart\x12\"\n" +
+	"\fapplications\x18\x02 \x01(\x05R\fapplications\"G\n" +
+	"\vFunnelStage\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\"\n" +
+	"\fapplications\x18\x02 \x01(\x05R\fapplications\"t\n" +
+	"\x12ResponseTimeBucket\x12:\n" +
+	"\vupper_bound\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"upperBound\x12\"\n" +
+	"\fapplications\x18\x02 \x01(\x05R\fapplications\"\xe0\x04\n" +
+	"\x10GetStatsResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12A\n" +
+	"\rstatus_counts\x18\x02 \x03(\v2\x1c.maxbear.maxhire.StatusCountR\fstatusCounts\x12\x1c\n" +
+	"\tresponses\x18\x03 \x01(\x05R\tresponses\x12#\n" +
+	"\rresponse_rate\x18\x04 \x01(\x01R\fresponseRate\x12[\n" +
+	"\x1dmedian_time_to_first_response\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x19medianTimeToFirstResponse\x12U\n" +
+	"\x1ap90_time_to_first_response\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x16p90TimeToFirstResponse\x12C\n" +
+	"\n" +
+	"interviews\x18\a \x03(\v2#.maxbear.maxhire.InterviewTypeStatsR\n" +
+	"interviews\x125\n" +
+	"\x06weekly\x18\b \x03(\v2\x1d.maxbear.maxhire.WeeklyVolumeR\x06weekly\x124\n" +
+	"\x06funnel\x18\t \x03(\v2\x1c.maxbear.maxhire.FunnelStageR\x06funnel\x12J\n" +
+	"\x0eresponse_times\x18\n" +
+	" \x03(\v2#.maxbear.maxhire.ResponseTimeBucketR\rresponseTimes\"\xcc\x03\n" +
+	"\x10ApplicationEvent\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12.\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1a.maxbear.maxhire.EventTypeR\x04type\x124\n" +
+	"\x06source\x18\x03 \x01(\x0e2\x1c.maxbear.maxhire.EventSourceR\x06source\x12D\n" +
+	"\x0fprevious_status\x18\x04 \x01(\x0e2\x1b.maxbear.maxhire.StatusTypeR\x0epreviousStatus\x123\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1b.maxbear.maxhire.StatusTypeR\x06status\x12E\n" +
+	"\x0einterview_type\x18\x06 \x01(\x0e2\x1e.maxbear.maxhire.InterviewTypeR\rinterviewType\x12A\n" +
+	"\x0einterview_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rinterviewTime\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\b \x01(\tR\tmessageId\".\n" +
+	"\x1cListApplicationEventsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Z\n" +
+	"\x1dListApplicationEventsResponse\x129\n" +
+	"\x06events\x18\x01 \x03(\v2!.maxbear.maxhire.ApplicationEventR\x06events\"\xfe\x03\n" +
+	"\x18WatchApplicationsRequest\x123\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x1b.maxbear.maxhire.StatusTypeR\x06status\x129\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x18\n" +
+	"\acompany\x18\x04 \x01(\tR\acompany\x127\n" +
+	"\bstatuses\x18\x05 \x03(\x0e2\x1b.maxbear.maxhire.StatusTypeR\bstatuses\x12%\n" +
+	"\x0ecompany_prefix\x18\x06 \x01(\bR\rcompanyPrefix\x12\x14\n" +
+	"\x05query\x18\a \x01(\tR\x05query\x12*\n" +
+	"\x0ehas_interviews\x18\b \x01(\bH\x00R\rhasInterviews\x88\x01\x01\x12G\n" +
+	"\x0finterview_types\x18\t \x03(\x0e2\x1e.maxbear.maxhire.InterviewTypeR\x0einterviewTypes\x12#\n" +
+	"\rfrom_revision\x18\n" +
+	" \x01(\x03R\ffromRevisionB\x11\n" +
+	"\x0f_has_interviews\"\xa8\x01\n" +
+	"\x19WatchApplicationsResponse\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\x12/\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1b.maxbear.maxhire.ChangeTypeR\x04type\x12>\n" +
+	"\vapplication\x18\x03 \x01(\v2\x1c.maxbear.maxhire.ApplicationR\vapplication*\xa0\x01\n" +
 	"\n" +
 	"StatusType\x12\v\n" +
 	"\aPENDING\x10\x00\x12\n" +
 	"\n" +
 	"\x06REJECT\x10\x01\x12\v\n" +
 	"\aSUCCESS\x10\x02\x12\v\n" +
-	"\aAPPLIED\x10\x03*\x83\x01\n" +
+	"\aAPPLIED\x10\x03\x12\x10\n" +
+	"\fINTERVIEWING\x10\x04\x12\t\n" +
+	"\x05OFFER\x10\x05\x12\x12\n" +
+	"\x0eOFFER_ACCEPTED\x10\x06\x12\x12\n" +
+	"\x0eOFFER_DECLINED\x10\a\x12\r\n" +
+	"\tWITHDRAWN\x10\b\x12\v\n" +
+	"\aGHOSTED\x10\t*\x83\x01\n" +
 	"\rInterviewType\x12\x0f\n" +
 	"\vUNSPECIFIED\x10\x00\x12\x14\n" +
 	"\x10RECRUITER_SCREEN\x10\x01\x12\x12\n" +
@@ -587,11 +2327,37 @@ const file_proto_applications_v1_applications_proto_rawDesc = "" +
 	"\vTECH_CODING\x10\x03\x12\x16\n" +
 	"\x12TECH_SYSTEM_DESIGN\x10\x04\x12\x0e\n" +
 	"\n" +
-	"TEAM_MATCH\x10\x052\xbc\x02\n" +
-	"\fApplications\x12c\n" +
-	"\x0fSetApplications\x12'.maxbear.maxhire.SetApplicationsRequest\x1a%.maxbear.maxhire.ApplicationsResponse\"\x00\x12e\n" +
+	"TEAM_MATCH\x10\x05*\\\n" +
+	"\tEventType\x12\x18\n" +
+	"\x14EVENT_STATUS_CHANGED\x10\x00\x12\x1d\n" +
+	"\x19EVENT_INTERVIEW_SCHEDULED\x10\x01\x12\x16\n" +
+	"\x12EVENT_EMAIL_LINKED\x10\x02*A\n" +
+	"\vEventSource\x12\x11\n" +
+	"\rSOURCE_MANUAL\x10\x00\x12\x0e\n" +
+	"\n" +
+	"SOURCE_LLM\x10\x01\x12\x0f\n" +
+	"\vSOURCE_RULE\x10\x02*\x85\x01\n" +
+	"\x18SetApplicationResultType\x12\x16\n" +
+	"\x12RESULT_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eRESULT_CREATED\x10\x01\x12\x12\n" +
+	"\x0eRESULT_UPDATED\x10\x02\x12\x14\n" +
+	"\x10RESULT_UNCHANGED\x10\x03\x12\x13\n" +
+	"\x0fRESULT_REJECTED\x10\x04*H\n" +
+	"\n" +
+	"ChangeType\x12\x12\n" +
+	"\x0eCHANGE_CREATED\x10\x00\x12\x12\n" +
+	"\x0eCHANGE_UPDATED\x10\x01\x12\x12\n" +
+	"\x0eCHANGE_DELETED\x10\x022\xbd\a\n" +
+	"\fApplications\x12f\n" +
+	"\x0fSetApplications\x12'.maxbear.maxhire.SetApplicationsRequest\x1a(.maxbear.maxhire.SetApplicationsResponse\"\x00\x12e\n" +
 	"\x10ListApplications\x12(.maxbear.maxhire.ListApplicationsRequest\x1a%.maxbear.maxhire.ApplicationsResponse\"\x00\x12`\n" +
-	"\rSetInterviews\x12%.maxbear.maxhire.SetInterviewsRequest\x1a&.maxbear.maxhire.SetInterviewsResponse\"\x00B-Z+proto/gen/go/applications/v1;applicationspbb\x06proto3"
+	"\rSetInterviews\x12%.maxbear.maxhire.SetInterviewsRequest\x1a&.maxbear.maxhire.SetInterviewsResponse\"\x00\x12c\n" +
+	"\x0eGetApplication\x12&.maxbear.maxhire.GetApplicationRequest\x1a'.maxbear.maxhire.GetApplicationResponse\"\x00\x12l\n" +
+	"\x11UpdateApplication\x12).maxbear.maxhire.UpdateApplicationRequest\x1a*.maxbear.maxhire.UpdateApplicationResponse\"\x00\x12l\n" +
+	"\x11DeleteApplication\x12).maxbear.maxhire.DeleteApplicationRequest\x1a*.maxbear.maxhire.DeleteApplicationResponse\"\x00\x12Q\n" +
+	"\bGetStats\x12 .maxbear.maxhire.GetStatsRequest\x1a!.maxbear.maxhire.GetStatsResponse\"\x00\x12x\n" +
+	"\x15ListApplicationEvents\x12-.maxbear.maxhire.ListApplicationEventsRequest\x1a..maxbear.maxhire.ListApplicationEventsResponse\"\x00\x12n\n" +
+	"\x11WatchApplications\x12).maxbear.maxhire.WatchApplicationsRequest\x1a*.maxbear.maxhire.WatchApplicationsResponse\"\x000\x01B-Z+proto/gen/go/applications/v1;applicationspbb\x06proto3"
 
 var (
 	file_proto_applications_v1_applications_proto_rawDescOnce sync.Once
@@ -605,45 +2371,124 @@ func file_proto_applications_v1_applications_proto_rawDescGZIP() []byte {
 	return file_proto_applications_v1_applications_proto_rawDescData
 }
 
-var file_proto_applications_v1_applications_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_proto_applications_v1_applications_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_applications_v1_applications_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_proto_applications_v1_applications_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_proto_applications_v1_applications_proto_goTypes = []any{
-	(StatusType)(0),                 // 0: maxbear.maxhire.StatusType
-	(InterviewType)(0),              // 1: maxbear.maxhire.InterviewType
-	(*Interview)(nil),               // 2: maxbear.maxhire.Interview
-	(*Application)(nil),             // 3: maxbear.maxhire.Application
-	(*SetApplicationsRequest)(nil),  // 4: maxbear.maxhire.SetApplicationsRequest
-	(*ApplicationsResponse)(nil),    // 5: maxbear.maxhire.ApplicationsResponse
-	(*ListApplicationsRequest)(nil), // 6: maxbear.maxhire.ListApplicationsRequest
-	(*SetInterviewsRequest)(nil),    // 7: maxbear.maxhire.SetInterviewsRequest
-	(*SetInterviewsResponse)(nil),   // 8: maxbear.maxhire.SetInterviewsResponse
-	(*timestamppb.Timestamp)(nil),   // 9: google.protobuf.Timestamp
+	(StatusType)(0),                       // 0: maxbear.maxhire.StatusType
+	(InterviewType)(0),                    // 1: maxbear.maxhire.InterviewType
+	(EventType)(0),                        // 2: maxbear.maxhire.EventType
+	(EventSource)(0),                      // 3: maxbear.maxhire.EventSource
+	(SetApplicationResultType)(0),         // 4: maxbear.maxhire.SetApplicationResultType
+	(ChangeType)(0),                       // 5: maxbear.maxhire.ChangeType
+	(*Interview)(nil),                     // 6: maxbear.maxhire.Interview
+	(*Application)(nil),                   // 7: maxbear.maxhire.Application
+	(*SetApplicationsRequest)(nil),        // 8: maxbear.maxhire.SetApplicationsRequest
+	(*SetApplicationResult)(nil),          // 9: maxbear.maxhire.SetApplicationResult
+	(*SetApplicationsResponse)(nil),       // 10: maxbear.maxhire.SetApplicationsResponse
+	(*ApplicationsResponse)(nil),          // 11: maxbear.maxhire.ApplicationsResponse
+	(*ListApplicationsRequest)(nil),       // 12: maxbear.maxhire.ListApplicationsRequest
+	(*SetInterviewsRequest)(nil),          // 13: maxbear.maxhire.SetInterviewsRequest
+	(*SetInterviewsResponse)(nil),         // 14: maxbear.maxhire.SetInterviewsResponse
+	(*GetApplicationRequest)(nil),         // 15: maxbear.maxhire.GetApplicationRequest
+	(*GetApplicationResponse)(nil),        // 16: maxbear.maxhire.GetApplicationResponse
+	(*UpdateApplicationRequest)(nil),      // 17: maxbear.maxhire.UpdateApplicationRequest
+	(*UpdateApplicationResponse)(nil),     // 18: maxbear.maxhire.UpdateApplicationResponse
+	(*DeleteApplicationRequest)(nil),      // 19: maxbear.maxhire.DeleteApplicationRequest
+	(*DeleteApplicationResponse)(nil),     // 20: maxbear.maxhire.DeleteApplicationResponse
+	(*GetStatsRequest)(nil),               // 21: maxbear.maxhire.GetStatsRequest
+	(*StatusCount)(nil),                   // 22: maxbear.maxhire.StatusCount
+	(*InterviewTypeStats)(nil),            // 23: maxbear.maxhire.InterviewTypeStats
+	(*WeeklyVolume)(nil),                  // 24: maxbear.maxhire.WeeklyVolume
+	(*FunnelStage)(nil),                   // 25: maxbear.maxhire.FunnelStage
+	(*ResponseTimeBucket)(nil),            // 26: maxbear.maxhire.ResponseTimeBucket
+	(*GetStatsResponse)(nil),              // 27: maxbear.maxhire.GetStatsResponse
+	(*ApplicationEvent)(nil),              // 28: maxbear.maxhire.ApplicationEvent
+	(*ListApplicationEventsRequest)(nil),  // 29: maxbear.maxhire.ListApplicationEventsRequest
+	(*ListApplicationEventsResponse)(nil), // 30: maxbear.maxhire.ListApplicationEventsResponse
+	(*WatchApplicationsRequest)(nil),      // 31: maxbear.maxhire.WatchApplicationsRequest
+	(*WatchApplicationsResponse)(nil),     // 32: maxbear.maxhire.WatchApplicationsResponse
+	(*timestamppb.Timestamp)(nil),         // 33: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),         // 34: google.protobuf.FieldMask
+	(*durationpb.Duration)(nil),           // 35: google.protobuf.Duration
 }
 var file_proto_applications_v1_applications_proto_depIdxs = []int32{
-	9,  // 0: maxbear.maxhire.Interview.datetime:type_name -> google.protobuf.Timestamp
+	33, // 0: maxbear.maxhire.Interview.datetime:type_name -> google.protobuf.Timestamp
 	1,  // 1: maxbear.maxhire.Interview.interview_type:type_name -> maxbear.maxhire.InterviewType
-	9,  // 2: maxbear.maxhire.Application.date:type_name -> google.protobuf.Timestamp
+	33, // 2: maxbear.maxhire.Application.date:type_name -> google.protobuf.Timestamp
 	0,  // 3: maxbear.maxhire.Application.status:type_name -> maxbear.maxhire.StatusType
-	2,  // 4: maxbear.maxhire.Application.interviews:type_name -> maxbear.maxhire.Interview
-	3,  // 5: maxbear.maxhire.SetApplicationsRequest.applications:type_name -> maxbear.maxhire.Application
-	3,  // 6: maxbear.maxhire.ApplicationsResponse.applications:type_name -> maxbear.maxhire.Application
-	0,  // 7: maxbear.maxhire.ListApplicationsRequest.status:type_name -> maxbear.maxhire.StatusType
-	9,  // 8: maxbear.maxhire.ListApplicationsRequest.start_date:type_name -> google.protobuf.Timestamp
-	9,  // 9: maxbear.maxhire.ListApplicationsRequest.end_date:type_name -> google.protobuf.Timestamp
-	9,  // 10: maxbear.maxhire.SetInterviewsRequest.date:type_name -> google.protobuf.Timestamp
-	2,  // 11: maxbear.maxhire.SetInterviewsRequest.interviews:type_name -> maxbear.maxhire.Interview
-	3,  // 12: maxbear.maxhire.SetInterviewsResponse.application:type_name -> maxbear.maxhire.Application
-	4,  // 13: maxbear.maxhire.Applications.SetApplications:input_type -> maxbear.maxhire.SetApplicationsRequest
-	6,  // 14: maxbear.maxhire.Applications.ListApplications:input_type -> maxbear.maxhire.ListApplicationsRequest
-	7,  // 15: maxbear.maxhire.Applications.SetInterviews:input_type -> maxbear.maxhire.SetInterviewsRequest
-	5,  // 16: maxbear.maxhire.Applications.SetApplications:output_type -> maxbear.maxhire.ApplicationsResponse
-	5,  // 17: maxbear.maxhire.Applications.ListApplications:output_type -> maxbear.maxhire.ApplicationsResponse
-	8,  // 18: maxbear.maxhire.Applications.SetInterviews:output_type -> maxbear.maxhire.SetInterviewsResponse
-	16, // [16:19] is the sub-list for method output_type
-	13, // [13:16] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	6,  // 4: maxbear.maxhire.Application.interviews:type_name -> maxbear.maxhire.Interview
+	7,  // 5: maxbear.maxhire.SetApplicationsRequest.applications:type_name -> maxbear.maxhire.Application
+	3,  // 6: maxbear.maxhire.SetApplicationsRequest.source:type_name -> maxbear.maxhire.EventSource
+	4,  // 7: maxbear.maxhire.SetApplicationResult.result:type_name -> maxbear.maxhire.SetApplicationResultType
+	7,  // 8: maxbear.maxhire.SetApplicationResult.application:type_name -> maxbear.maxhire.Application
+	7,  // 9: maxbear.maxhire.SetApplicationsResponse.applications:type_name -> maxbear.maxhire.Application
+	9,  // 10: maxbear.maxhire.SetApplicationsResponse.results:type_name -> maxbear.maxhire.SetApplicationResult
+	7,  // 11: maxbear.maxhire.ApplicationsResponse.applications:type_name -> maxbear.maxhire.Application
+	0,  // 12: maxbear.maxhire.ListApplicationsRequest.status:type_name -> maxbear.maxhire.StatusType
+	33, // 13: maxbear.maxhire.ListApplicationsRequest.start_date:type_name -> google.protobuf.Timestamp
+	33, // 14: maxbear.maxhire.ListApplicationsRequest.end_date:type_name -> google.protobuf.Timestamp
+	0,  // 15: maxbear.maxhire.ListApplicationsRequest.statuses:type_name -> maxbear.maxhire.StatusType
+	1,  // 16: maxbear.maxhire.ListApplicationsRequest.interview_types:type_name -> maxbear.maxhire.InterviewType
+	33, // 17: maxbear.maxhire.SetInterviewsRequest.date:type_name -> google.protobuf.Timestamp
+	6,  // 18: maxbear.maxhire.SetInterviewsRequest.interviews:type_name -> maxbear.maxhire.Interview
+	3,  // 19: maxbear.maxhire.SetInterviewsRequest.source:type_name -> maxbear.maxhire.EventSource
+	7,  // 20: maxbear.maxhire.SetInterviewsResponse.application:type_name -> maxbear.maxhire.Application
+	7,  // 21: maxbear.maxhire.GetApplicationResponse.application:type_name -> maxbear.maxhire.Application
+	7,  // 22: maxbear.maxhire.UpdateApplicationRequest.application:type_name -> maxbear.maxhire.Application
+	34, // 23: maxbear.maxhire.UpdateApplicationRequest.update_mask:type_name -> google.protobuf.FieldMask
+	3,  // 24: maxbear.maxhire.UpdateApplicationRequest.source:type_name -> maxbear.maxhire.EventSource
+	7,  // 25: maxbear.maxhire.UpdateApplicationResponse.application:type_name -> maxbear.maxhire.Application
+	33, // 26: maxbear.maxhire.GetStatsRequest.start_date:type_name -> google.protobuf.Timestamp
+	33, // 27: maxbear.maxhire.GetStatsRequest.end_date:type_name -> google.protobuf.Timestamp
+	0,  // 28: maxbear.maxhire.StatusCount.status:type_name -> maxbear.maxhire.StatusType
+	1,  // 29: maxbear.maxhire.InterviewTypeStats.interview_type:type_name -> maxbear.maxhire.InterviewType
+	33, // 30: maxbear.maxhire.WeeklyVolume.week_start:type_name -> google.protobuf.Timestamp
+	35, // 31: maxbear.maxhire.ResponseTimeBucket.upper_bound:type_name -> google.protobuf.Duration
+	22, // 32: maxbear.maxhire.GetStatsResponse.status_counts:type_name -> maxbear.maxhire.StatusCount
+	35, // 33: maxbear.maxhire.GetStatsResponse.median_time_to_first_response:type_name -> google.protobuf.Duration
+	35, // 34: maxbear.maxhire.GetStatsResponse.p90_time_to_first_response:type_name -> google.protobuf.Duration
+	23, // 35: maxbear.maxhire.GetStatsResponse.interviews:type_name -> maxbear.maxhire.InterviewTypeStats
+	24, // 36: maxbear.maxhire.GetStatsResponse.weekly:type_name -> maxbear.maxhire.WeeklyVolume
+	25, // 37: maxbear.maxhire.GetStatsResponse.funnel:type_name -> maxbear.maxhire.FunnelStage
+	26, // 38: maxbear.maxhire.GetStatsResponse.response_times:type_name -> maxbear.maxhire.ResponseTimeBucket
+	33, // 39: maxbear.maxhire.ApplicationEvent.time:type_name -> google.protobuf.Timestamp
+	2,  // 40: maxbear.maxhire.ApplicationEvent.type:type_name -> maxbear.maxhire.EventType
+	3,  // 41: maxbear.maxhire.ApplicationEvent.source:type_name -> maxbear.maxhire.EventSource
+	0,  // 42: maxbear.maxhire.ApplicationEvent.previous_status:type_name -> maxbear.maxhire.StatusType
+	0,  // 43: maxbear.maxhire.ApplicationEvent.status:type_name -> maxbear.maxhire.StatusType
+	1,  // 44: maxbear.maxhire.ApplicationEvent.interview_type:type_name -> maxbear.maxhire.InterviewType
+	33, // 45: maxbear.maxhire.ApplicationEvent.interview_time:type_name -> google.protobuf.Timestamp
+	28, // 46: maxbear.maxhire.ListApplicationEventsResponse.events:type_name -> maxbear.maxhire.ApplicationEvent
+	0,  // 47: maxbear.maxhire.WatchApplicationsRequest.status:type_name -> maxbear.maxhire.StatusType
+	33, // 48: maxbear.maxhire.WatchApplicationsRequest.start_date:type_name -> google.protobuf.Timestamp
+	33, // 49: maxbear.maxhire.WatchApplicationsRequest.end_date:type_name -> google.protobuf.Timestamp
+	0,  // 50: maxbear.maxhire.WatchApplicationsRequest.statuses:type_name -> maxbear.maxhire.StatusType
+	1,  // 51: maxbear.maxhire.WatchApplicationsRequest.interview_types:type_name -> maxbear.maxhire.InterviewType
+	5,  // 52: maxbear.maxhire.WatchApplicationsResponse.type:type_name -> maxbear.maxhire.ChangeType
+	7,  // 53: maxbear.maxhire.WatchApplicationsResponse.application:type_name -> maxbear.maxhire.Application
+	8,  // 54: maxbear.maxhire.Applications.SetApplications:input_type -> maxbear.maxhire.SetApplicationsRequest
+	12, // 55: maxbear.maxhire.Applications.ListApplications:input_type -> maxbear.maxhire.ListApplicationsRequest
+	13, // 56: maxbear.maxhire.Applications.SetInterviews:input_type -> maxbear.maxhire.SetInterviewsRequest
+	15, // 57: maxbear.maxhire.Applications.GetApplication:input_type -> maxbear.maxhire.GetApplicationRequest
+	17, // 58: maxbear.maxhire.Applications.UpdateApplication:input_type -> maxbear.maxhire.UpdateApplicationRequest
+	19, // 59: maxbear.maxhire.Applications.DeleteApplication:input_type -> maxbear.maxhire.DeleteApplicationRequest
+	21, // 60: maxbear.maxhire.Applications.GetStats:input_type -> maxbear.maxhire.GetStatsRequest
+	29, // 61: maxbear.maxhire.Applications.ListApplicationEvents:input_type -> maxbear.maxhire.ListApplicationEventsRequest
+	31, // 62: maxbear.maxhire.Applications.WatchApplications:input_type -> maxbear.maxhire.WatchApplicationsRequest
+	10, // 63: maxbear.maxhire.Applications.SetApplications:output_type -> maxbear.maxhire.SetApplicationsResponse
+	11, // 64: maxbear.maxhire.Applications.ListApplications:output_type -> maxbear.maxhire.ApplicationsResponse
+	14, // 65: maxbear.maxhire.Applications.SetInterviews:output_type -> maxbear.maxhire.SetInterviewsResponse
+	16, // 66: maxbear.maxhire.Applications.GetApplication:output_type -> maxbear.maxhire.GetApplicationResponse
+	18, // 67: maxbear.maxhire.Applications.UpdateApplication:output_type -> maxbear.maxhire.UpdateApplicationResponse
+	20, // 68: maxbear.maxhire.Applications.DeleteApplication:output_type -> maxbear.maxhire.DeleteApplicationResponse
+	27, // 69: maxbear.maxhire.Applications.GetStats:output_type -> maxbear.maxhire.GetStatsResponse
+	30, // 70: maxbear.maxhire.Applications.ListApplicationEvents:output_type -> maxbear.maxhire.ListApplicationEventsResponse
+	32, // 71: maxbear.maxhire.Applications.WatchApplications:output_type -> maxbear.maxhire.WatchApplicationsResponse
+	63, // [63:72] is the sub-list for method output_type
+	54, // [54:63] is the sub-list for method input_type
+	54, // [54:54] is the sub-list for extension type_name
+	54, // [54:54] is the sub-list for extension extendee
+	0,  // [0:54] is the sub-list for field type_name
 }
 
 func init() { file_proto_applications_v1_applications_proto_init() }
@@ -651,13 +2496,15 @@ func file_proto_applications_v1_applications_proto_init() {
 	if File_proto_applications_v1_applications_proto != nil {
 		return
 	}
+	file_proto_applications_v1_applications_proto_msgTypes[6].OneofWrappers = []any{}
+	file_proto_applications_v1_applications_proto_msgTypes[25].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_applications_v1_applications_proto_rawDesc), len(file_proto_applications_v1_applications_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   7,
+			NumEnums:      6,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
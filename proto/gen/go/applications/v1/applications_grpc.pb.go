@@ -19,18 +19,34 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Applications_SetApplications_FullMethodName  = "/maxbear.maxhire.Applications/SetApplications"
-	Applications_ListApplications_FullMethodName = "/maxbear.maxhire.Applications/ListApplications"
-	Applications_SetInterviews_FullMethodName    = "/maxbear.maxhire.Applications/SetInterviews"
+	Applications_SetApplications_FullMethodName       = "/maxbear.maxhire.Applications/SetApplications"
+	Applications_ListApplications_FullMethodName      = "/maxbear.maxhire.Applications/ListApplications"
+	Applications_SetInterviews_FullMethodName         = "/maxbear.maxhire.Applications/SetInterviews"
+	Applications_GetApplication_FullMethodName        = "/maxbear.maxhire.Applications/GetApplication"
+	Applications_UpdateApplication_FullMethodName     = "/maxbear.maxhire.Applications/UpdateApplication"
+	Applications_DeleteApplication_FullMethodName     = "/maxbear.maxhire.Applications/DeleteApplication"
+	Applications_GetStats_FullMethodName              = "/maxbear.maxhire.Applications/GetStats"
+	Applications_ListApplicationEvents_FullMethodName = "/maxbear.maxhire.Applications/ListApplicationEvents"
+	Applications_WatchApplications_FullMethodName     = "/maxbear.maxhire.Applications/WatchApplications"
 )
 
 // ApplicationsClient is the client API for Applications service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ApplicationsClient interface {
-	SetApplications(ctx context.Context, in *SetApplicationsRequest, opts ...grpc.CallOption) (*ApplicationsResponse, error)
+	SetApplications(ctx context.Context, in *SetApplicationsRequest, opts ...grpc.CallOption) (*SetApplicationsResponse, error)
 	ListApplications(ctx context.Context, in *ListApplicationsRequest, opts ...grpc.CallOption) (*ApplicationsResponse, error)
 	SetInterviews(ctx context.Context, in *SetInterviewsRequest, opts ...grpc.CallOption) (*SetInterviewsResponse, error)
+	GetApplication(ctx context.Context, in *GetApplicationRequest, opts ...grpc.CallOption) (*GetApplicationResponse, error)
+	UpdateApplication(ctx context.Context, in *UpdateApplicationRequest, opts ...grpc.CallOption) (*UpdateApplicationResponse, error)
+	DeleteApplication(ctx context.Context, in *DeleteApplicationRequest, opts ...grpc.CallOption) (*DeleteApplicationResponse, error)
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	ListApplicationEvents(ctx context.Context, in *ListApplicationEventsRequest, opts ...grpc.CallOption) (*ListApplicationEventsResponse, error)
+	// Streams the changes to the applications matching the filters as they happen.
+	// Revisions are kept in memory only and start over at 1 when the server restarts, resuming with a
+	// revision from before the restart fails with INVALID_ARGUMENT if it is past the current revision.
+	// Clients reconnecting after a restart list the applications again and watch from their revision.
+	WatchApplications(ctx context.Context, in *WatchApplicationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchApplicationsResponse], error)
 }
 
 type applicationsClient struct {
@@ -41,9 +57,9 @@ func NewApplicationsClient(cc grpc.ClientConnInterface) ApplicationsClient {
 	return &applicationsClient{cc}
 }
 
-func (c *applicationsClient) SetApplications(ctx context.Context, in *SetApplicationsRequest, opts ...grpc.CallOption) (*ApplicationsResponse, error) {
+func (c *applicationsClient) SetApplications(ctx context.Context, in *SetApplicationsRequest, opts ...grpc.CallOption) (*SetApplicationsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ApplicationsResponse)
+	out := new(SetApplicationsResponse)
 	err := c.cc.Invoke(ctx, Applications_SetApplications_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -71,13 +87,92 @@ func (c *applicationsClient) SetInterviews(ctx context.Context, in *SetInterview
 	return out, nil
 }
 
+func (c *applicationsClient) GetApplication(ctx context.Context, in *GetApplicationRequest, opts ...grpc.CallOption) (*GetApplicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetApplicationResponse)
+	err := c.cc.Invoke(ctx, Applications_GetApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *applicationsClient) UpdateApplication(ctx context.Context, in *UpdateApplicationRequest, opts ...grpc.CallOption) (*UpdateApplicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateApplicationResponse)
+	err := c.cc.Invoke(ctx, Applications_UpdateApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *applicationsClient) DeleteApplication(ctx context.Context, in *DeleteApplicationRequest, opts ...grpc.CallOption) (*DeleteApplicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteApplicationResponse)
+	err := c.cc.Invoke(ctx, Applications_DeleteApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *applicationsClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatsResponse)
+	err := c.cc.Invoke(ctx, Applications_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *applicationsClient) ListApplicationEvents(ctx context.Context, in *ListApplicationEventsRequest, opts ...grpc.CallOption) (*ListApplicationEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListApplicationEventsResponse)
+	err := c.cc.Invoke(ctx, Applications_ListApplicationEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *applicationsClient) WatchApplications(ctx context.Context, in *WatchApplicationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchApplicationsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Applications_ServiceDesc.Streams[0], Applications_WatchApplications_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchApplicationsRequest, WatchApplicationsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Applications_WatchApplicationsClient = grpc.ServerStreamingClient[WatchApplicationsResponse]
+
 // ApplicationsServer is the server API for Applications service.
 // All implementations must embed UnimplementedApplicationsServer
 // for forward compatibility.
 type ApplicationsServer interface {
-	SetApplications(context.Context, *SetApplicationsRequest) (*ApplicationsResponse, error)
+	SetApplications(context.Context, *SetApplicationsRequest) (*SetApplicationsResponse, error)
 	ListApplications(context.Context, *ListApplicationsRequest) (*ApplicationsResponse, error)
 	SetInterviews(context.Context, *SetInterviewsRequest) (*SetInterviewsResponse, error)
+	GetApplication(context.Context, *GetApplicationRequest) (*GetApplicationResponse, error)
+	UpdateApplication(context.Context, *UpdateApplicationRequest) (*UpdateApplicationResponse, error)
+	DeleteApplication(context.Context, *DeleteApplicationRequest) (*DeleteApplicationResponse, error)
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	ListApplicationEvents(context.Context, *ListApplicationEventsRequest) (*ListApplicationEventsResponse, error)
+	// Streams the changes to the applications matching the filters as they happen.
+	// Revisions are kept in memory only and start over at 1 when the server restarts, resuming with a
+	// revision from before the restart fails with INVALID_ARGUMENT if it is past the current revision.
+	// Clients reconnecting after a restart list the applications again and watch from their revision.
+	WatchApplications(*WatchApplicationsRequest, grpc.ServerStreamingServer[WatchApplicationsResponse]) error
 	mustEmbedUnimplementedApplicationsServer()
 }
 
@@ -88,7 +183,7 @@ type ApplicationsServer interface {
 // pointer dereference when methods are called.
 type UnimplementedApplicationsServer struct{}
 
-func (UnimplementedApplicationsServer) SetApplications(context.Context, *SetApplicationsRequest) (*ApplicationsResponse, error) {
+func (UnimplementedApplicationsServer) SetApplications(context.Context, *SetApplicationsRequest) (*SetApplicationsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SetApplications not implemented")
 }
 func (UnimplementedApplicationsServer) ListApplications(context.Context, *ListApplicationsRequest) (*ApplicationsResponse, error) {
@@ -97,6 +192,24 @@ func (UnimplementedApplicationsServer) ListApplications(context.Context, *ListAp
 func (UnimplementedApplicationsServer) SetInterviews(context.Context, *SetInterviewsRequest) (*SetInterviewsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SetInterviews not implemented")
 }
+func (UnimplementedApplicationsServer) GetApplication(context.Context, *GetApplicationRequest) (*GetApplicationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetApplication not implemented")
+}
+func (UnimplementedApplicationsServer) UpdateApplication(context.Context, *UpdateApplicationRequest) (*UpdateApplicationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateApplication not implemented")
+}
+func (UnimplementedApplicationsServer) DeleteApplication(context.Context, *DeleteApplicationRequest) (*DeleteApplicationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteApplication not implemented")
+}
+func (UnimplementedApplicationsServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedApplicationsServer) ListApplicationEvents(context.Context, *ListApplicationEventsRequest) (*ListApplicationEventsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListApplicationEvents not implemented")
+}
+func (UnimplementedApplicationsServer) WatchApplications(*WatchApplicationsRequest, grpc.ServerStreamingServer[WatchApplicationsResponse]) error {
+	return status.Error(codes.Unimplemented, "method WatchApplications not implemented")
+}
 func (UnimplementedApplicationsServer) mustEmbedUnimplementedApplicationsServer() {}
 func (UnimplementedApplicationsServer) testEmbeddedByValue()                      {}
 
@@ -172,6 +285,107 @@ func _Applications_SetInterviews_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Applications_GetApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetApplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplicationsServer).GetApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Applications_GetApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplicationsServer).GetApplication(ctx, req.(*GetApplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Applications_UpdateApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateApplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplicationsServer).UpdateApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Applications_UpdateApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplicationsServer).UpdateApplication(ctx, req.(*UpdateApplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Applications_DeleteApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteApplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplicationsServer).DeleteApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Applications_DeleteApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplicationsServer).DeleteApplication(ctx, req.(*DeleteApplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Applications_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplicationsServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Applications_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplicationsServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Applications_ListApplicationEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListApplicationEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplicationsServer).ListApplicationEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Applications_ListApplicationEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplicationsServer).ListApplicationEvents(ctx, req.(*ListApplicationEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Applications_WatchApplications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchApplicationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApplicationsServer).WatchApplications(m, &grpc.GenericServerStream[WatchApplicationsRequest, WatchApplicationsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Applications_WatchApplicationsServer = grpc.ServerStreamingServer[WatchApplicationsResponse]
+
 // Applications_ServiceDesc is the grpc.ServiceDesc for Applications service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,7 +405,33 @@ var Applications_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetInterviews",
 			Handler:    _Applications_SetInterviews_Handler,
 		},
+		{
+			MethodName: "GetApplication",
+			Handler:    _Applications_GetApplication_Handler,
+		},
+		{
+			MethodName: "UpdateApplication",
+			Handler:    _Applications_UpdateApplication_Handler,
+		},
+		{
+			MethodName: "DeleteApplication",
+			Handler:    _Applications_DeleteApplication_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _Applications_GetStats_Handler,
+		},
+		{
+			MethodName: "ListApplicationEvents",
+			Handler:    _Applications_ListApplicationEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchApplications",
+			Handler:       _Applications_WatchApplications_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/applications/v1/applications.proto",
 }
@@ -2,7 +2,15 @@ package server
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
 	"github.com/MaxBear/maxhire/models"
@@ -12,17 +20,71 @@ import (
 
 type Server struct {
 	service service.Service
+	logger  *slog.Logger
 
 	applicationspb.UnimplementedApplicationsServer
 }
 
-func New(svc service.Service) *Server {
-	return &Server{
+type ServerOpt func(*Server)
+
+// WithLogger sets the logger of the server, defaults to slog.Default()
+func WithLogger(logger *slog.Logger) ServerOpt {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+func New(svc service.Service, opts ...ServerOpt) *Server {
+	s := &Server{
 		service: svc,
+		logger:  slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
-func (i *Server) ListApplications(ctx context.Context, req *applicationspb.ListApplicationsRequest) (*applicationspb.ApplicationsResponse, error) {
+// toStatus converts service errors to grpc status errors with the matching code, the unexpected errors
+// are logged
+func (i *Server) toStatus(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrRevisionCompacted):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return err
+	}
+	i.logger.ErrorContext(ctx, "service error", "error", err)
+	return err
+}
+
+// filtersRequest holds the filters shared by ListApplicationsRequest and WatchApplicationsRequest
+type filtersRequest interface {
+	GetStatus() applicationspb.StatusType
+	GetStartDate() *timestamppb.Timestamp
+	GetEndDate() *timestamppb.Timestamp
+	GetCompany() string
+	GetStatuses() []applicationspb.StatusType
+	GetCompanyPrefix() bool
+	GetQuery() string
+	GetInterviewTypes() []applicationspb.InterviewType
+}
+
+// withEventSource records the changes of the call as made by source, which must be known
+func withEventSource(ctx context.Context, source applicationspb.EventSource) (context.Context, error) {
+	if !models.EventSource(source).Valid() {
+		return ctx, status.Errorf(codes.InvalidArgument, "invalid source %d", source)
+	}
+	return service.WithEventSource(ctx, models.EventSource(source)), nil
+}
+
+func toFilters(req filtersRequest, hasInterviews *bool) *service.ListApplicationsFilters {
 	filters := &service.ListApplicationsFilters{}
 
 	// Get company filter
@@ -31,13 +93,26 @@ func (i *Server) ListApplications(ctx context.Context, req *applicationspb.ListA
 		filters.Company = company
 	}
 
-	// Convert status filter - only filter if explicitly set to REJECT or SUCCESS
+	// Convert status filter - only filter if explicitly set to a status other than PENDING
 	// (PENDING is 0, which is also the zero value, so we can't distinguish "unset" from "set to PENDING")
+	// statuses has to be used to filter PENDING applications
 	status := req.GetStatus()
-	if status == applicationspb.StatusType_REJECT || status == applicationspb.StatusType_SUCCESS {
+	if status != applicationspb.StatusType_PENDING {
 		gcpStatus := gcp.Status(status)
 		filters.Status = &gcpStatus
 	}
+	for _, status := range req.GetStatuses() {
+		filters.Statuses = append(filters.Statuses, gcp.Status(status))
+	}
+
+	filters.CompanyPrefix = req.GetCompanyPrefix()
+	filters.Query = req.GetQuery()
+
+	// Convert interview filters
+	filters.HasInterviews = hasInterviews
+	for _, interviewType := range req.GetInterviewTypes() {
+		filters.InterviewTypes = append(filters.InterviewTypes, models.InterviewType(interviewType))
+	}
 
 	// Convert date filters
 	if req.GetStartDate() != nil {
@@ -50,23 +125,37 @@ func (i *Server) ListApplications(ctx context.Context, req *applicationspb.ListA
 		filters.EndDate = &endDate
 	}
 
-	applications, err := i.service.ListApplications(ctx, filters)
+	return filters
+}
+
+func (i *Server) ListApplications(ctx context.Context, req *applicationspb.ListApplicationsRequest) (*applicationspb.ApplicationsResponse, error) {
+	filters := toFilters(req, req.HasInterviews)
+
+	// Paging and ordering
+	filters.PageSize = int(req.GetPageSize())
+	filters.PageToken = req.GetPageToken()
+	filters.OrderBy = req.GetOrderBy()
+
+	page, err := i.service.ListApplications(ctx, filters)
 	if err != nil {
-		return nil, err
+		return nil, i.toStatus(ctx, err)
 	}
 
-	pbApplications := make([]*applicationspb.Application, len(applications))
+	pbApplications := make([]*applicationspb.Application, len(page.Applications))
 
-	for i, application := range applications {
+	for i, application := range page.Applications {
 		pbApplications[i] = application.Pb()
 	}
 
 	return &applicationspb.ApplicationsResponse{
-		Applications: pbApplications,
+		Applications:  pbApplications,
+		NextPageToken: page.NextPageToken,
+		TotalSize:     int32(page.TotalSize),
+		Revision:      page.Revision,
 	}, nil
 }
 
-func (i *Server) SetApplications(ctx context.Context, req *applicationspb.SetApplicationsRequest) (*applicationspb.ApplicationsResponse, error) {
+func (i *Server) SetApplications(ctx context.Context, req *applicationspb.SetApplicationsRequest) (*applicationspb.SetApplicationsResponse, error) {
 	applications := make([]*models.Application, 0)
 
 	for _, application := range req.Applications {
@@ -74,26 +163,42 @@ func (i *Server) SetApplications(ctx context.Context, req *applicationspb.SetApp
 		applications = append(applications, a)
 	}
 
-	if err := i.service.SetApplications(ctx, applications); err != nil {
+	ctx, err := withEventSource(ctx, req.GetSource())
+	if err != nil {
 		return nil, err
 	}
+	results, err := i.service.SetApplications(ctx, applications)
+	if err != nil {
+		return nil, i.toStatus(ctx, err)
+	}
 
-	return &applicationspb.ApplicationsResponse{
-		Applications: req.Applications,
-	}, nil
+	// Respond with the stored applications, which carry the server assigned ids
+	res := &applicationspb.SetApplicationsResponse{
+		Applications: make([]*applicationspb.Application, 0, len(results)),
+		Results:      make([]*applicationspb.SetApplicationResult, len(results)),
+	}
+	for i, result := range results {
+		pbResult := &applicationspb.SetApplicationResult{
+			Index:  int32(i),
+			Result: applicationspb.SetApplicationResultType(result.Result),
+			Reason: result.Reason,
+		}
+		if result.Application != nil {
+			pbResult.Application = result.Application.Pb()
+			res.Applications = append(res.Applications, pbResult.Application)
+		}
+		res.Results[i] = pbResult
+	}
+
+	return res, nil
 }
 
 func (i *Server) SetInterviews(ctx context.Context, req *applicationspb.SetInterviewsRequest) (*applicationspb.SetInterviewsResponse, error) {
-	if req.GetDate() == nil {
-		return nil, fmt.Errorf("date is required")
-	}
-	if req.GetCompany() == "" {
-		return nil, fmt.Errorf("company is required")
+	ctx, err := withEventSource(ctx, req.GetSource())
+	if err != nil {
+		return nil, err
 	}
 
-	date := req.GetDate().AsTime()
-	company := req.GetCompany()
-
 	// Convert protobuf interviews to models
 	interviews := make([]*models.Interview, 0, len(req.GetInterviews()))
 	for _, pbInterview := range req.GetInterviews() {
@@ -101,13 +206,192 @@ func (i *Server) SetInterviews(ctx context.Context, req *applicationspb.SetInter
 		interviews = append(interviews, &interview)
 	}
 
+	// Application identified by id
+	if req.GetId() != "" {
+		update := &models.Application{
+			ID:         req.GetId(),
+			Interviews: make([]models.Interview, len(interviews)),
+		}
+		for i, interview := range interviews {
+			update.Interviews[i] = *interview
+		}
+		application, err := i.service.UpdateApplication(ctx, update, []string{"interviews"})
+		if err != nil {
+			return nil, i.toStatus(ctx, err)
+		}
+		return &applicationspb.SetInterviewsResponse{
+			Application: application.Pb(),
+		}, nil
+	}
+
+	if req.GetDate() == nil {
+		return nil, status.Error(codes.InvalidArgument, "date is required")
+	}
+	if req.GetCompany() == "" {
+		return nil, status.Error(codes.InvalidArgument, "company is required")
+	}
+
+	date := req.GetDate().AsTime()
+	company := req.GetCompany()
+
 	// Call service to set interviews
 	application, err := i.service.SetInterviews(ctx, date, company, interviews)
 	if err != nil {
-		return nil, err
+		return nil, i.toStatus(ctx, err)
 	}
 
 	return &applicationspb.SetInterviewsResponse{
 		Application: application.Pb(),
 	}, nil
 }
+
+func (i *Server) GetApplication(ctx context.Context, req *applicationspb.GetApplicationRequest) (*applicationspb.GetApplicationResponse, error) {
+	application, err := i.service.GetApplication(ctx, req.GetId())
+	if err != nil {
+		return nil, i.toStatus(ctx, err)
+	}
+
+	return &applicationspb.GetApplicationResponse{
+		Application: application.Pb(),
+	}, nil
+}
+
+func (i *Server) UpdateApplication(ctx context.Context, req *applicationspb.UpdateApplicationRequest) (*applicationspb.UpdateApplicationResponse, error) {
+	if req.GetApplication() == nil {
+		return nil, status.Error(codes.InvalidArgument, "application is required")
+	}
+
+	ctx, err := withEventSource(ctx, req.GetSource())
+	if err != nil {
+		return nil, err
+	}
+	application, err := i.service.UpdateApplication(ctx, models.NewApplication(req.GetApplication()), req.GetUpdateMask().GetPaths())
+	if err != nil {
+		return nil, i.toStatus(ctx, err)
+	}
+
+	return &applicationspb.UpdateApplicationResponse{
+		Application: application.Pb(),
+	}, nil
+}
+
+func (i *Server) DeleteApplication(ctx context.Context, req *applicationspb.DeleteApplicationRequest) (*applicationspb.DeleteApplicationResponse, error) {
+	if err := i.service.DeleteApplication(ctx, req.GetId()); err != nil {
+		return nil, i.toStatus(ctx, err)
+	}
+
+	return &applicationspb.DeleteApplicationResponse{}, nil
+}
+
+func (i *Server) GetStats(ctx context.Context, req *applicationspb.GetStatsRequest) (*applicationspb.GetStatsResponse, error) {
+	var start, end *time.Time
+	if req.GetStartDate() != nil {
+		startDate := req.GetStartDate().AsTime()
+		start = &startDate
+	}
+	if req.GetEndDate() != nil {
+		endDate := req.GetEndDate().AsTime()
+		end = &endDate
+	}
+
+	stats, err := i.service.GetStats(ctx, start, end)
+	if err != nil {
+		return nil, i.toStatus(ctx, err)
+	}
+
+	res := &applicationspb.GetStatsResponse{
+		Total:                     int32(stats.Total),
+		Responses:                 int32(stats.Responses),
+		ResponseRate:              stats.ResponseRate,
+		MedianTimeToFirstResponse: durationpb.New(stats.MedianTimeToFirstResponse),
+		P90TimeToFirstResponse:    durationpb.New(stats.P90TimeToFirstResponse),
+	}
+
+	for status := range applicationspb.StatusType_name {
+		res.StatusCounts = append(res.StatusCounts, &applicationspb.StatusCount{
+			Status:       applicationspb.StatusType(status),
+			Applications: int32(stats.ByStatus[gcp.Status(status)]),
+		})
+	}
+	sort.Slice(res.StatusCounts, func(i, j int) bool {
+		return res.StatusCounts[i].Status < res.StatusCounts[j].Status
+	})
+
+	for _, interview := range stats.Interviews {
+		res.Interviews = append(res.Interviews, &applicationspb.InterviewTypeStats{
+			InterviewType:  applicationspb.InterviewType(interview.InterviewType),
+			Applications:   int32(interview.Applications),
+			ConversionRate: interview.ConversionRate,
+			SuccessRate:    interview.SuccessRate,
+		})
+	}
+
+	for _, week := range stats.Weekly {
+		res.Weekly = append(res.Weekly, &applicationspb.WeeklyVolume{
+			WeekStart:    timestamppb.New(week.WeekStart),
+			Applications: int32(week.Applications),
+		})
+	}
+
+	for _, stage := range stats.Funnel {
+		res.Funnel = append(res.Funnel, &applicationspb.FunnelStage{
+			Stage:        stage.Stage,
+			Applications: int32(stage.Applications),
+		})
+	}
+
+	for _, bucket := range stats.ResponseTimes {
+		pbBucket := &applicationspb.ResponseTimeBucket{
+			Applications: int32(bucket.Applications),
+		}
+		if bucket.UpperBound > 0 {
+			pbBucket.UpperBound = durationpb.New(bucket.UpperBound)
+		}
+		res.ResponseTimes = append(res.ResponseTimes, pbBucket)
+	}
+
+	return res, nil
+}
+
+func (i *Server) ListApplicationEvents(ctx context.Context, req *applicationspb.ListApplicationEventsRequest) (*applicationspb.ListApplicationEventsResponse, error) {
+	events, err := i.service.ListApplicationEvents(ctx, req.GetId())
+	if err != nil {
+		return nil, i.toStatus(ctx, err)
+	}
+
+	res := &applicationspb.ListApplicationEventsResponse{
+		Events: make([]*applicationspb.ApplicationEvent, len(events)),
+	}
+	for i, event := range events {
+		res.Events[i] = event.Pb()
+	}
+
+	return res, nil
+}
+
+func (i *Server) WatchApplications(req *applicationspb.WatchApplicationsRequest, stream applicationspb.Applications_WatchApplicationsServer) error {
+	ctx := stream.Context()
+
+	changes, err := i.service.WatchApplications(ctx, toFilters(req, req.HasInterviews), req.GetFromRevision())
+	if err != nil {
+		return i.toStatus(ctx, err)
+	}
+
+	revision := req.GetFromRevision()
+	for change := range changes {
+		err := stream.Send(&applicationspb.WatchApplicationsResponse{
+			Revision:    change.Revision,
+			Type:        applicationspb.ChangeType(change.Type),
+			Application: change.Application.Pb(),
+		})
+		if err != nil {
+			return err
+		}
+		revision = change.Revision
+	}
+
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Errorf(codes.Aborted, "watch fell behind, resume from revision %d", revision)
+}
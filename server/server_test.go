@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	applicationspb "github.com/MaxBear/maxhire/proto/gen/go/applications/v1"
+	"github.com/MaxBear/maxhire/service"
+)
+
+func setup(t *testing.T) (applicationspb.ApplicationsClient, context.Context) {
+	ctx := context.Background()
+	svc, err := service.NewService(ctx, "")
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	applicationspb.RegisterApplicationsServer(grpcServer, New(svc))
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return applicationspb.NewApplicationsClient(conn), ctx
+}
+
+// create stores an application and returns it with its id
+func create(t *testing.T, client applicationspb.ApplicationsClient, ctx context.Context) *applicationspb.Application {
+	res, err := client.SetApplications(ctx, &applicationspb.SetApplicationsRequest{
+		Applications: []*applicationspb.Application{{
+			Date:     timestamppb.New(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)),
+			Company:  "TestCompany",
+			Position: "Software Engineer",
+			Status:   applicationspb.StatusType_APPLIED,
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.GetResults(), 1)
+	require.Equal(t, applicationspb.SetApplicationResultType_RESULT_CREATED, res.GetResults()[0].GetResult())
+	return res.GetResults()[0].GetApplication()
+}
+
+func TestNotFound(t *testing.T) {
+	client, ctx := setup(t)
+
+	_, err := client.GetApplication(ctx, &applicationspb.GetApplicationRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.UpdateApplication(ctx, &applicationspb.UpdateApplicationRequest{
+		Application: &applicationspb.Application{Id: "missing", Company: "TestCompany"},
+		UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"company"}},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.DeleteApplication(ctx, &applicationspb.DeleteApplicationRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestInvalidArgument(t *testing.T) {
+	client, ctx := setup(t)
+	app := create(t, client, ctx)
+
+	tcs := []struct {
+		name string
+		req  *applicationspb.UpdateApplicationRequest
+	}{
+		{"missing application", &applicationspb.UpdateApplicationRequest{}},
+		{"unknown mask path", &applicationspb.UpdateApplicationRequest{
+			Application: &applicationspb.Application{Id: app.GetId(), Company: "Renamed"},
+			UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"salary"}},
+		}},
+		{"invalid record", &applicationspb.UpdateApplicationRequest{
+			Application: &applicationspb.Application{Id: app.GetId()},
+			UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"company"}},
+		}},
+		{"illegal status transition", &applicationspb.UpdateApplicationRequest{
+			Application: &applicationspb.Application{Id: app.GetId(), Status: applicationspb.StatusType_PENDING},
+			UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+		}},
+		{"invalid source", &applicationspb.UpdateApplicationRequest{
+			Application: &applicationspb.Application{Id: app.GetId(), Company: "Renamed"},
+			UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"company"}},
+			Source:      applicationspb.EventSource(42),
+		}},
+	}
+
+	for _, tc := range tcs {
+		_, err := client.UpdateApplication(ctx, tc.req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), tc.name)
+	}
+
+	// nothing was changed
+	res, err := client.GetApplication(ctx, &applicationspb.GetApplicationRequest{Id: app.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, "TestCompany", res.GetApplication().GetCompany())
+	assert.Equal(t, applicationspb.StatusType_APPLIED, res.GetApplication().GetStatus())
+}
+
+func TestUpdateApplication_Mask(t *testing.T) {
+	client, ctx := setup(t)
+	app := create(t, client, ctx)
+
+	// the other fields of the request are left alone
+	res, err := client.UpdateApplication(ctx, &applicationspb.UpdateApplicationRequest{
+		Application: &applicationspb.Application{
+			Id:       app.GetId(),
+			Company:  "Ignored",
+			Position: "Staff Engineer",
+			Status:   applicationspb.StatusType_REJECT,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"position"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Staff Engineer", res.GetApplication().GetPosition())
+	assert.Equal(t, "TestCompany", res.GetApplication().GetCompany())
+	assert.Equal(t, applicationspb.StatusType_APPLIED, res.GetApplication().GetStatus())
+	assert.Equal(t, app.GetDate().AsTime(), res.GetApplication().GetDate().AsTime())
+
+	got, err := client.GetApplication(ctx, &applicationspb.GetApplicationRequest{Id: app.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, "Staff Engineer", got.GetApplication().GetPosition())
+	assert.Equal(t, "TestCompany", got.GetApplication().GetCompany())
+	assert.Equal(t, applicationspb.StatusType_APPLIED, got.GetApplication().GetStatus())
+}
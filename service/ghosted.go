@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+)
+
+// DefaultGhostedAfter is how long an application can go without a response before it is ghosted
+const DefaultGhostedAfter = 30 * 24 * time.Hour
+
+// ghostable reports whether the application is still waiting for a first response
+func ghostable(app *models.Application) bool {
+	if app.Status != gcp.Pending && app.Status != gcp.Applied {
+		return false
+	}
+	if app.Responded() {
+		return false
+	}
+	_, responded := app.FirstResponse()
+	return !responded
+}
+
+// awaitingResponse reports whether the application has no decision yet, ghosted included
+func awaitingResponse(app *models.Application) bool {
+	switch app.Status {
+	case gcp.Pending, gcp.Applied, gcp.Ghosted:
+		return true
+	}
+	return false
+}
+
+// MarkGhosted moves the applications without any response and no activity for longer than after
+// to Ghosted, recording the change as made by a rule. A later response for the same position moves them
+// out of Ghosted again, however late it arrives.
+// Applies to the applications of every tenant, returns the applications which were marked.
+func (s *serviceImpl) MarkGhosted(ctx context.Context, after time.Duration) ([]*models.Application, error) {
+	ctx, span := tracer.Start(ctx, "Service.MarkGhosted")
+	defer span.End()
+
+	if after <= 0 {
+		return nil, invalidArgument("invalid ghosted after duration %v", after)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenants, err := s.store.ListTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	marked := []*models.Application{}
+	for _, tenant := range tenants {
+		applications, err := s.store.ListApplications(ctx, tenant)
+		if err != nil {
+			return marked, err
+		}
+
+		for _, app := range applications {
+			if !ghostable(app) || now.Sub(app.LastActivity()) < after {
+				continue
+			}
+
+			before := app.Clone()
+			app.Status = gcp.Ghosted
+			app.RecordChanges(before, now, models.SourceRule)
+			if err := s.store.UpdateApplication(ctx, app); err != nil {
+				return marked, err
+			}
+			s.watch.publish(ChangeUpdated, app, before)
+			marked = append(marked, app)
+		}
+	}
+
+	return marked, nil
+}
+
+// RunGhostedDetection runs MarkGhosted right away and then every interval until ctx is done.
+func (s *serviceImpl) RunGhostedDetection(ctx context.Context, after, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		marked, err := s.MarkGhosted(ctx, after)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error detecting ghosted applications", "error", err)
+		} else if len(marked) > 0 {
+			s.logger.InfoContext(ctx, "marked applications without a response as ghosted", "count", len(marked), "after", after)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+)
+
+func TestMarkGhosted(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	old := now.AddDate(0, 0, -45)
+	results, err := svc.SetApplications(ctx, []*models.Application{
+		{Date: old, Company: "Silent", Position: "Software Engineer"},
+		{Date: old, Company: "Applied", Position: "Software Engineer", Status: gcp.Applied},
+		{Date: old, Company: "Rejected", Position: "Software Engineer", Status: gcp.Reject},
+		{Date: old, Company: "Interviewed", Position: "Software Engineer", Interviews: []models.Interview{
+			{DateTime: old.AddDate(0, 0, 5), InterviewType: models.RecruiterScreen, DurationMin: 30},
+		}},
+		{Date: now.AddDate(0, 0, -10), Company: "Recent", Position: "Software Engineer"},
+	})
+	require.NoError(t, err)
+	silent := results[0].Application.ID
+
+	marked, err := svc.MarkGhosted(ctx, DefaultGhostedAfter)
+	require.NoError(t, err)
+	require.Len(t, marked, 2)
+	assert.Equal(t, "Silent", marked[0].Company)
+	assert.Equal(t, "Applied", marked[1].Company)
+
+	app, err := svc.GetApplication(ctx, silent)
+	require.NoError(t, err)
+	assert.Equal(t, gcp.Ghosted, app.Status)
+	last := app.Events[len(app.Events)-1]
+	assert.Equal(t, models.StatusChanged, last.Type)
+	assert.Equal(t, models.SourceRule, last.Source)
+	assert.Equal(t, now, last.Time)
+	assert.Equal(t, gcp.Pending, last.PreviousStatus)
+	assert.Equal(t, gcp.Ghosted, last.Status)
+
+	for _, res := range results[2:] {
+		app, err := svc.GetApplication(ctx, res.Application.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, gcp.Ghosted, app.Status, app.Company)
+	}
+
+	// Running again marks nothing new
+	marked, err = svc.MarkGhosted(ctx, DefaultGhostedAfter)
+	require.NoError(t, err)
+	assert.Len(t, marked, 0)
+
+	// A late response revives the application
+	results, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: now, Company: "Silent", Position: "Software Engineer", Status: gcp.Interviewing},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Updated, results[0].Result)
+	assert.Equal(t, silent, results[0].Application.ID)
+	assert.Equal(t, gcp.Interviewing, results[0].Application.Status)
+
+	// A new application for the same position, long after the first one, is not merged
+	results, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: now, Company: "Applied", Position: "Software Engineer", Status: gcp.Applied},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Created, results[0].Result)
+}
+
+func TestMarkGhosted_InvalidArgument(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	_, err = svc.MarkGhosted(ctx, 0)
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
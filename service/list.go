@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+	"github.com/MaxBear/maxhire/storage"
+)
+
+// MaxPageSize caps ListApplicationsFilters.PageSize
+const MaxPageSize = 1000
+
+// DefaultPageSize is the page size of ListApplications when ListApplicationsFilters.PageSize is 0
+const DefaultPageSize = 100
+
+type ListApplicationsFilters struct {
+	Status    *gcp.Status
+	Company   string
+	StartDate *time.Time
+	EndDate   *time.Time
+
+	// Match any of the statuses
+	Statuses []gcp.Status
+	// Match Company as a prefix instead of the whole name
+	CompanyPrefix bool
+	// Every word must appear in the position or the subject, case-insensitive
+	Query string
+	// Match applications with (true) or without (false) interviews
+	HasInterviews *bool
+	// Match applications having an interview of any of the types
+	InterviewTypes []models.InterviewType
+
+	// Maximum number of applications to return, DefaultPageSize if 0
+	PageSize int
+	// NextPageToken of the previous page, must be used with the same filters and order.
+	// The token is an offset in the matching applications: pages may skip or repeat
+	// applications added, deleted or reordered between the calls.
+	PageToken string
+	// One of "date", "company", "status" or "last_activity", optionally followed by " desc",
+	// applications are returned in storage order if empty or blank
+	OrderBy string
+}
+
+// ApplicationsPage is one page of ListApplications results
+type ApplicationsPage struct {
+	Applications []*models.Application
+	// Token to retrieve the next page, empty on the last page
+	NextPageToken string
+	// Number of applications matching the filters across all pages
+	TotalSize int
+	// Revision of the applications, the page may already include changes made after it
+	Revision int64
+}
+
+// storageQuery returns the store query of the tenant selecting the applications passing the filters,
+// without order nor paging
+func (filters *ListApplicationsFilters) storageQuery(tenant string) *storage.Query {
+	return &storage.Query{
+		Tenant:         tenant,
+		Status:         filters.Status,
+		Statuses:       filters.Statuses,
+		Company:        filters.Company,
+		CompanyPrefix:  filters.CompanyPrefix,
+		Words:          strings.Fields(filters.Query),
+		HasInterviews:  filters.HasInterviews,
+		InterviewTypes: filters.InterviewTypes,
+		StartDate:      filters.StartDate,
+		EndDate:        filters.EndDate,
+	}
+}
+
+// Match reports whether the application passes the filters
+func (filters *ListApplicationsFilters) Match(app *models.Application) bool {
+	return filters.storageQuery(app.Tenant).Match(app)
+}
+
+// order sets the order of the query from OrderBy
+func (filters *ListApplicationsFilters) order(query *storage.Query) error {
+	fields := strings.Fields(strings.ToLower(filters.OrderBy))
+	if len(fields) == 0 {
+		// empty or blank, storage order
+		return nil
+	}
+	if len(fields) > 2 {
+		return invalidArgument("invalid order_by %q", filters.OrderBy)
+	}
+	if _, ok := storage.OrderKeys[fields[0]]; !ok {
+		return invalidArgument("invalid order_by field %q", fields[0])
+	}
+	query.OrderBy = fields[0]
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "asc":
+		case "desc":
+			query.Desc = true
+		default:
+			return invalidArgument("invalid order_by direction %q", fields[1])
+		}
+	}
+
+	return nil
+}
+
+type pageToken struct {
+	Offset int    `json:"o"`
+	Query  uint64 `json:"q"`
+}
+
+// query fingerprints everything but the paging fields, so a token can't be reused for another query
+func (filters *ListApplicationsFilters) query() uint64 {
+	q := *filters
+	q.PageSize = 0
+	q.PageToken = ""
+	b, _ := json.Marshal(q)
+
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+func (filters *ListApplicationsFilters) offset() (int, error) {
+	if filters.PageToken == "" {
+		return 0, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(filters.PageToken)
+	if err != nil {
+		return 0, invalidArgument("invalid page token")
+	}
+	var token pageToken
+	if err := json.Unmarshal(b, &token); err != nil || token.Offset < 0 {
+		return 0, invalidArgument("invalid page token")
+	}
+	if token.Query != filters.query() {
+		return 0, invalidArgument("page token does not match the filters and order")
+	}
+	return token.Offset, nil
+}
+
+func (filters *ListApplicationsFilters) nextPageToken(offset int) string {
+	b, _ := json.Marshal(pageToken{
+		Offset: offset,
+		Query:  filters.query(),
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ListApplications returns the page of applications matching the filters, in the requested order.
+// The store filters, sorts and pages the applications of the tenant.
+func (s *serviceImpl) ListApplications(ctx context.Context, filters *ListApplicationsFilters) (*ApplicationsPage, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListApplications")
+	defer span.End()
+
+	if filters == nil {
+		filters = &ListApplicationsFilters{}
+	}
+	if filters.PageSize < 0 {
+		return nil, invalidArgument("invalid page size %d", filters.PageSize)
+	}
+	offset, err := filters.offset()
+	if err != nil {
+		return nil, err
+	}
+	query := filters.storageQuery(Tenant(ctx))
+	if err := filters.order(query); err != nil {
+		return nil, err
+	}
+	query.Offset = offset
+	query.Limit = DefaultPageSize
+	if filters.PageSize > 0 {
+		query.Limit = min(filters.PageSize, MaxPageSize)
+	}
+
+	// read before listing, watching from it replays rather than misses the changes made meanwhile
+	revision := s.watch.currentRevision()
+	applications, total, err := s.store.QueryApplications(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ApplicationsPage{
+		Applications: applications,
+		TotalSize:    total,
+		Revision:     revision,
+	}
+	if end := offset + len(applications); end < total {
+		page.NextPageToken = filters.nextPageToken(end)
+	}
+
+	return page, nil
+}
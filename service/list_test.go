@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+)
+
+func setupList(t *testing.T) (*serviceImpl, context.Context) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 10, 0, 0, 0, time.UTC)
+	}
+	_, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: day(3), Company: "Charlie", Status: gcp.Reject},
+		{Date: day(1), Company: "alpha", Status: gcp.Pending},
+		{Date: day(5), Company: "Echo", Status: gcp.Applied},
+		{Date: day(2), Company: "Bravo", Status: gcp.Success, Interviews: []models.Interview{
+			{DateTime: day(20), InterviewType: models.TechCoding, DurationMin: 60},
+		}},
+		{Date: day(4), Company: "Delta", Status: gcp.Pending},
+	})
+	require.NoError(t, err)
+
+	return svc, ctx
+}
+
+func companies(applications []*models.Application) []string {
+	res := []string{}
+	for _, app := range applications {
+		res = append(res, app.Company)
+	}
+	return res
+}
+
+func TestListApplications_Paging(t *testing.T) {
+	svc, ctx := setupList(t)
+
+	filters := &ListApplicationsFilters{PageSize: 2, OrderBy: "date"}
+	got := []string{}
+	pages := 0
+	for {
+		page, err := svc.ListApplications(ctx, filters)
+		require.NoError(t, err)
+		assert.Equal(t, 5, page.TotalSize)
+		got = append(got, companies(page.Applications)...)
+		pages++
+		if page.NextPageToken == "" {
+			break
+		}
+		filters.PageToken = page.NextPageToken
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"alpha", "Bravo", "Charlie", "Delta", "Echo"}, got)
+}
+
+func TestListApplications_OrderBy(t *testing.T) {
+	svc, ctx := setupList(t)
+
+	tcs := []struct {
+		orderBy string
+		res     []string
+	}{
+		{"", []string{"Charlie", "alpha", "Echo", "Bravo", "Delta"}},
+		{"  ", []string{"Charlie", "alpha", "Echo", "Bravo", "Delta"}},
+		{"date desc", []string{"Echo", "Delta", "Charlie", "Bravo", "alpha"}},
+		{"company", []string{"alpha", "Bravo", "Charlie", "Delta", "Echo"}},
+		{"status", []string{"alpha", "Delta", "Charlie", "Bravo", "Echo"}},
+		{"last_activity desc", []string{"Bravo", "Echo", "Delta", "Charlie", "alpha"}},
+	}
+
+	for _, tc := range tcs {
+		page, err := svc.ListApplications(ctx, &ListApplicationsFilters{OrderBy: tc.orderBy})
+		require.NoError(t, err)
+		assert.Equal(t, tc.res, companies(page.Applications), tc.orderBy)
+		assert.Empty(t, page.NextPageToken)
+	}
+}
+
+func TestListApplications_InvalidArguments(t *testing.T) {
+	svc, ctx := setupList(t)
+
+	_, err := svc.ListApplications(ctx, &ListApplicationsFilters{OrderBy: "salary"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	_, err = svc.ListApplications(ctx, &ListApplicationsFilters{OrderBy: "date sideways"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	_, err = svc.ListApplications(ctx, &ListApplicationsFilters{PageToken: "garbage"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	// A token can't be reused with different filters
+	page, err := svc.ListApplications(ctx, &ListApplicationsFilters{PageSize: 1, OrderBy: "date"})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextPageToken)
+	_, err = svc.ListApplications(ctx, &ListApplicationsFilters{PageSize: 1, OrderBy: "company", PageToken: page.NextPageToken})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestListApplications_Filters(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 10, 0, 0, 0, time.UTC)
+	}
+	_, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: day(1), Company: "Stripe", Position: "Backend Engineer, Data", Subject: "Thanks for applying to Stripe!", Status: gcp.Pending},
+		{Date: day(2), Company: "Stripe Payments", Position: "Full Stack Engineer", Status: gcp.Applied},
+		{Date: day(3), Company: "Lyft", Position: "Software Engineer", Subject: "Infrastructure Automation", Status: gcp.Reject,
+			Interviews: []models.Interview{{DateTime: day(10), InterviewType: models.RecruiterScreen, DurationMin: 30}}},
+		{Date: day(4), Company: "Zapier", Position: "Sr. Software Engineer (L4)", Status: gcp.Success,
+			Interviews: []models.Interview{{DateTime: day(12), InterviewType: models.TechCoding, DurationMin: 60}}},
+	})
+	require.NoError(t, err)
+
+	hasInterviews := true
+	noInterviews := false
+
+	tcs := []struct {
+		name    string
+		filters *ListApplicationsFilters
+		res     []string
+	}{
+		{"company is case-insensitive", &ListApplicationsFilters{Company: "stripe"}, []string{"Stripe"}},
+		{"company prefix", &ListApplicationsFilters{Company: "STRIPE", CompanyPrefix: true}, []string{"Stripe", "Stripe Payments"}},
+		{"pending and applied", &ListApplicationsFilters{Statuses: []gcp.Status{gcp.Pending, gcp.Applied}}, []string{"Stripe", "Stripe Payments"}},
+		{"query over position", &ListApplicationsFilters{Query: "software engineer"}, []string{"Lyft", "Zapier"}},
+		{"query over subject", &ListApplicationsFilters{Query: "automation"}, []string{"Lyft"}},
+		{"query words span position and subject", &ListApplicationsFilters{Query: "backend applying"}, []string{"Stripe"}},
+		{"has interviews", &ListApplicationsFilters{HasInterviews: &hasInterviews}, []string{"Lyft", "Zapier"}},
+		{"has no interviews", &ListApplicationsFilters{HasInterviews: &noInterviews}, []string{"Stripe", "Stripe Payments"}},
+		{"interview type", &ListApplicationsFilters{InterviewTypes: []models.InterviewType{models.TechCoding, models.TeamMatch}}, []string{"Zapier"}},
+	}
+
+	for _, tc := range tcs {
+		page, err := svc.ListApplications(ctx, tc.filters)
+		require.NoError(t, err)
+		assert.Equal(t, tc.res, companies(page.Applications), tc.name)
+	}
+}
+
+func TestListApplications_DefaultPageSize(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	date := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	applications := []*models.Application{}
+	for i := range DefaultPageSize + 5 {
+		applications = append(applications, &models.Application{Date: date, Company: fmt.Sprintf("Company %03d", i)})
+	}
+	_, err = svc.SetApplications(ctx, applications)
+	require.NoError(t, err)
+
+	page, err := svc.ListApplications(ctx, &ListApplicationsFilters{})
+	require.NoError(t, err)
+	assert.Len(t, page.Applications, DefaultPageSize)
+	assert.Equal(t, DefaultPageSize+5, page.TotalSize)
+	require.NotEmpty(t, page.NextPageToken)
+
+	page, err = svc.ListApplications(ctx, &ListApplicationsFilters{PageToken: page.NextPageToken})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Company 100", "Company 101", "Company 102", "Company 103", "Company 104"}, companies(page.Applications))
+	assert.Empty(t, page.NextPageToken)
+}
@@ -2,114 +2,362 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
 	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
 	"github.com/MaxBear/maxhire/models"
+	"github.com/MaxBear/maxhire/storage"
+	"github.com/MaxBear/maxhire/storage/memory"
+)
+
+var tracer = otel.Tracer("github.com/MaxBear/maxhire/service")
+
+var (
+	ErrNotFound        = storage.ErrNotFound
+	ErrInvalidArgument = errors.New("invalid argument")
 )
 
 type Service interface {
-	SetApplications(context.Context, []*models.Application) error
-	ListApplications(context.Context, *ListApplicationsFilters) ([]*models.Application, error)
+	SetApplications(context.Context, []*models.Application) ([]*SetApplicationResult, error)
+	ListApplications(context.Context, *ListApplicationsFilters) (*ApplicationsPage, error)
 	SetInterviews(context.Context, time.Time, string, []*models.Interview) (*models.Application, error)
+	GetApplication(context.Context, string) (*models.Application, error)
+	UpdateApplication(context.Context, *models.Application, []string) (*models.Application, error)
+	DeleteApplication(context.Context, string) error
+	GetStats(context.Context, *time.Time, *time.Time) (*Stats, error)
+	ListApplicationEvents(context.Context, string) ([]models.Event, error)
+	MarkGhosted(context.Context, time.Duration) ([]*models.Application, error)
+	WatchApplications(context.Context, *ListApplicationsFilters, int64) (<-chan Change, error)
+}
+
+type eventSourceKey struct{}
+
+// WithEventSource returns a context whose changes are recorded in application timelines as made by source,
+// changes are recorded as manual by default.
+func WithEventSource(ctx context.Context, source models.EventSource) context.Context {
+	return context.WithValue(ctx, eventSourceKey{}, source)
+}
+
+func eventSource(ctx context.Context) models.EventSource {
+	if source, ok := ctx.Value(eventSourceKey{}).(models.EventSource); ok {
+		return source
+	}
+	return models.SourceManual
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context whose calls only see and create the applications of tenant. Calls without
+// a tenant work on the applications stored without authentication.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// Tenant returns the tenant of the context, see WithTenant
+func Tenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// DefaultDedupWindow is how far apart two records with the same company and position can be
+// to be considered the same application.
+const DefaultDedupWindow = 24 * time.Hour
+
+type SetResult int
+
+// The values match the SetApplicationResultType of the api, unspecified being the zero value
+const (
+	Unspecified SetResult = iota // 0
+	Created                      // 1
+	Updated                      // 2
+	Unchanged                    // 3
+	Rejected                     // 4
+)
+
+func (r SetResult) String() string {
+	names := [...]string{"Unspecified", "Created", "Updated", "Unchanged", "Rejected"}
+	if r < 0 || int(r) >= len(names) {
+		return fmt.Sprintf("SetResult(%d)", int(r))
+	}
+	return names[r]
 }
 
-type ListApplicationsFilters struct {
-	Status    *gcp.Status
-	Company   string
-	StartDate *time.Time
-	EndDate   *time.Time
+// SetApplicationResult reports what SetApplications did with one record
+type SetApplicationResult struct {
+	// The stored application, nil if the record was rejected
+	Application *models.Application
+	Result      SetResult
+	// Why the record was rejected
+	Reason string
+}
+
+type ServiceOpt func(*serviceImpl)
+
+// WithStore sets the storage backend, applications are kept in memory by default.
+func WithStore(store storage.Store) ServiceOpt {
+	return func(s *serviceImpl) {
+		s.store = store
+	}
 }
 
-func NewService(ctx context.Context, jsonFile string) (*serviceImpl, error) {
-	applications := []*models.Application{}
+// WithDedupWindow sets how far apart the dates of two records with the same company and
+// position can be for SetApplications to merge them, defaults to DefaultDedupWindow.
+func WithDedupWindow(window time.Duration) ServiceOpt {
+	return func(s *serviceImpl) {
+		s.dedupWindow = window
+	}
+}
+
+// WithWatchHistory sets how many of the latest changes are kept for WatchApplications to resume from,
+// defaults to DefaultWatchHistory.
+func WithWatchHistory(size int) ServiceOpt {
+	return func(s *serviceImpl) {
+		s.watchHistory = size
+	}
+}
+
+// WithLogger sets the logger of the service, defaults to slog.Default()
+func WithLogger(logger *slog.Logger) ServiceOpt {
+	return func(s *serviceImpl) {
+		s.logger = logger
+	}
+}
+
+func NewService(ctx context.Context, jsonFile string, opts ...ServiceOpt) (*serviceImpl, error) {
+	s := &serviceImpl{
+		ctx:          ctx,
+		logger:       slog.Default(),
+		store:        memory.New(),
+		dedupWindow:  DefaultDedupWindow,
+		watchHistory: DefaultWatchHistory,
+		now:          time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.watch = newWatchHub(s.watchHistory)
 
 	if len(jsonFile) > 0 {
 		emails, err := gcp.FromJson(jsonFile)
 		if err != nil {
-			log.Printf("failed to load application records from %s, error: %s", jsonFile, err.Error())
+			s.logger.ErrorContext(ctx, "failed to load application records", "file", jsonFile, "error", err)
 			return nil, err
 		}
+		applications := []*models.Application{}
 		for _, email := range emails {
 			applications = append(applications, models.ToApplication(email))
 		}
-		log.Printf("Successfully loaded %d applications from %s", len(emails), jsonFile)
+		// records in json files are produced by the LLM analyzer
+		results, err := s.SetApplications(WithEventSource(ctx, models.SourceLlm), applications)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to store application records", "file", jsonFile, "error", err)
+			return nil, err
+		}
+		counts := make(map[SetResult]int)
+		for i, result := range results {
+			counts[result.Result]++
+			if result.Result == Rejected {
+				s.logger.WarnContext(ctx, "skipped application record", "file", jsonFile, "index", i, "reason", result.Reason)
+			}
+		}
+		s.logger.InfoContext(ctx, "loaded application records", "file", jsonFile, "count", len(emails),
+			"created", counts[Created], "updated", counts[Updated], "unchanged", counts[Unchanged], "rejected", counts[Rejected])
 	}
 
-	return &serviceImpl{
-		ctx:          ctx,
-		applications: applications,
-	}, nil
+	return s, nil
 }
 
 type serviceImpl struct {
-	mu           sync.RWMutex
-	applications []*models.Application
-	ctx          context.Context
+	// mu serializes read-modify-write sequences against the store
+	mu          sync.Mutex
+	store       storage.Store
+	dedupWindow time.Duration
+	now         func() time.Time
+	ctx         context.Context
+	logger      *slog.Logger
+
+	watchHistory int
+	watch        *watchHub
+}
+
+func invalidArgument(format string, a ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidArgument, fmt.Sprintf(format, a...))
 }
 
-func (s *serviceImpl) ListApplications(ctx context.Context, filters *ListApplicationsFilters) ([]*models.Application, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// listApplications returns the stored applications of the tenant of ctx
+func (s *serviceImpl) listApplications(ctx context.Context) ([]*models.Application, error) {
+	return s.store.ListApplications(ctx, Tenant(ctx))
+}
 
-	if filters == nil {
-		return s.applications, nil
+// getApplication returns the stored application of the tenant of ctx with the id
+func (s *serviceImpl) getApplication(ctx context.Context, id string) (*models.Application, error) {
+	application, err := s.store.GetApplication(ctx, Tenant(ctx), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("%w with id %s", ErrNotFound, id)
 	}
+	return application, err
+}
 
-	var filtered []*models.Application
-	for _, app := range s.applications {
-		// Filter by company
-		if filters.Company != "" && app.Company != filters.Company {
+// SetApplications upserts the applications: a record describing an already stored application
+// (see models.Application.SameAs) is merged into it instead of being added again. Invalid records
+// are rejected without failing the others, the result of every record is returned in order. The records
+// are not modified, the results carry the stored applications and their ids.
+func (s *serviceImpl) SetApplications(ctx context.Context, applications []*models.Application) ([]*SetApplicationResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetApplications")
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.listApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	source := eventSource(ctx)
+	results := make([]*SetApplicationResult, len(applications))
+	created := []*models.Application{}
+	isCreated := make(map[*models.Application]bool)
+	updated := []*models.Application{}
+	isUpdated := make(map[*models.Application]bool)
+	// stored state of the updated applications, for watchers
+	previous := make(map[*models.Application]*models.Application)
+
+	for i, application := range applications {
+		if err := application.Validate(); err != nil {
+			results[i] = &SetApplicationResult{
+				Result: Rejected,
+				Reason: err.Error(),
+			}
 			continue
 		}
 
-		// Filter by status
-		if filters.Status != nil && app.Status != *filters.Status {
+		match := s.match(existing, application)
+		if match == nil {
+			// the records of the caller are left alone, the id is returned with the stored application
+			stored := application.Clone()
+			stored.ID = uuid.NewString()
+			stored.Tenant = Tenant(ctx)
+			// the timeline is recorded by the service, never taken from clients
+			stored.Events = nil
+			stored.RecordChanges(nil, stored.Date, source)
+			stored.LinkEmail(stored.MessageID, stored.Date, source)
+			existing = append(existing, stored)
+			created = append(created, stored)
+			isCreated[stored] = true
+			results[i] = &SetApplicationResult{
+				Application: stored,
+				Result:      Created,
+			}
 			continue
 		}
 
-		// Filter by start date
-		if filters.StartDate != nil && app.Date.Before(*filters.StartDate) {
+		record := application
+		if match.HasMessage(application.MessageID) {
+			// the email was merged before, its status is not news anymore
+			record = application.Clone()
+			record.Status = match.Status
+		}
+		if record.Status != gcp.Pending && !match.Status.CanTransitionTo(record.Status) {
+			results[i] = &SetApplicationResult{
+				Result: Rejected,
+				Reason: fmt.Sprintf("illegal status transition from %s to %s", match.Status, record.Status),
+			}
 			continue
 		}
 
-		// Filter by end date
-		if filters.EndDate != nil && app.Date.After(*filters.EndDate) {
+		before := match.Clone()
+		if _, ok := previous[match]; !ok && !isCreated[match] {
+			previous[match] = before
+		}
+		changed := match.Merge(record)
+		if changed {
+			// changes happened when the email of the record was received
+			match.RecordChanges(before, application.Date, source)
+		}
+		if linked := match.LinkEmail(application.MessageID, application.Date, source); !changed && !linked {
+			results[i] = &SetApplicationResult{
+				Application: match,
+				Result:      Unchanged,
+			}
 			continue
 		}
 
-		filtered = append(filtered, app)
+		results[i] = &SetApplicationResult{
+			Application: match,
+			Result:      Updated,
+		}
+		if !isCreated[match] && !isUpdated[match] {
+			updated = append(updated, match)
+			isUpdated[match] = true
+		}
 	}
 
-	return filtered, nil
-}
-
-func (s *serviceImpl) SetApplications(ctx context.Context, applications []*models.Application) error {
-	for _, application := range applications {
-		err := application.Validate()
-		if err != nil {
-			return fmt.Errorf("invalid application found %+v, error: %s", *application, err.Error())
+	// the batch is stored at once, none of it is if it fails
+	if len(created) > 0 || len(updated) > 0 {
+		if err := s.store.SaveApplications(ctx, created, updated); err != nil {
+			return nil, err
 		}
 	}
+	for _, application := range created {
+		s.watch.publish(ChangeCreated, application, nil)
+	}
+	for _, application := range updated {
+		s.watch.publish(ChangeUpdated, application, previous[application])
+	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return results, nil
+}
 
-	s.applications = append(s.applications, applications...)
+// match returns the stored application the record describes, see models.Application.SameAs. A response
+// arriving after the dedup window, e.g. a rejection weeks later, is matched to the latest application for
+// the same position still waiting for a response, so ghosted applications are revived.
+func (s *serviceImpl) match(existing []*models.Application, record *models.Application) *models.Application {
+	for _, app := range existing {
+		if app.SameAs(record, s.dedupWindow) {
+			return app
+		}
+	}
+	if !record.Responded() {
+		return nil
+	}
 
-	return nil
+	var match *models.Application
+	for _, app := range existing {
+		if !awaitingResponse(app) || !app.SamePosition(record) || app.Date.After(record.Date) {
+			continue
+		}
+		if match == nil || app.Date.After(match.Date) {
+			match = app
+		}
+	}
+	return match
 }
 
 func (s *serviceImpl) SetInterviews(ctx context.Context, date time.Time, company string, interviews []*models.Interview) (*models.Application, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetInterviews")
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	applications, err := s.listApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Find the application by date and company
 	var foundApp *models.Application
-	for _, app := range s.applications {
+	for _, app := range applications {
 		if app.Date.Equal(date) && app.Company == company {
 			foundApp = app
 			break
@@ -117,7 +365,7 @@ func (s *serviceImpl) SetInterviews(ctx context.Context, date time.Time, company
 	}
 
 	if foundApp == nil {
-		return nil, fmt.Errorf("application not found for date %v and company %s", date, company)
+		return nil, fmt.Errorf("%w for date %v and company %s", ErrNotFound, date, company)
 	}
 
 	// Convert []*models.Interview to []models.Interview
@@ -127,7 +375,137 @@ func (s *serviceImpl) SetInterviews(ctx context.Context, date time.Time, company
 	}
 
 	// Set the interviews (replace existing)
+	before := foundApp.Clone()
 	foundApp.Interviews = interviewSlice
+	foundApp.RecordChanges(before, s.now(), eventSource(ctx))
+
+	if err := s.store.UpdateApplication(ctx, foundApp); err != nil {
+		return nil, err
+	}
+	s.watch.publish(ChangeUpdated, foundApp, before)
 
 	return foundApp, nil
 }
+
+func (s *serviceImpl) GetApplication(ctx context.Context, id string) (*models.Application, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetApplication")
+	defer span.End()
+
+	if id == "" {
+		return nil, invalidArgument("id is required")
+	}
+
+	return s.getApplication(ctx, id)
+}
+
+// updatableFields maps field mask paths to the function copying the field
+var updatableFields = map[string]func(dst, src *models.Application){
+	"date":       func(dst, src *models.Application) { dst.Date = src.Date },
+	"company":    func(dst, src *models.Application) { dst.Company = src.Company },
+	"position":   func(dst, src *models.Application) { dst.Position = src.Position },
+	"status":     func(dst, src *models.Application) { dst.Status = src.Status },
+	"interviews": func(dst, src *models.Application) { dst.Interviews = src.Interviews },
+}
+
+// UpdateApplication copies the fields listed in paths from update to the stored application
+// with the same ID, all updatable fields are copied if paths is empty.
+func (s *serviceImpl) UpdateApplication(ctx context.Context, update *models.Application, paths []string) (*models.Application, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateApplication")
+	defer span.End()
+
+	if update.ID == "" {
+		return nil, invalidArgument("id is required")
+	}
+
+	if len(paths) == 0 {
+		for path := range updatableFields {
+			paths = append(paths, path)
+		}
+	}
+	for _, path := range paths {
+		if _, ok := updatableFields[path]; !ok {
+			return nil, invalidArgument("field %q can not be updated", path)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	application, err := s.getApplication(ctx, update.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := application.Clone()
+	for _, path := range paths {
+		updatableFields[path](application, update)
+	}
+	if application.Interviews == nil {
+		application.Interviews = []models.Interview{}
+	}
+
+	if err := application.Validate(); err != nil {
+		return nil, invalidArgument("invalid application update %+v, error: %s", *application, err.Error())
+	}
+	if !before.Status.CanTransitionTo(application.Status) {
+		return nil, invalidArgument("illegal status transition from %s to %s", before.Status, application.Status)
+	}
+	application.RecordChanges(before, s.now(), eventSource(ctx))
+
+	if err := s.store.UpdateApplication(ctx, application); err != nil {
+		return nil, err
+	}
+	s.watch.publish(ChangeUpdated, application, before)
+
+	return application, nil
+}
+
+func (s *serviceImpl) DeleteApplication(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteApplication")
+	defer span.End()
+
+	if id == "" {
+		return invalidArgument("id is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// watchers are sent the deleted application
+	application, err := s.getApplication(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.store.DeleteApplication(ctx, Tenant(ctx), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("%w with id %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return err
+	}
+	s.watch.publish(ChangeDeleted, application, application)
+
+	return nil
+}
+
+// ListApplicationEvents returns the timeline of the application ordered by time
+func (s *serviceImpl) ListApplicationEvents(ctx context.Context, id string) ([]models.Event, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListApplicationEvents")
+	defer span.End()
+
+	application, err := s.GetApplication(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	events := application.Events
+	if events == nil {
+		events = []models.Event{}
+	}
+	// stable, so events recorded at the same time keep their recorded order
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	return events, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
 	"github.com/MaxBear/maxhire/models"
+	"github.com/MaxBear/maxhire/storage/memory"
 )
 
 func TestSetInterviews_Success(t *testing.T) {
@@ -29,7 +31,7 @@ func TestSetInterviews_Success(t *testing.T) {
 	}
 
 	// Add the application to the service
-	err = svc.SetApplications(ctx, []*models.Application{application})
+	_, err = svc.SetApplications(ctx, []*models.Application{application})
 	require.NoError(t, err)
 
 	// Create interviews to set
@@ -107,7 +109,7 @@ func TestSetInterviews_ReplaceExistingInterviews(t *testing.T) {
 	}
 
 	// Add the application to the service
-	err = svc.SetApplications(ctx, []*models.Application{application})
+	_, err = svc.SetApplications(ctx, []*models.Application{application})
 	require.NoError(t, err)
 
 	// Create new interviews to replace the existing ones
@@ -172,7 +174,7 @@ func TestSetInterviews_EmptyInterviewsList(t *testing.T) {
 	}
 
 	// Add the application to the service
-	err = svc.SetApplications(ctx, []*models.Application{application})
+	_, err = svc.SetApplications(ctx, []*models.Application{application})
 	require.NoError(t, err)
 
 	// Set empty interviews list
@@ -210,7 +212,7 @@ func TestSetInterviews_MultipleApplicationsSameCompany(t *testing.T) {
 	}
 
 	// Add both applications
-	err = svc.SetApplications(ctx, []*models.Application{application1, application2})
+	_, err = svc.SetApplications(ctx, []*models.Application{application1, application2})
 	require.NoError(t, err)
 
 	// Set interviews for the first application
@@ -248,9 +250,9 @@ func TestSetInterviews_MultipleApplicationsSameCompany(t *testing.T) {
 	assert.Equal(t, "Senior Software Engineer", result2.Position)
 
 	// Verify the first application's interviews weren't affected
-	allApps, err := svc.ListApplications(ctx, nil)
+	page, err := svc.ListApplications(ctx, nil)
 	require.NoError(t, err)
-	for _, app := range allApps {
+	for _, app := range page.Applications {
 		if app.Date.Equal(date1) && app.Company == testCompany {
 			assert.Len(t, app.Interviews, 1)
 			assert.Equal(t, models.RecruiterScreen, app.Interviews[0].InterviewType)
@@ -281,7 +283,7 @@ func TestSetInterviews_AllInterviewTypes(t *testing.T) {
 	}
 
 	// Add the application
-	err = svc.SetApplications(ctx, []*models.Application{application})
+	_, err = svc.SetApplications(ctx, []*models.Application{application})
 	require.NoError(t, err)
 
 	// Create interviews with all interview types
@@ -344,3 +346,436 @@ func TestSetInterviews_AllInterviewTypes(t *testing.T) {
 	assert.True(t, interviewTypes[models.TeamMatch])
 	assert.Equal(t, int32(30), durationMap[models.TeamMatch])
 }
+
+func TestSetApplications_AssignsIds(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	// Same company and timestamp, only distinguishable by id
+	application1 := &models.Application{Date: testDate, Company: "TestCompany", Position: "Software Engineer"}
+	application2 := &models.Application{Date: testDate, Company: "TestCompany", Position: "Staff Engineer"}
+
+	results, err := svc.SetApplications(ctx, []*models.Application{application1, application2})
+	require.NoError(t, err)
+	id1, id2 := results[0].Application.ID, results[1].Application.ID
+	require.NotEmpty(t, id1)
+	require.NotEmpty(t, id2)
+	assert.NotEqual(t, id1, id2)
+	// the records of the caller are not changed
+	assert.Empty(t, application1.ID)
+	assert.Empty(t, application2.ID)
+
+	result, err := svc.GetApplication(ctx, id2)
+	require.NoError(t, err)
+	assert.Equal(t, "Staff Engineer", result.Position)
+}
+
+func TestGetApplication_Errors(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	_, err = svc.GetApplication(ctx, "")
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	_, err = svc.GetApplication(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpdateApplication_FieldMask(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	application := &models.Application{
+		Date:     testDate,
+		Company:  "TestCompany",
+		Position: "Software Engineer",
+		Status:   gcp.Pending,
+		Interviews: []models.Interview{
+			{DateTime: testDate.Add(24 * time.Hour), InterviewType: models.RecruiterScreen, DurationMin: 30},
+		},
+	}
+	results, err := svc.SetApplications(ctx, []*models.Application{application})
+	require.NoError(t, err)
+	id := results[0].Application.ID
+
+	// Only the status is copied, the empty position and interviews are ignored
+	result, err := svc.UpdateApplication(ctx, &models.Application{ID: id, Status: gcp.Reject}, []string{"status"})
+	require.NoError(t, err)
+	assert.Equal(t, gcp.Reject, result.Status)
+	assert.Equal(t, "Software Engineer", result.Position)
+	assert.Len(t, result.Interviews, 1)
+
+	stored, err := svc.GetApplication(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, gcp.Reject, stored.Status)
+	assert.Equal(t, "Software Engineer", stored.Position)
+	assert.Equal(t, testDate, stored.Date)
+}
+
+func TestUpdateApplication_Errors(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	application := &models.Application{Date: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Company: "TestCompany"}
+	results, err := svc.SetApplications(ctx, []*models.Application{application})
+	require.NoError(t, err)
+	id := results[0].Application.ID
+
+	_, err = svc.UpdateApplication(ctx, &models.Application{ID: id}, []string{"id"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	// Clearing the company fails validation
+	_, err = svc.UpdateApplication(ctx, &models.Application{ID: id}, []string{"company"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	_, err = svc.UpdateApplication(ctx, &models.Application{ID: id, Status: gcp.Status(42)}, []string{"status"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	_, err = svc.UpdateApplication(ctx, &models.Application{ID: "missing"}, []string{"status"})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDeleteApplication(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	application := &models.Application{Date: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Company: "TestCompany"}
+	results, err := svc.SetApplications(ctx, []*models.Application{application})
+	require.NoError(t, err)
+	id := results[0].Application.ID
+
+	require.NoError(t, svc.DeleteApplication(ctx, id))
+
+	_, err = svc.GetApplication(ctx, id)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, svc.DeleteApplication(ctx, id), ErrNotFound)
+}
+
+func TestSetApplications_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	batch := func() []*models.Application {
+		return []*models.Application{
+			{Date: testDate, Company: "TestCompany", Position: "Software Engineer"},
+			{Date: testDate.Add(time.Hour), Company: "OtherCompany", Position: "Backend Engineer"},
+		}
+	}
+
+	results, err := svc.SetApplications(ctx, batch())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, Created, results[0].Result)
+	assert.Equal(t, Created, results[1].Result)
+
+	// Loading the same records again must not duplicate them
+	results, err = svc.SetApplications(ctx, batch())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, Unchanged, results[0].Result)
+	assert.Equal(t, Unchanged, results[1].Result)
+
+	page, err := svc.ListApplications(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, page.Applications, 2)
+}
+
+// failingStore fails to save the batches of SetApplications
+type failingStore struct {
+	*memory.Store
+}
+
+func (s failingStore) SaveApplications(ctx context.Context, added, updated []*models.Application) error {
+	return errors.New("disk full")
+}
+
+func TestSetApplications_Atomic(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, store.AddApplications(ctx, []*models.Application{{ID: "1", Date: testDate, Company: "TestCompany", Position: "Software Engineer"}}))
+
+	svc, err := NewService(ctx, "", WithStore(failingStore{store}))
+	require.NoError(t, err)
+	page, err := svc.ListApplications(ctx, nil)
+	require.NoError(t, err)
+	revision := page.Revision
+
+	// neither the update nor the creation of the failed batch is stored or published
+	_, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: testDate, Company: "TestCompany", Position: "Software Engineer", Status: gcp.Interviewing},
+		{Date: testDate, Company: "OtherCompany", Position: "Backend Engineer"},
+	})
+	assert.EqualError(t, err, "disk full")
+
+	page, err = svc.ListApplications(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Applications, 1)
+	assert.Equal(t, gcp.Pending, page.Applications[0].Status)
+	assert.Equal(t, revision, page.Revision)
+}
+
+func TestSetApplications_Merge(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	results, err := svc.SetApplications(ctx, []*models.Application{
+		{Date: testDate, Company: "TestCompany", Position: "Software Engineer", Status: gcp.Pending},
+	})
+	require.NoError(t, err)
+	id := results[0].Application.ID
+
+	// A record for the same company and position a few hours later, with a case difference in the
+	// company name, updates the stored application
+	results, err = svc.SetApplications(ctx, []*models.Application{
+		{
+			Date:     testDate.Add(3 * time.Hour),
+			Company:  "testcompany",
+			Position: "Software Engineer",
+			Status:   gcp.Reject,
+			Interviews: []models.Interview{
+				{DateTime: testDate.Add(48 * time.Hour), InterviewType: models.RecruiterScreen, DurationMin: 30},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, Updated, results[0].Result)
+	assert.Equal(t, id, results[0].Application.ID)
+
+	stored, err := svc.GetApplication(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, testDate, stored.Date, "earliest date is kept")
+	assert.Equal(t, "TestCompany", stored.Company)
+	assert.Equal(t, gcp.Reject, stored.Status)
+	assert.Len(t, stored.Interviews, 1)
+}
+
+func TestSetApplications_MessageId(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	_, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: testDate, Company: "TestCompany", MessageID: "msg-1"},
+	})
+	require.NoError(t, err)
+
+	// Same source email, the position extracted later is merged in
+	results, err := svc.SetApplications(ctx, []*models.Application{
+		{Date: testDate, Company: "TestCompany", Position: "Software Engineer", MessageID: "msg-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Updated, results[0].Result)
+
+	page, err := svc.ListApplications(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Applications, 1)
+	assert.Equal(t, "Software Engineer", page.Applications[0].Position)
+}
+
+func TestSetApplications_RejectInvalidRecords(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	results, err := svc.SetApplications(ctx, []*models.Application{
+		{Company: "NoDate"},
+		{Date: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Company: "TestCompany"},
+		{Date: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{Date: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Company: "OtherCompany", Status: gcp.Status(42)},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, Rejected, results[0].Result)
+	assert.Equal(t, "invalid date", results[0].Reason)
+	assert.Nil(t, results[0].Application)
+	assert.Equal(t, Created, results[1].Result)
+	assert.Equal(t, Rejected, results[2].Result)
+	assert.Equal(t, "invalid company name", results[2].Reason)
+	assert.Equal(t, Rejected, results[3].Result)
+	assert.Equal(t, "invalid status 42", results[3].Reason)
+
+	page, err := svc.ListApplications(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, page.Applications, 1)
+}
+
+func TestListApplicationEvents(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+	now := time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	results, err := svc.SetApplications(WithEventSource(ctx, models.SourceLlm), []*models.Application{
+		{Date: testDate, Company: "TestCompany", Position: "Software Engineer", MessageID: "msg-1"},
+	})
+	require.NoError(t, err)
+	id := results[0].Application.ID
+
+	// An interview invitation for the same application, a week later
+	_, err = svc.SetApplications(WithEventSource(ctx, models.SourceLlm), []*models.Application{
+		{Date: testDate.AddDate(0, 0, 7), Company: "TestCompany", Position: "Software Engineer", Status: gcp.Interviewing, MessageID: "msg-2"},
+	})
+	require.NoError(t, err)
+
+	// Manually updated afterwards
+	_, err = svc.UpdateApplication(ctx, &models.Application{
+		ID:     id,
+		Status: gcp.Offer,
+		Interviews: []models.Interview{
+			{DateTime: testDate.AddDate(0, 0, 3), InterviewType: models.RecruiterScreen, DurationMin: 30},
+		},
+	}, []string{"status", "interviews"})
+	require.NoError(t, err)
+
+	events, err := svc.ListApplicationEvents(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, events, 6)
+
+	assert.Equal(t, models.StatusChanged, events[0].Type)
+	assert.Equal(t, testDate, events[0].Time)
+	assert.Equal(t, models.SourceLlm, events[0].Source)
+	assert.Equal(t, gcp.Pending, events[0].Status)
+
+	assert.Equal(t, models.EmailLinked, events[1].Type)
+	assert.Equal(t, "msg-1", events[1].MessageID)
+
+	// The interview was recorded later but took place before the invitation email
+	assert.Equal(t, models.InterviewScheduled, events[2].Type)
+	assert.Equal(t, testDate.AddDate(0, 0, 3), events[2].Time)
+	assert.Equal(t, models.SourceManual, events[2].Source)
+	assert.Equal(t, models.RecruiterScreen, events[2].InterviewType)
+
+	assert.Equal(t, models.StatusChanged, events[3].Type)
+	assert.Equal(t, testDate.AddDate(0, 0, 7), events[3].Time)
+	assert.Equal(t, gcp.Pending, events[3].PreviousStatus)
+	assert.Equal(t, gcp.Interviewing, events[3].Status)
+
+	assert.Equal(t, models.EmailLinked, events[4].Type)
+	assert.Equal(t, "msg-2", events[4].MessageID)
+
+	assert.Equal(t, models.StatusChanged, events[5].Type)
+	assert.Equal(t, now, events[5].Time)
+	assert.Equal(t, models.SourceManual, events[5].Source)
+	assert.Equal(t, gcp.Interviewing, events[5].PreviousStatus)
+	assert.Equal(t, gcp.Offer, events[5].Status)
+
+	// The second email is linked, loading it again changes nothing
+	results, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: testDate.AddDate(0, 0, 7), Company: "Renamed", Status: gcp.Interviewing, MessageID: "msg-2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Unchanged, results[0].Result)
+	assert.Equal(t, id, results[0].Application.ID)
+	assert.Equal(t, gcp.Offer, results[0].Application.Status)
+
+	// Turnaround is derived from the timeline: the interview, 3 days after applying
+	stats, err := svc.GetStats(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3*24*time.Hour, stats.MedianTimeToFirstResponse)
+}
+
+func TestStatusTransitions(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	results, err := svc.SetApplications(ctx, []*models.Application{
+		{Date: testDate, Company: "TestCompany", Position: "Software Engineer", Status: gcp.Reject},
+	})
+	require.NoError(t, err)
+	id := results[0].Application.ID
+
+	// Reject is final
+	_, err = svc.UpdateApplication(ctx, &models.Application{ID: id, Status: gcp.Offer}, []string{"status"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "illegal status transition from Reject to Offer")
+
+	// Merging a record with an illegal transition rejects the record only
+	results, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: testDate.Add(time.Hour), Company: "TestCompany", Position: "Software Engineer", Status: gcp.Interviewing},
+		{Date: testDate, Company: "OtherCompany", Status: gcp.Ghosted},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Rejected, results[0].Result)
+	assert.Equal(t, "illegal status transition from Reject to Interviewing", results[0].Reason)
+	assert.Equal(t, Created, results[1].Result)
+
+	// A later response revives a ghosted application
+	result, err := svc.UpdateApplication(ctx, &models.Application{ID: results[1].Application.ID, Status: gcp.Interviewing}, []string{"status"})
+	require.NoError(t, err)
+	assert.Equal(t, gcp.Interviewing, result.Status)
+
+	stored, err := svc.GetApplication(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, gcp.Reject, stored.Status)
+}
+
+func TestTenants(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	jane, john := WithTenant(ctx, "jane"), WithTenant(ctx, "john")
+	changes, err := svc.WatchApplications(john, nil, 0)
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	janes, err := svc.SetApplications(jane, []*models.Application{{Date: testDate, Company: "TestCompany", Position: "Software Engineer"}})
+	require.NoError(t, err)
+	janeID := janes[0].Application.ID
+	assert.Equal(t, "jane", janes[0].Application.Tenant)
+
+	// the same application of another tenant is not merged
+	johns, err := svc.SetApplications(john, []*models.Application{{Date: testDate, Company: "TestCompany", Position: "Software Engineer"}})
+	require.NoError(t, err)
+	assert.Equal(t, Created, johns[0].Result)
+	assert.NotEqual(t, janeID, johns[0].Application.ID)
+
+	change := receive(t, changes)
+	assert.Equal(t, johns[0].Application.ID, change.Application.ID)
+
+	page, err := svc.ListApplications(jane, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Applications, 1)
+	assert.Equal(t, janeID, page.Applications[0].ID)
+
+	page, err = svc.ListApplications(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, page.Applications)
+
+	_, err = svc.GetApplication(john, janeID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = svc.UpdateApplication(john, &models.Application{ID: janeID, Status: gcp.Reject}, []string{"status"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, svc.DeleteApplication(john, janeID), ErrNotFound)
+
+	application, err := svc.GetApplication(jane, janeID)
+	require.NoError(t, err)
+	assert.Equal(t, gcp.Pending, application.Status)
+
+	// john's watch doesn't see the changes of jane
+	require.NoError(t, svc.DeleteApplication(jane, janeID))
+	select {
+	case change := <-changes:
+		assert.Fail(t, "unexpected change", "%+v", change)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+	"github.com/MaxBear/maxhire/storage"
+)
+
+type Stats struct {
+	// Number of applications in the date range
+	Total    int
+	ByStatus map[gcp.Status]int
+
+	// Applications which got a decision or an interview, see models.Application.Responded
+	Responses    int
+	ResponseRate float64
+
+	// Time between applying and the first response, over the applications with a timestamped response
+	MedianTimeToFirstResponse time.Duration
+	P90TimeToFirstResponse    time.Duration
+
+	// One entry per interview type, in InterviewType order
+	Interviews []InterviewTypeStats
+
+	// Applications per week, from the week of the first application to the week of the last one,
+	// at most MaxWeeks of them ending with the last one
+	Weekly []WeeklyVolume
+
+	// Applications reaching each stage, in FunnelStages order
+	Funnel []FunnelStage
+
+	// Histogram of the time between applying and the first response, one bucket per ResponseTimeBounds
+	// and a last one for the longer times
+	ResponseTimes []ResponseTimeBucket
+}
+
+// FunnelStages are the stages of the funnel from applying to accepting an offer
+var FunnelStages = []string{"applied", "responded", "interviewed", "offer", "accepted"}
+
+type FunnelStage struct {
+	Stage        string
+	Applications int
+}
+
+// MaxWeeks caps the number of weeks of Stats.Weekly, e.g. when an application has a date far in the past
+const MaxWeeks = 520
+
+// ResponseTimeBounds are the upper bounds of the response time histogram buckets
+var ResponseTimeBounds = []time.Duration{
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	14 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+}
+
+type ResponseTimeBucket struct {
+	// Response times up to this bound, 0 for the last bucket which has no bound
+	UpperBound   time.Duration
+	Applications int
+}
+
+type InterviewTypeStats struct {
+	InterviewType models.InterviewType
+	// Applications with at least one interview of this type
+	Applications int
+	// Share of all applications reaching an interview of this type
+	ConversionRate float64
+	// Share of the applications reaching an interview of this type which reached Success or OfferAccepted
+	SuccessRate float64
+}
+
+type WeeklyVolume struct {
+	// Monday 00:00 UTC of the week
+	WeekStart    time.Time
+	Applications int
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func median(sorted []time.Duration) time.Duration {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// reached reports whether the application was ever in any of the statuses
+func reached(app *models.Application, statuses ...gcp.Status) bool {
+	if slices.Contains(statuses, app.Status) {
+		return true
+	}
+	return slices.ContainsFunc(app.Events, func(event models.Event) bool {
+		return event.Type == models.StatusChanged && slices.Contains(statuses, event.Status)
+	})
+}
+
+// funnelStage returns the index in FunnelStages of the last stage the application reached
+func funnelStage(app *models.Application) int {
+	switch {
+	case reached(app, gcp.OfferAccepted, gcp.Success):
+		return 4
+	case reached(app, gcp.Offer, gcp.OfferDeclined):
+		return 3
+	case len(app.Interviews) > 0 || reached(app, gcp.Interviewing):
+		return 2
+	case app.Responded():
+		return 1
+	}
+	return 0
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// weekStart returns Monday 00:00 UTC of the week t falls in
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	// Weekday is 0 on Sunday
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// GetStats aggregates the applications sent between start and end, both optional.
+func (s *serviceImpl) GetStats(ctx context.Context, start, end *time.Time) (*Stats, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetStats")
+	defer span.End()
+
+	if start != nil && end != nil && end.Before(*start) {
+		return nil, invalidArgument("end date %v is before start date %v", *end, *start)
+	}
+
+	// every application of the range, not a page of them
+	applications, _, err := s.store.QueryApplications(ctx, &storage.Query{
+		Tenant:    Tenant(ctx),
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{
+		Total:    len(applications),
+		ByStatus: make(map[gcp.Status]int),
+	}
+
+	turnarounds := []time.Duration{}
+	byInterviewType := make(map[models.InterviewType]*InterviewTypeStats)
+	successes := make(map[models.InterviewType]int)
+	byWeek := make(map[time.Time]int)
+	var firstWeek, lastWeek time.Time
+	stages := make([]int, len(FunnelStages))
+
+	for _, app := range applications {
+		stats.ByStatus[app.Status]++
+
+		if app.Responded() {
+			stats.Responses++
+		}
+		if first, ok := app.FirstResponse(); ok && !first.Before(app.Date) {
+			turnarounds = append(turnarounds, first.Sub(app.Date))
+		}
+		// an application reaching a stage went through the ones before it
+		for stage := range funnelStage(app) + 1 {
+			stages[stage]++
+		}
+
+		seen := make(map[models.InterviewType]bool)
+		for _, interview := range app.Interviews {
+			if seen[interview.InterviewType] {
+				continue
+			}
+			seen[interview.InterviewType] = true
+
+			st, ok := byInterviewType[interview.InterviewType]
+			if !ok {
+				st = &InterviewTypeStats{InterviewType: interview.InterviewType}
+				byInterviewType[interview.InterviewType] = st
+			}
+			st.Applications++
+			if reached(app, gcp.OfferAccepted, gcp.Success) {
+				successes[interview.InterviewType]++
+			}
+		}
+
+		week := weekStart(app.Date)
+		byWeek[week]++
+		if firstWeek.IsZero() || week.Before(firstWeek) {
+			firstWeek = week
+		}
+		if week.After(lastWeek) {
+			lastWeek = week
+		}
+	}
+
+	if stats.Total > 0 {
+		stats.ResponseRate = float64(stats.Responses) / float64(stats.Total)
+	}
+
+	slices.Sort(turnarounds)
+	stats.MedianTimeToFirstResponse = median(turnarounds)
+	stats.P90TimeToFirstResponse = percentile(turnarounds, 90)
+
+	for i, stage := range FunnelStages {
+		stats.Funnel = append(stats.Funnel, FunnelStage{
+			Stage:        stage,
+			Applications: stages[i],
+		})
+	}
+
+	for _, bound := range ResponseTimeBounds {
+		stats.ResponseTimes = append(stats.ResponseTimes, ResponseTimeBucket{UpperBound: bound})
+	}
+	stats.ResponseTimes = append(stats.ResponseTimes, ResponseTimeBucket{})
+	for _, turnaround := range turnarounds {
+		bucket, _ := slices.BinarySearch(ResponseTimeBounds, turnaround)
+		stats.ResponseTimes[bucket].Applications++
+	}
+
+	for _, st := range byInterviewType {
+		st.ConversionRate = float64(st.Applications) / float64(stats.Total)
+		st.SuccessRate = float64(successes[st.InterviewType]) / float64(st.Applications)
+		stats.Interviews = append(stats.Interviews, *st)
+	}
+	slices.SortFunc(stats.Interviews, func(a, b InterviewTypeStats) int {
+		return int(a.InterviewType) - int(b.InterviewType)
+	})
+
+	// Include the weeks without applications so the volume can be charted as is
+	if stats.Total > 0 {
+		firstWeek = latest(firstWeek, lastWeek.AddDate(0, 0, -7*(MaxWeeks-1)))
+		for week := firstWeek; !week.After(lastWeek); week = week.AddDate(0, 0, 7) {
+			stats.Weekly = append(stats.Weekly, WeeklyVolume{
+				WeekStart:    week,
+				Applications: byWeek[week],
+			})
+		}
+	}
+
+	return stats, nil
+}
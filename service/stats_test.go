@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+)
+
+func TestGetStats(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	// 2024-01-01 is a Monday
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 10, 0, 0, 0, time.UTC)
+	}
+	interview := func(d int, interviewType models.InterviewType) models.Interview {
+		return models.Interview{DateTime: day(d), InterviewType: interviewType, DurationMin: 30}
+	}
+	_, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: day(1), Company: "A", Status: gcp.Success, Interviews: []models.Interview{
+			interview(3, models.RecruiterScreen), interview(10, models.TechCoding),
+		}},
+		{Date: day(2), Company: "B", Status: gcp.Reject, Interviews: []models.Interview{
+			interview(6, models.RecruiterScreen),
+		}},
+		{Date: day(3), Company: "C", Status: gcp.Reject},
+		{Date: day(4), Company: "D", Status: gcp.Pending},
+		// Nothing in the week of the 8th
+		{Date: day(16), Company: "E", Status: gcp.Applied, Interviews: []models.Interview{
+			interview(26, models.RecruiterScreen),
+		}},
+		{Date: day(17), Company: "F", Status: gcp.Pending},
+	})
+	require.NoError(t, err)
+
+	stats, err := svc.GetStats(ctx, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, stats.Total)
+	assert.Equal(t, 2, stats.ByStatus[gcp.Pending])
+	assert.Equal(t, 2, stats.ByStatus[gcp.Reject])
+	assert.Equal(t, 1, stats.ByStatus[gcp.Success])
+	assert.Equal(t, 1, stats.ByStatus[gcp.Applied])
+
+	assert.Equal(t, 4, stats.Responses)
+	assert.InDelta(t, 4.0/6.0, stats.ResponseRate, 1e-9)
+
+	// Turnarounds are 2, 4 and 10 days
+	assert.Equal(t, 4*24*time.Hour, stats.MedianTimeToFirstResponse)
+	assert.Equal(t, 10*24*time.Hour, stats.P90TimeToFirstResponse)
+
+	require.Len(t, stats.Interviews, 2)
+	assert.Equal(t, models.RecruiterScreen, stats.Interviews[0].InterviewType)
+	assert.Equal(t, 3, stats.Interviews[0].Applications)
+	assert.InDelta(t, 0.5, stats.Interviews[0].ConversionRate, 1e-9)
+	assert.InDelta(t, 1.0/3.0, stats.Interviews[0].SuccessRate, 1e-9)
+	assert.Equal(t, models.TechCoding, stats.Interviews[1].InterviewType)
+	assert.Equal(t, 1, stats.Interviews[1].Applications)
+	assert.InDelta(t, 1.0, stats.Interviews[1].SuccessRate, 1e-9)
+
+	require.Len(t, stats.Weekly, 3)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), stats.Weekly[0].WeekStart)
+	assert.Equal(t, 4, stats.Weekly[0].Applications)
+	assert.Equal(t, 0, stats.Weekly[1].Applications)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), stats.Weekly[2].WeekStart)
+	assert.Equal(t, 2, stats.Weekly[2].Applications)
+
+	funnel := []FunnelStage{
+		{Stage: "applied", Applications: 6},
+		{Stage: "responded", Applications: 4},
+		{Stage: "interviewed", Applications: 3},
+		{Stage: "offer", Applications: 1},
+		{Stage: "accepted", Applications: 1},
+	}
+	assert.Equal(t, funnel, stats.Funnel)
+
+	require.Len(t, stats.ResponseTimes, len(ResponseTimeBounds)+1)
+	histogram := []int{}
+	for _, bucket := range stats.ResponseTimes {
+		histogram = append(histogram, bucket.Applications)
+	}
+	assert.Equal(t, []int{0, 1, 1, 1, 0, 0}, histogram)
+	assert.Equal(t, time.Duration(0), stats.ResponseTimes[5].UpperBound)
+}
+
+func TestGetStats_DateRange(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	_, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Company: "A"},
+		{Date: time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC), Company: "B"},
+	})
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	stats, err := svc.GetStats(ctx, &start, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Total)
+	assert.Equal(t, 0.0, stats.ResponseRate)
+	assert.Equal(t, time.Duration(0), stats.MedianTimeToFirstResponse)
+
+	end := start.AddDate(0, 0, -1)
+	_, err = svc.GetStats(ctx, &start, &end)
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestGetStats_SuccessRate(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	date := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	interviews := []models.Interview{{DateTime: date.AddDate(0, 0, 3), InterviewType: models.RecruiterScreen, DurationMin: 30}}
+	_, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: date, Company: "A", Status: gcp.OfferAccepted, Interviews: interviews},
+		{Date: date, Company: "B", Status: gcp.Offer, Interviews: interviews},
+		{Date: date, Company: "C", Status: gcp.Reject, Interviews: interviews},
+		{Date: date, Company: "D", Status: gcp.Success, Interviews: interviews},
+	})
+	require.NoError(t, err)
+
+	stats, err := svc.GetStats(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, stats.Interviews, 1)
+	assert.InDelta(t, 0.5, stats.Interviews[0].SuccessRate, 1e-9)
+}
+
+func TestGetStats_MaxWeeks(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	last := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	_, err = svc.SetApplications(ctx, []*models.Application{
+		{Date: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), Company: "A"},
+		{Date: last, Company: "B"},
+	})
+	require.NoError(t, err)
+
+	stats, err := svc.GetStats(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Total)
+	require.Len(t, stats.Weekly, MaxWeeks)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), stats.Weekly[MaxWeeks-1].WeekStart)
+	assert.Equal(t, 1, stats.Weekly[MaxWeeks-1].Applications)
+}
+
+func TestGetStats_AllPages(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	date := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	applications := []*models.Application{}
+	for i := range DefaultPageSize + 5 {
+		applications = append(applications, &models.Application{Date: date, Company: fmt.Sprintf("Company %03d", i)})
+	}
+	_, err = svc.SetApplications(ctx, applications)
+	require.NoError(t, err)
+
+	stats, err := svc.GetStats(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPageSize+5, stats.Total)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/MaxBear/maxhire/models"
+)
+
+// ErrRevisionCompacted is returned when watching from a revision whose changes are not kept anymore
+var ErrRevisionCompacted = errors.New("revision compacted")
+
+// DefaultWatchHistory is how many of the latest changes are kept to resume watches from
+const DefaultWatchHistory = 1000
+
+// watchBuffer is how many changes a watcher can fall behind before it is dropped
+const watchBuffer = 256
+
+type ChangeType int
+
+const (
+	ChangeCreated ChangeType = iota // 0
+	ChangeUpdated                   // 1
+	ChangeDeleted                   // 2
+)
+
+func (t ChangeType) String() string {
+	names := [...]string{"Created", "Updated", "Deleted"}
+	if t < 0 || int(t) >= len(names) {
+		return fmt.Sprintf("ChangeType(%d)", int(t))
+	}
+	return names[t]
+}
+
+// Change is a change to one application
+type Change struct {
+	Revision int64
+	Type     ChangeType
+	// The application after the change, or before it was deleted
+	Application *models.Application
+
+	// The application before the change, nil if it was created
+	previous *models.Application
+}
+
+// match reports whether the change concerns an application of the tenant passing the filters, before
+// or after the change, so watchers also learn about applications leaving the filters.
+func (c *Change) match(tenant string, filters *ListApplicationsFilters) bool {
+	if c.Application.Tenant != tenant {
+		return false
+	}
+	if filters == nil {
+		return true
+	}
+	return filters.Match(c.Application) || (c.previous != nil && filters.Match(c.previous))
+}
+
+type watcher struct {
+	tenant  string
+	filters *ListApplicationsFilters
+	changes chan Change
+	// closed with changes
+	stopped chan struct{}
+}
+
+// watchHub numbers the changes and fans them out to the watchers
+type watchHub struct {
+	mu sync.Mutex
+	// The initial state is revision 1, so 0 can mean now
+	revision int64
+	// The latest changes, oldest first
+	history  []Change
+	size     int
+	watchers map[*watcher]struct{}
+}
+
+func newWatchHub(size int) *watchHub {
+	return &watchHub{
+		revision: 1,
+		size:     size,
+		watchers: make(map[*watcher]struct{}),
+	}
+}
+
+func (h *watchHub) currentRevision() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.revision
+}
+
+// publish records a change, callers hold serviceImpl.mu so changes are published in the order they are stored
+func (h *watchHub) publish(typ ChangeType, application, previous *models.Application) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.revision++
+	change := Change{
+		Revision:    h.revision,
+		Type:        typ,
+		Application: application.Clone(),
+	}
+	if previous != nil {
+		change.previous = previous.Clone()
+	}
+
+	if h.size > 0 {
+		if len(h.history) >= h.size {
+			h.history = h.history[1:]
+		}
+		h.history = append(h.history, change)
+	}
+
+	for w := range h.watchers {
+		if !change.match(w.tenant, w.filters) {
+			continue
+		}
+		select {
+		case w.changes <- change:
+		default:
+			// never block the writers on a slow watcher, it has to resume from its last revision
+			h.remove(w)
+		}
+	}
+}
+
+// remove closes the changes of the watcher, callers hold h.mu
+func (h *watchHub) remove(w *watcher) {
+	if _, ok := h.watchers[w]; ok {
+		delete(h.watchers, w)
+		close(w.changes)
+		close(w.stopped)
+	}
+}
+
+func (h *watchHub) watch(ctx context.Context, filters *ListApplicationsFilters, fromRevision int64) (<-chan Change, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if fromRevision < 0 || fromRevision > h.revision {
+		return nil, invalidArgument("invalid revision %d, current revision is %d", fromRevision, h.revision)
+	}
+
+	replay := []Change{}
+	if fromRevision > 0 && fromRevision < h.revision {
+		oldest := h.revision + 1
+		if len(h.history) > 0 {
+			oldest = h.history[0].Revision
+		}
+		if fromRevision < oldest-1 {
+			return nil, fmt.Errorf("%w: revision %d, oldest revision to watch from is %d", ErrRevisionCompacted, fromRevision, oldest-1)
+		}
+		for _, change := range h.history {
+			if change.Revision > fromRevision && change.match(Tenant(ctx), filters) {
+				replay = append(replay, change)
+			}
+		}
+	}
+
+	w := &watcher{
+		tenant:  Tenant(ctx),
+		filters: filters,
+		changes: make(chan Change, watchBuffer+len(replay)),
+		stopped: make(chan struct{}),
+	}
+	for _, change := range replay {
+		w.changes <- change
+	}
+	h.watchers[w] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-w.stopped:
+			return
+		}
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(w)
+	}()
+
+	return w.changes, nil
+}
+
+// WatchApplications streams the changes to the applications of the tenant of ctx matching the filters
+// made after fromRevision, or from now on if fromRevision is 0. Revisions are shared by all tenants and
+// only kept in memory, they start over when the process restarts.
+// Paging and ordering filters are ignored. The channel is closed when ctx is done, or early when the
+// receiver falls behind, in which case it can watch again from the revision of the last change received.
+func (s *serviceImpl) WatchApplications(ctx context.Context, filters *ListApplicationsFilters, fromRevision int64) (<-chan Change, error) {
+	ctx, span := tracer.Start(ctx, "Service.WatchApplications")
+	defer span.End()
+
+	return s.watch.watch(ctx, filters, fromRevision)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+)
+
+func receive(t *testing.T, changes <-chan Change) Change {
+	t.Helper()
+	select {
+	case change, ok := <-changes:
+		require.True(t, ok, "changes closed")
+		return change
+	case <-time.After(time.Second):
+		require.FailNow(t, "no change received")
+	}
+	return Change{}
+}
+
+func TestWatchApplications(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	page, err := svc.ListApplications(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Revision)
+
+	changes, err := svc.WatchApplications(ctx, &ListApplicationsFilters{Company: "TestCompany"}, 0)
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	results, err := svc.SetApplications(ctx, []*models.Application{
+		{Date: testDate, Company: "OtherCompany", Position: "Software Engineer"},
+		{Date: testDate, Company: "TestCompany", Position: "Software Engineer"},
+		{Date: testDate, Company: "TestCompany", Position: "Data Engineer"},
+	})
+	require.NoError(t, err)
+	id := results[1].Application.ID
+
+	change := receive(t, changes)
+	assert.Equal(t, int64(3), change.Revision)
+	assert.Equal(t, ChangeCreated, change.Type)
+	assert.Equal(t, id, change.Application.ID)
+	change = receive(t, changes)
+	assert.Equal(t, int64(4), change.Revision)
+
+	_, err = svc.UpdateApplication(ctx, &models.Application{ID: id, Status: gcp.Interviewing}, []string{"status"})
+	require.NoError(t, err)
+	change = receive(t, changes)
+	assert.Equal(t, int64(5), change.Revision)
+	assert.Equal(t, ChangeUpdated, change.Type)
+	assert.Equal(t, gcp.Interviewing, change.Application.Status)
+
+	require.NoError(t, svc.DeleteApplication(ctx, results[0].Application.ID))
+	require.NoError(t, svc.DeleteApplication(ctx, results[2].Application.ID))
+	change = receive(t, changes)
+	assert.Equal(t, int64(7), change.Revision)
+	assert.Equal(t, ChangeDeleted, change.Type)
+	assert.Equal(t, results[2].Application.ID, change.Application.ID)
+
+	// Watchers also learn about applications leaving the filters
+	_, err = svc.UpdateApplication(ctx, &models.Application{ID: id, Company: "Renamed"}, []string{"company"})
+	require.NoError(t, err)
+	change = receive(t, changes)
+	assert.Equal(t, int64(8), change.Revision)
+	assert.Equal(t, ChangeUpdated, change.Type)
+	assert.Equal(t, "Renamed", change.Application.Company)
+
+	// but not about their changes afterwards
+	require.NoError(t, svc.DeleteApplication(ctx, id))
+	select {
+	case change := <-changes:
+		assert.Fail(t, "unexpected change", "%+v", change)
+	default:
+	}
+
+	// The changes are closed with the context
+	cancel()
+	select {
+	case _, ok := <-changes:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "changes not closed")
+	}
+}
+
+func TestWatchApplications_Resume(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "", WithWatchHistory(2))
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for _, company := range []string{"A", "B", "C"} {
+		_, err := svc.SetApplications(ctx, []*models.Application{
+			{Date: testDate, Company: company, Position: "Software Engineer"},
+		})
+		require.NoError(t, err)
+	}
+
+	// Revisions 3 and 4 are kept
+	changes, err := svc.WatchApplications(ctx, nil, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "B", receive(t, changes).Application.Company)
+	assert.Equal(t, "C", receive(t, changes).Application.Company)
+
+	// Up to date
+	_, err = svc.WatchApplications(ctx, nil, 4)
+	require.NoError(t, err)
+
+	_, err = svc.WatchApplications(ctx, nil, 1)
+	assert.ErrorIs(t, err, ErrRevisionCompacted)
+
+	_, err = svc.WatchApplications(ctx, nil, 5)
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	// Revisions start over with the process
+	restarted, err := NewService(ctx, "", WithWatchHistory(2))
+	require.NoError(t, err)
+	_, err = restarted.WatchApplications(ctx, nil, 4)
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestChangeType(t *testing.T) {
+	assert.Equal(t, "Deleted", ChangeDeleted.String())
+	assert.Equal(t, "ChangeType(42)", ChangeType(42).String())
+	assert.Equal(t, "ChangeType(-1)", ChangeType(-1).String())
+}
+
+func TestWatchApplications_SlowWatcher(t *testing.T) {
+	ctx := context.Background()
+	svc, err := NewService(ctx, "")
+	require.NoError(t, err)
+
+	changes, err := svc.WatchApplications(ctx, nil, 0)
+	require.NoError(t, err)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	applications := []*models.Application{}
+	for i := 0; i <= watchBuffer; i++ {
+		applications = append(applications, &models.Application{
+			Date: testDate, Company: fmt.Sprintf("Company%d", i), Position: "Software Engineer",
+		})
+	}
+	_, err = svc.SetApplications(ctx, applications)
+	require.NoError(t, err)
+
+	// Dropped once the buffer is full instead of blocking the writers
+	received := 0
+	for range changes {
+		received++
+	}
+	assert.Equal(t, watchBuffer, received)
+}
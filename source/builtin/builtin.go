@@ -0,0 +1,107 @@
+// Package builtin registers the built-in email sources, import it for its side effects:
+//
+//	import _ "github.com/MaxBear/maxhire/source/builtin"
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/MaxBear/maxhire/config"
+	"github.com/MaxBear/maxhire/deps/ImapService"
+	"github.com/MaxBear/maxhire/deps/MailboxService"
+	"github.com/MaxBear/maxhire/deps/gcp/AppScriptService"
+	"github.com/MaxBear/maxhire/deps/gcp/GmailService"
+	"github.com/MaxBear/maxhire/source"
+)
+
+var (
+	_ source.EmailSource = (*AppScriptService.AppScriptService)(nil)
+	_ source.EmailSource = (*GmailService.GmailService)(nil)
+	_ source.EmailSource = (*ImapService.ImapService)(nil)
+	_ source.EmailSource = (*MailboxService.MailboxService)(nil)
+)
+
+// the built-in sources, their factories return a nil EmailSource, not a nil service, with their errors
+func init() {
+	source.Register(AppScriptService.Source, newAppScript)
+	source.Register(GmailService.Source, newGmail)
+	source.Register(ImapService.Source, newImap)
+	source.Register(MailboxService.Source, newMailbox)
+}
+
+func redirectUrl(cfg *config.Config) string {
+	return fmt.Sprintf("http://localhost:%d", cfg.Google.OAuthRedirectPort)
+}
+
+func newAppScript(ctx context.Context, logger *slog.Logger, cfg *config.Config) (source.EmailSource, error) {
+	google := cfg.Google
+	s, err := AppScriptService.New(
+		ctx,
+		AppScriptService.WithOauthRedirectPort(google.OAuthRedirectPort),
+		AppScriptService.WithOauthRedirectUrl(redirectUrl(cfg)),
+		AppScriptService.WithCredFile(google.CredentialsFile),
+		AppScriptService.WithTokFile(google.TokenFile),
+		AppScriptService.WithAppScriptDeploymentId(google.AppScriptDeploymentID),
+		AppScriptService.WithLogger(logger),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newGmail(ctx context.Context, logger *slog.Logger, cfg *config.Config) (source.EmailSource, error) {
+	google := cfg.Google
+	opts := []GmailService.GmailServiceOpt{
+		GmailService.WithOauthRedirectPort(google.OAuthRedirectPort),
+		GmailService.WithOauthRedirectUrl(redirectUrl(cfg)),
+		GmailService.WithCredFile(google.CredentialsFile),
+		GmailService.WithTokFile(google.TokenFile),
+		GmailService.WithLogger(logger),
+	}
+	if google.GmailQuery != "" {
+		opts = append(opts, GmailService.WithQuery(google.GmailQuery))
+	}
+	s, err := GmailService.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newImap(ctx context.Context, logger *slog.Logger, cfg *config.Config) (source.EmailSource, error) {
+	auth := ImapService.WithLogin(cfg.Imap.User, cfg.Imap.Password)
+	if cfg.Imap.Token != "" {
+		auth = ImapService.WithXOAuth2(cfg.Imap.User, cfg.Imap.Token)
+	}
+	s, err := ImapService.New(
+		ctx,
+		ImapService.WithAddr(cfg.Imap.Addr),
+		auth,
+		ImapService.WithFolder(cfg.Imap.Folder),
+		ImapService.WithTLS(cfg.Imap.TLS),
+		ImapService.WithAllowPlaintext(cfg.Imap.AllowPlaintext),
+		ImapService.WithLogger(logger),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newMailbox(ctx context.Context, logger *slog.Logger, cfg *config.Config) (source.EmailSource, error) {
+	opts := []MailboxService.MailboxServiceOpt{MailboxService.WithLogger(logger)}
+	if cfg.Ingest.Mbox != "" {
+		opts = append(opts, MailboxService.WithMbox(cfg.Ingest.Mbox))
+	}
+	if cfg.Ingest.EmlDir != "" {
+		opts = append(opts, MailboxService.WithEmlDir(cfg.Ingest.EmlDir))
+	}
+	s, err := MailboxService.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
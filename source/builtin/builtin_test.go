@@ -0,0 +1,24 @@
+package builtin
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MaxBear/maxhire/config"
+	"github.com/MaxBear/maxhire/deps/ImapService"
+	"github.com/MaxBear/maxhire/source"
+)
+
+func TestBuiltin(t *testing.T) {
+	// config names the built-in sources without importing them
+	assert.Equal(t, []string{config.SourceAppScript, config.SourceGmail, config.SourceImap, config.SourceMailbox}, source.Names())
+	assert.Equal(t, ImapService.DefaultFolder, config.Default().Imap.Folder)
+
+	// the mailbox source requires its files
+	s, err := source.New(context.Background(), config.SourceMailbox, slog.Default(), config.Default())
+	assert.Error(t, err)
+	assert.Nil(t, s)
+}
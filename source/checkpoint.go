@@ -0,0 +1,132 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MaxBear/maxhire/deps/gcp/models"
+)
+
+// Overlap is how long before the last email of a checkpoint the next read starts, the sources searching by
+// day and some emails being received late. The emails of the overlap already read are skipped by their id.
+const Overlap = 24 * time.Hour
+
+// Checkpoint is where the incremental reads of a source resume
+type Checkpoint struct {
+	// LastSentTime is the time of the last email read
+	LastSentTime time.Time `json:"last_sent_time"`
+	// Seen are the times of the emails read in the overlap, by id
+	Seen map[string]time.Time `json:"seen,omitempty"`
+}
+
+// Checkpoints are the checkpoints of the sources, by the key of the source, see Key
+type Checkpoints map[string]*Checkpoint
+
+// Identifier is implemented by the sources reading one of several mailboxes, e.g. a folder of an account, to
+// keep their checkpoints apart
+type Identifier interface {
+	// ID identifies the mailbox read, e.g. by its account and folder
+	ID() string
+}
+
+// Key returns the key of the checkpoint of the source, its name followed by its id if it has one
+func Key(s EmailSource) string {
+	if i, ok := s.(Identifier); ok && i.ID() != "" {
+		return s.Name() + ":" + i.ID()
+	}
+	return s.Name()
+}
+
+// LoadCheckpoints reads the checkpoints of a file, none if it does not exist
+func LoadCheckpoints(path string) (Checkpoints, error) {
+	checkpoints := Checkpoints{}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return checkpoints, err
+	}
+	if err := json.Unmarshal(b, &checkpoints); err != nil {
+		return checkpoints, err
+	}
+	return checkpoints, nil
+}
+
+// Save writes the checkpoints to a file, replaced at once so that a failed write keeps the previous ones
+func (c Checkpoints) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// since returns the start of the next read: start if set, otherwise the overlap before the checkpoint
+func (c *Checkpoint) since(start time.Time) time.Time {
+	if !start.IsZero() || c.LastSentTime.IsZero() {
+		return start
+	}
+	return c.LastSentTime.Add(-Overlap)
+}
+
+// add records the emails read, and forgets the ones sent before the overlap of the last one
+func (c *Checkpoint) add(records models.RawEmailRecords) {
+	if c.Seen == nil {
+		c.Seen = map[string]time.Time{}
+	}
+	for _, r := range records {
+		c.Seen[r.Key()] = r.SentTime
+		if r.SentTime.After(c.LastSentTime) {
+			c.LastSentTime = r.SentTime
+		}
+	}
+	maps.DeleteFunc(c.Seen, func(_ string, sent time.Time) bool {
+		return sent.Before(c.LastSentTime.Add(-Overlap))
+	})
+}
+
+// FetchNew returns the emails of the source sent from start to end excluded, either is unbounded if zero,
+// not read since its checkpoint. Without start, the read resumes at the checkpoint. With start, the whole
+// range is read, and only the emails of the overlap of the checkpoint are known to be read already. The
+// checkpoint is updated once all the emails are read.
+func FetchNew(ctx context.Context, s EmailSource, checkpoints Checkpoints, start, end time.Time) (models.RawEmailRecords, error) {
+	key := Key(s)
+	checkpoint, ok := checkpoints[key]
+	if !ok {
+		checkpoint = &Checkpoint{}
+	}
+
+	emails := models.RawEmailRecords{}
+	for record, err := range s.Emails(ctx, checkpoint.since(start), end) {
+		if err != nil {
+			return emails, err
+		}
+		if _, seen := checkpoint.Seen[record.Key()]; seen {
+			continue
+		}
+		emails = append(emails, record)
+	}
+
+	checkpoint.add(emails)
+	checkpoints[key] = checkpoint
+	return emails, nil
+}
@@ -0,0 +1,102 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/source/fake"
+)
+
+func TestFetchNew(t *testing.T) {
+	ctx := context.Background()
+	day := func(d, h int) time.Time {
+		return time.Date(2025, 3, d, h, 0, 0, 0, time.UTC)
+	}
+	first := []*models.RawEmailRecord{
+		{SentTime: day(1, 9), MessageId: "m1"},
+		{SentTime: day(3, 9), MessageId: "m2"},
+		{SentTime: day(4, 9), MessageId: "m3"},
+	}
+	checkpoints := Checkpoints{}
+
+	emails, err := FetchNew(ctx, fake.New(fake.WithEmails(first...)), checkpoints, day(2, 0), time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, emails, 2)
+	assert.Equal(t, day(4, 9), checkpoints[fake.Name].LastSentTime)
+	assert.Equal(t, map[string]time.Time{"m2": day(3, 9), "m3": day(4, 9)}, checkpoints[fake.Name].Seen)
+
+	// a late email of the overlap, and a new one
+	s := fake.New(fake.WithEmails(append(first,
+		&models.RawEmailRecord{SentTime: day(4, 8), MessageId: "m4"},
+		&models.RawEmailRecord{SentTime: day(5, 9), MessageId: "m5"},
+	)...))
+	emails, err = FetchNew(ctx, s, checkpoints, day(2, 0), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, emails, 2)
+	assert.Equal(t, "m4", emails[0].MessageId)
+	assert.Equal(t, "m5", emails[1].MessageId)
+	// the given start is kept, the emails read before are skipped
+	assert.Equal(t, day(2, 0), s.Requests()[0].Start)
+
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	require.NoError(t, checkpoints.Save(path))
+	loaded, err := LoadCheckpoints(path)
+	require.NoError(t, err)
+	assert.True(t, day(5, 9).Equal(loaded[fake.Name].LastSentTime))
+	// m2 and m4 are before the overlap of m5
+	assert.ElementsMatch(t, []string{"m3", "m5"}, slices.Collect(maps.Keys(loaded[fake.Name].Seen)))
+
+	emails, err = FetchNew(ctx, s, loaded, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, emails)
+	// resumed at the checkpoint without start
+	assert.Equal(t, day(4, 9), s.Requests()[1].Start)
+
+	// a failed read keeps the checkpoint
+	failing := fake.New(fake.WithEmails(&models.RawEmailRecord{SentTime: day(6, 9), MessageId: "m6"}), fake.WithError(errors.New("unavailable")))
+	_, err = FetchNew(ctx, failing, loaded, time.Time{}, time.Time{})
+	assert.Error(t, err)
+	assert.True(t, day(5, 9).Equal(loaded[fake.Name].LastSentTime))
+
+	loaded, err = LoadCheckpoints(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, err = LoadCheckpoints(path)
+	assert.Error(t, err)
+}
+
+func TestFetchNew_Identity(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time {
+		return time.Date(2025, 3, d, 9, 0, 0, 0, time.UTC)
+	}
+	checkpoints := Checkpoints{}
+
+	inbox := fake.New(fake.WithID("inbox"), fake.WithEmails(&models.RawEmailRecord{SentTime: day(10), MessageId: "m1"}))
+	_, err := FetchNew(ctx, inbox, checkpoints, time.Time{}, time.Time{})
+	require.NoError(t, err)
+
+	// another mailbox of the same source, with older emails, is read from its start
+	jobs := fake.New(fake.WithID("jobs"), fake.WithEmails(&models.RawEmailRecord{SentTime: day(1), MessageId: "m2"}))
+	emails, err := FetchNew(ctx, jobs, checkpoints, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, emails, 1)
+	assert.Equal(t, "m2", emails[0].MessageId)
+	assert.True(t, jobs.Requests()[0].Start.IsZero())
+
+	assert.Equal(t, day(10), checkpoints["fake:inbox"].LastSentTime)
+	assert.Equal(t, day(1), checkpoints["fake:jobs"].LastSentTime)
+	assert.Equal(t, "fake", Key(fake.New()))
+}
@@ -0,0 +1,114 @@
+// Package fake is an in-memory source of job application emails, to run the ingestion without a mailbox,
+// e.g. in tests.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/MaxBear/maxhire/deps/gcp/models"
+)
+
+// Name is the default name of the source
+const Name = "fake"
+
+// Source returns its emails sent in the time range, in the order they were added
+type Source struct {
+	name     string
+	id       string
+	emails   models.RawEmailRecords
+	err      error
+	requests []Request
+}
+
+// Request is a time range the emails were read for
+type Request struct {
+	Start, End time.Time
+}
+
+type SourceOpt func(*Source)
+
+// WithName sets the name of the source, defaults to Name
+func WithName(name string) SourceOpt {
+	return func(s *Source) {
+		s.name = name
+	}
+}
+
+// WithID sets the id of the mailbox of the source, e.g. to tell apart several fake sources of the same name
+func WithID(id string) SourceOpt {
+	return func(s *Source) {
+		s.id = id
+	}
+}
+
+// WithEmails adds emails to the source, the ones without MessageId are identified by their index
+func WithEmails(emails ...*models.RawEmailRecord) SourceOpt {
+	return func(s *Source) {
+		s.emails = append(s.emails, emails...)
+	}
+}
+
+// WithError fails the reads with err, after the emails
+func WithError(err error) SourceOpt {
+	return func(s *Source) {
+		s.err = err
+	}
+}
+
+func New(opts ...SourceOpt) *Source {
+	s := &Source{
+		name: Name,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	for i, email := range s.emails {
+		if email.MessageId == "" {
+			email.MessageId = fmt.Sprintf("%s/%d", s.name, i+1)
+		}
+	}
+
+	return s
+}
+
+// Name returns the name of the source
+func (s *Source) Name() string {
+	return s.name
+}
+
+// ID returns the id of the mailbox of the source, empty unless set with WithID
+func (s *Source) ID() string {
+	return s.id
+}
+
+// Emails returns the emails of the source sent from start to end excluded, either is unbounded if zero
+func (s *Source) Emails(ctx context.Context, start, end time.Time) iter.Seq2[*models.RawEmailRecord, error] {
+	s.requests = append(s.requests, Request{Start: start, End: end})
+	return func(yield func(*models.RawEmailRecord, error) bool) {
+		for _, email := range s.emails {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !email.InRange(start, end) {
+				continue
+			}
+			if !yield(email, nil) {
+				return
+			}
+		}
+		if s.err != nil {
+			yield(nil, s.err)
+		}
+	}
+}
+
+// Requests returns the time ranges the emails were read for
+func (s *Source) Requests() []Request {
+	return s.requests
+}
@@ -0,0 +1,115 @@
+// Package source reads the job application emails of the mailboxes, with the sources registered by name,
+// e.g. the Gmail api or an IMAP server.
+package source
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MaxBear/maxhire/config"
+	"github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/telemetry"
+)
+
+var tracer = otel.Tracer("github.com/MaxBear/maxhire/source")
+
+// EmailSource reads the job application emails of a mailbox
+type EmailSource interface {
+	// Name returns the name the source is registered with
+	Name() string
+	// Emails returns the emails sent from start to end excluded, either is unbounded if zero, as they are
+	// read. The MessageId of the emails identifies them in the source. The iteration ends with the error
+	// of the source, if it fails.
+	Emails(ctx context.Context, start, end time.Time) iter.Seq2[*models.RawEmailRecord, error]
+}
+
+// Factory returns the source configured by cfg
+type Factory func(ctx context.Context, logger *slog.Logger, cfg *config.Config) (EmailSource, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+// Register registers the factory of the source name, it panics if name is already registered
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := factories[name]; ok {
+		panic("source: Register called twice for source " + name)
+	}
+	factories[name] = factory
+}
+
+// Names returns the sorted names of the registered sources
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// New returns the source registered as name, configured by cfg
+func New(ctx context.Context, name string, logger *slog.Logger, cfg *config.Config) (EmailSource, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown email source %q, expecting one of: %s", name, strings.Join(Names(), ", "))
+	}
+	return factory(ctx, logger, cfg)
+}
+
+// Fetch returns the emails of the source sent from start to end excluded, and the ones read before its
+// error if it fails
+func Fetch(ctx context.Context, s EmailSource, start, end time.Time) (models.RawEmailRecords, error) {
+	return models.CollectRecords(s.Emails(ctx, start, end))
+}
+
+// ReadFunc reads the emails of a source, passing them to add until it returns false
+type ReadFunc func(ctx context.Context, add func(*models.RawEmailRecord) bool) error
+
+// Stream implements EmailSource.Emails for the source name with read, which may pass emails out of the
+// time range, e.g. of the days of start and end: they are skipped. The read is traced with the attributes,
+// and recorded in the fetch metrics of the source.
+func Stream(ctx context.Context, name string, start, end time.Time, read ReadFunc, attrs ...attribute.KeyValue) iter.Seq2[*models.RawEmailRecord, error] {
+	return func(yield func(*models.RawEmailRecord, error) bool) {
+		ctx, span := tracer.Start(ctx, "Source.Emails", trace.WithAttributes(append(attrs, attribute.String("source", name))...))
+		defer span.End()
+
+		began := time.Now()
+		n := 0
+		err := read(ctx, func(record *models.RawEmailRecord) bool {
+			if !record.InRange(start, end) {
+				return true
+			}
+			n++
+			return yield(record, nil)
+		})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attribute.Int("emails", n))
+		telemetry.ObserveSourceFetch(name, began, n, err)
+
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
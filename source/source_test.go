@@ -0,0 +1,97 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MaxBear/maxhire/config"
+	"github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/source/fake"
+)
+
+func emails() []*models.RawEmailRecord {
+	return []*models.RawEmailRecord{
+		{SentTime: time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC), FullSender: "no-reply@us.greenhouse-mail.io", Subject: "Thank you for applying", MessageId: "m1"},
+		{SentTime: time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC), FullSender: "jobs@acme.com", Subject: "Your application"},
+		{SentTime: time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC), FullSender: "later@example.com", Subject: "Out of range"},
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	Register("test", func(ctx context.Context, logger *slog.Logger, cfg *config.Config) (EmailSource, error) {
+		return fake.New(fake.WithName("test"), fake.WithEmails(emails()...)), nil
+	})
+	assert.Panics(t, func() {
+		Register("test", nil)
+	})
+	assert.Contains(t, Names(), "test")
+
+	s, err := New(context.Background(), "test", slog.Default(), config.Default())
+	require.NoError(t, err)
+	assert.Equal(t, "test", s.Name())
+
+	_, err = New(context.Background(), "other", slog.Default(), config.Default())
+	assert.ErrorContains(t, err, "unknown email source \"other\"")
+}
+
+func TestFetch(t *testing.T) {
+	s := fake.New(fake.WithEmails(emails()...))
+
+	records, err := Fetch(context.Background(), s, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "m1", records[0].MessageId)
+	assert.Equal(t, "fake/2", records[1].MessageId)
+
+	records, err = Fetch(context.Background(), s, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Len(t, s.Requests(), 2)
+
+	// the iteration stops with the consumer
+	n := 0
+	for range s.Emails(context.Background(), time.Time{}, time.Time{}) {
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+
+	failing := fake.New(fake.WithEmails(emails()...), fake.WithError(errors.New("unavailable")))
+	records, err = Fetch(context.Background(), failing, time.Time{}, time.Time{})
+	assert.EqualError(t, err, "unavailable")
+	assert.Len(t, records, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Fetch(ctx, s, time.Time{}, time.Time{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStream(t *testing.T) {
+	read := func(ctx context.Context, add func(*models.RawEmailRecord) bool) error {
+		for _, email := range emails() {
+			if !add(email) {
+				return nil
+			}
+		}
+		return errors.New("unavailable")
+	}
+
+	// the emails out of range are skipped, and the error ends the iteration
+	records, err := models.CollectRecords(Stream(context.Background(), "test", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), read))
+	assert.EqualError(t, err, "unavailable")
+	assert.Len(t, records, 2)
+
+	n := 0
+	for range Stream(context.Background(), "test", time.Time{}, time.Time{}, read) {
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+	"github.com/MaxBear/maxhire/storage"
+)
+
+// Store keeps applications in memory, everything is lost when the process exits unless the store
+// was opened with a snapshot file. Applications are copied in and out so callers never share state
+// with the store.
+type Store struct {
+	mu           sync.RWMutex
+	applications []*models.Application
+	snapshot     string
+}
+
+func New() *Store {
+	return &Store{
+		applications: []*models.Application{},
+	}
+}
+
+// Open returns a store with the applications saved in the snapshot file, which is created if it
+// doesn't exist. The applications are saved to the file on Close.
+func Open(snapshot string) (*Store, error) {
+	s := New()
+	s.snapshot = snapshot
+
+	b, err := os.ReadFile(snapshot)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.applications); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) ListApplications(ctx context.Context, tenant string) ([]*models.Application, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := []*models.Application{}
+	for _, app := range s.applications {
+		if app.Tenant == tenant {
+			res = append(res, app.Clone())
+		}
+	}
+	return res, nil
+}
+
+func (s *Store) QueryApplications(ctx context.Context, query *storage.Query) ([]*models.Application, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := []*models.Application{}
+	for _, app := range s.applications {
+		if app.Tenant == query.Tenant && query.Match(app) {
+			matched = append(matched, app)
+		}
+	}
+
+	if query.OrderBy != "" {
+		cmp, ok := storage.OrderKeys[query.OrderBy]
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid order %q", query.OrderBy)
+		}
+		slices.SortStableFunc(matched, func(a, b *models.Application) int {
+			if query.Desc {
+				return cmp(b, a)
+			}
+			return cmp(a, b)
+		})
+	}
+
+	start := min(query.Offset, len(matched))
+	end := len(matched)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, end)
+	}
+	res := make([]*models.Application, 0, end-start)
+	for _, app := range matched[start:end] {
+		res = append(res, app.Clone())
+	}
+	return res, len(matched), nil
+}
+
+func (s *Store) ListTenants(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenants := []string{}
+	for _, app := range s.applications {
+		if !slices.Contains(tenants, app.Tenant) {
+			tenants = append(tenants, app.Tenant)
+		}
+	}
+	slices.Sort(tenants)
+	return tenants, nil
+}
+
+func (s *Store) CountApplications(ctx context.Context) (map[gcp.Status]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[gcp.Status]int)
+	for _, app := range s.applications {
+		counts[app.Status]++
+	}
+	return counts, nil
+}
+
+func (s *Store) GetApplication(ctx context.Context, tenant, id string) (*models.Application, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if i := s.find(tenant, id); i >= 0 {
+		return s.applications[i].Clone(), nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *Store) AddApplications(ctx context.Context, applications []*models.Application) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, app := range applications {
+		s.applications = append(s.applications, app.Clone())
+	}
+
+	return nil
+}
+
+func (s *Store) UpdateApplication(ctx context.Context, application *models.Application) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(application.Tenant, application.ID)
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	s.applications[i] = application.Clone()
+
+	return nil
+}
+
+func (s *Store) SaveApplications(ctx context.Context, added, updated []*models.Application) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// nothing is changed unless every updated application is found
+	indexes := make([]int, len(updated))
+	for j, application := range updated {
+		if indexes[j] = s.find(application.Tenant, application.ID); indexes[j] < 0 {
+			return storage.ErrNotFound
+		}
+	}
+	for j, application := range updated {
+		s.applications[indexes[j]] = application.Clone()
+	}
+	for _, app := range added {
+		s.applications = append(s.applications, app.Clone())
+	}
+
+	return nil
+}
+
+func (s *Store) DeleteApplication(ctx context.Context, tenant, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(tenant, id)
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	s.applications = append(s.applications[:i], s.applications[i+1:]...)
+
+	return nil
+}
+
+// find returns the index of the application of the tenant with the given id, or -1; callers must hold the lock
+func (s *Store) find(tenant, id string) int {
+	for i, app := range s.applications {
+		if app.Tenant == tenant && app.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Store) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close saves the applications to the snapshot file, if any. The file is replaced at once so a
+// failed save keeps the previous snapshot.
+func (s *Store) Close() error {
+	if s.snapshot == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	b, err := json.Marshal(s.applications)
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.snapshot), filepath.Base(s.snapshot)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.snapshot)
+}
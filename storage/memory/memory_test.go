@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+	"github.com/MaxBear/maxhire/storage"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "applications.json")
+
+	store, err := Open(path)
+	require.NoError(t, err)
+	applications, err := store.ListApplications(ctx, "jane")
+	require.NoError(t, err)
+	assert.Empty(t, applications)
+
+	application := &models.Application{
+		ID:      "1",
+		Date:    time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		Company: "TestCompany",
+		Status:  gcp.Interviewing,
+		Interviews: []models.Interview{
+			{DateTime: time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC), InterviewType: models.TechCoding, DurationMin: 60},
+		},
+		Tenant: "jane",
+	}
+	require.NoError(t, store.AddApplications(ctx, []*models.Application{application}))
+	require.NoError(t, store.Close())
+
+	store, err = Open(path)
+	require.NoError(t, err)
+	applications, err = store.ListApplications(ctx, "jane")
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Application{application}, applications)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	_, err = Open(path)
+	assert.Error(t, err)
+}
+
+func TestSaveApplications_Atomic(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, store.AddApplications(ctx, []*models.Application{{ID: "1", Date: date, Company: "TestCompany"}}))
+
+	err := store.SaveApplications(ctx,
+		[]*models.Application{{ID: "2", Date: date, Company: "Other"}},
+		[]*models.Application{{ID: "1", Date: date, Company: "Renamed"}, {ID: "missing", Date: date}})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	applications, err := store.ListApplications(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, applications, 1)
+	assert.Equal(t, "TestCompany", applications[0].Company)
+}
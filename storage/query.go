@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+)
+
+// Orders of the applications returned by QueryApplications
+const (
+	OrderDate         = "date"
+	OrderCompany      = "company"
+	OrderStatus       = "status"
+	OrderLastActivity = "last_activity"
+)
+
+// OrderKeys compares two applications on each order
+var OrderKeys = map[string]func(a, b *models.Application) int{
+	OrderDate: func(a, b *models.Application) int {
+		return a.Date.Compare(b.Date)
+	},
+	OrderCompany: func(a, b *models.Application) int {
+		return strings.Compare(strings.ToLower(a.Company), strings.ToLower(b.Company))
+	},
+	OrderStatus: func(a, b *models.Application) int {
+		return int(a.Status) - int(b.Status)
+	},
+	OrderLastActivity: func(a, b *models.Application) int {
+		return a.LastActivity().Compare(b.LastActivity())
+	},
+}
+
+// Query selects, orders and pages the applications of a tenant. The zero value of a filter matches
+// every application.
+type Query struct {
+	Tenant string
+
+	Status *gcp.Status
+	// Match any of the statuses
+	Statuses []gcp.Status
+	// Case-insensitive, the whole name or a prefix of it with CompanyPrefix
+	Company       string
+	CompanyPrefix bool
+	// Every word must appear in the position or the subject, case-insensitive
+	Words []string
+	// Match applications with (true) or without (false) interviews
+	HasInterviews *bool
+	// Match applications having an interview of any of the types
+	InterviewTypes []models.InterviewType
+	StartDate      *time.Time
+	EndDate        *time.Time
+
+	// One of the Order constants, applications are returned in the order they were added if empty.
+	// Applications with equal keys keep the order they were added in, in both directions.
+	OrderBy string
+	Desc    bool
+
+	// Number of matching applications to skip
+	Offset int
+	// Maximum number of applications to return, all of them if 0
+	Limit int
+}
+
+// Match reports whether the application passes the filters of the query, its tenant is not checked
+func (q *Query) Match(app *models.Application) bool {
+	if q.Company != "" {
+		company := strings.ToLower(strings.TrimSpace(app.Company))
+		want := strings.ToLower(strings.TrimSpace(q.Company))
+		if q.CompanyPrefix && !strings.HasPrefix(company, want) {
+			return false
+		}
+		if !q.CompanyPrefix && company != want {
+			return false
+		}
+	}
+
+	if q.Status != nil && app.Status != *q.Status {
+		return false
+	}
+	if len(q.Statuses) > 0 && !slices.Contains(q.Statuses, app.Status) {
+		return false
+	}
+
+	if len(q.Words) > 0 {
+		text := strings.ToLower(app.Position + " " + app.Subject)
+		for _, word := range q.Words {
+			if !strings.Contains(text, strings.ToLower(word)) {
+				return false
+			}
+		}
+	}
+
+	if q.HasInterviews != nil && (len(app.Interviews) > 0) != *q.HasInterviews {
+		return false
+	}
+	if len(q.InterviewTypes) > 0 && !slices.ContainsFunc(app.Interviews, func(interview models.Interview) bool {
+		return slices.Contains(q.InterviewTypes, interview.InterviewType)
+	}) {
+		return false
+	}
+
+	if q.StartDate != nil && app.Date.Before(*q.StartDate) {
+		return false
+	}
+	if q.EndDate != nil && app.Date.After(*q.EndDate) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,546 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+	"github.com/MaxBear/maxhire/storage"
+)
+
+// migrations are applied in order on startup, the index of a migration + 1 is its schema version.
+// Never edit an existing migration, append a new one instead.
+var migrations = []string{
+	`CREATE TABLE applications (
+		id       INTEGER PRIMARY KEY AUTOINCREMENT,
+		date     TEXT    NOT NULL,
+		company  TEXT    NOT NULL,
+		position TEXT    NOT NULL DEFAULT '',
+		status   INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX applications_company_date ON applications (company, date);
+	CREATE TABLE interviews (
+		id             INTEGER PRIMARY KEY AUTOINCREMENT,
+		application_id INTEGER NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+		datetime       TEXT    NOT NULL,
+		interview_type INTEGER NOT NULL DEFAULT 0,
+		duration_min   INTEGER NOT NULL DEFAULT 15
+	);
+	CREATE INDEX interviews_application_id ON interviews (application_id);`,
+	`ALTER TABLE applications ADD COLUMN uid TEXT;
+	UPDATE applications SET uid = lower(hex(randomblob(16))) WHERE uid IS NULL;
+	CREATE UNIQUE INDEX applications_uid ON applications (uid);`,
+	`ALTER TABLE applications ADD COLUMN message_id TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE applications ADD COLUMN subject TEXT NOT NULL DEFAULT '';`,
+	`CREATE TABLE events (
+		id              INTEGER PRIMARY KEY AUTOINCREMENT,
+		application_id  INTEGER NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+		time            TEXT    NOT NULL,
+		type            INTEGER NOT NULL,
+		source          INTEGER NOT NULL,
+		previous_status INTEGER NOT NULL DEFAULT 0,
+		status          INTEGER NOT NULL DEFAULT 0,
+		interview_type  INTEGER NOT NULL DEFAULT 0,
+		interview_time  TEXT    NOT NULL DEFAULT '',
+		message_id      TEXT    NOT NULL DEFAULT ''
+	);
+	CREATE INDEX events_application_id ON events (application_id);`,
+	`ALTER TABLE applications ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+	CREATE INDEX applications_tenant ON applications (tenant);`,
+}
+
+// Store persists applications in an embedded SQLite database file.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (or creates) the database at path and migrates it to the latest schema version.
+func Open(ctx context.Context, path string) (*Store, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer, serialize access through one connection
+	db.SetMaxOpenConns(1)
+
+	s := &Store{
+		db: db,
+	}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Store) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return fmt.Errorf("error creating schema_migrations table, error: %w", err)
+	}
+
+	var version int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return fmt.Errorf("error reading schema version, error: %w", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error applying migration %d, error: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, i+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		log.Printf("applied database migration %d", i+1)
+	}
+
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// maxChildrenIds is the most applications whose interviews and events are selected by id, the children
+// of more applications are all read and filtered instead
+const maxChildrenIds = 1000
+
+const selectApplications = `SELECT id, uid, tenant, date, company, position, status, message_id, subject FROM applications`
+
+// scanApplications reads the application rows and attaches their interviews
+func (s *Store) scanApplications(ctx context.Context, rows *sql.Rows) ([]*models.Application, error) {
+	defer rows.Close()
+
+	applications := []*models.Application{}
+	byId := make(map[int64]*models.Application)
+	for rows.Next() {
+		var (
+			id     int64
+			date   string
+			status int
+			app    = &models.Application{Interviews: []models.Interview{}}
+		)
+		if err := rows.Scan(&id, &app.ID, &app.Tenant, &date, &app.Company, &app.Position, &status, &app.MessageID, &app.Subject); err != nil {
+			return nil, err
+		}
+		var err error
+		if app.Date, err = parseTime(date); err != nil {
+			return nil, fmt.Errorf("invalid date stored for application %s, error: %w", app.ID, err)
+		}
+		app.Status = gcp.Status(status)
+		applications = append(applications, app)
+		byId[id] = app
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(applications) == 0 {
+		return applications, nil
+	}
+
+	// only load the children of the selected applications, unless there are too many to list them
+	where := ""
+	args := []any{}
+	if len(byId) <= maxChildrenIds {
+		for id := range byId {
+			args = append(args, id)
+		}
+		where = ` WHERE application_id IN (` + placeholders(len(args)) + `)`
+	}
+
+	if err := s.scanInterviews(ctx, byId, where, args); err != nil {
+		return nil, err
+	}
+	if err := s.scanEvents(ctx, byId, where, args); err != nil {
+		return nil, err
+	}
+
+	return applications, nil
+}
+
+func (s *Store) scanInterviews(ctx context.Context, byId map[int64]*models.Application, where string, args []any) error {
+	interviews, err := s.db.QueryContext(ctx,
+		`SELECT application_id, datetime, interview_type, duration_min FROM interviews`+where+` ORDER BY application_id, id`, args...)
+	if err != nil {
+		return err
+	}
+	defer interviews.Close()
+
+	for interviews.Next() {
+		var (
+			appId     int64
+			datetime  string
+			interview models.Interview
+		)
+		if err := interviews.Scan(&appId, &datetime, &interview.InterviewType, &interview.DurationMin); err != nil {
+			return err
+		}
+		app, ok := byId[appId]
+		if !ok {
+			continue
+		}
+		if interview.DateTime, err = parseTime(datetime); err != nil {
+			return fmt.Errorf("invalid datetime stored for interview of application %s, error: %w", app.ID, err)
+		}
+		app.Interviews = append(app.Interviews, interview)
+	}
+
+	return interviews.Err()
+}
+
+func (s *Store) scanEvents(ctx context.Context, byId map[int64]*models.Application, where string, args []any) error {
+	events, err := s.db.QueryContext(ctx,
+		`SELECT application_id, time, type, source, previous_status, status, interview_type, interview_time, message_id FROM events`+where+` ORDER BY application_id, id`, args...)
+	if err != nil {
+		return err
+	}
+	defer events.Close()
+
+	for events.Next() {
+		var (
+			appId         int64
+			eventTime     string
+			interviewTime string
+			e             models.Event
+		)
+		err := events.Scan(&appId, &eventTime, &e.Type, &e.Source, &e.PreviousStatus, &e.Status, &e.InterviewType, &interviewTime, &e.MessageID)
+		if err != nil {
+			return err
+		}
+		app, ok := byId[appId]
+		if !ok {
+			continue
+		}
+		if e.Time, err = parseTime(eventTime); err != nil {
+			return fmt.Errorf("invalid time stored for event of application %s, error: %w", app.ID, err)
+		}
+		if interviewTime != "" {
+			if e.InterviewTime, err = parseTime(interviewTime); err != nil {
+				return fmt.Errorf("invalid interview time stored for event of application %s, error: %w", app.ID, err)
+			}
+		}
+		app.Events = append(app.Events, e)
+	}
+
+	return events.Err()
+}
+
+func (s *Store) ListApplications(ctx context.Context, tenant string) ([]*models.Application, error) {
+	rows, err := s.db.QueryContext(ctx, selectApplications+` WHERE tenant = ? ORDER BY id`, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return s.scanApplications(ctx, rows)
+}
+
+// placeholders returns n comma separated query parameters
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// orderColumns are the expressions ordering the applications on each storage order
+var orderColumns = map[string]string{
+	storage.OrderDate:    `julianday(date)`,
+	storage.OrderCompany: `lower(company)`,
+	storage.OrderStatus:  `status`,
+	storage.OrderLastActivity: `max(julianday(date),
+		COALESCE((SELECT MAX(julianday(datetime)) FROM interviews WHERE application_id = applications.id), 0),
+		COALESCE((SELECT MAX(julianday(time)) FROM events WHERE application_id = applications.id), 0))`,
+}
+
+// queryWhere returns the condition selecting the applications of the query, and its parameters
+func queryWhere(query *storage.Query) (string, []any) {
+	conds := []string{`tenant = ?`}
+	args := []any{query.Tenant}
+
+	if query.Company != "" {
+		company := strings.ToLower(strings.TrimSpace(query.Company))
+		if query.CompanyPrefix {
+			conds = append(conds, `instr(lower(trim(company)), ?) = 1`)
+		} else {
+			conds = append(conds, `lower(trim(company)) = ?`)
+		}
+		args = append(args, company)
+	}
+
+	if query.Status != nil {
+		conds = append(conds, `status = ?`)
+		args = append(args, int(*query.Status))
+	}
+	if len(query.Statuses) > 0 {
+		conds = append(conds, `status IN (`+placeholders(len(query.Statuses))+`)`)
+		for _, status := range query.Statuses {
+			args = append(args, int(status))
+		}
+	}
+
+	for _, word := range query.Words {
+		conds = append(conds, `instr(lower(position || ' ' || subject), ?) > 0`)
+		args = append(args, strings.ToLower(word))
+	}
+
+	if query.HasInterviews != nil {
+		exists := `EXISTS (SELECT 1 FROM interviews WHERE application_id = applications.id)`
+		if !*query.HasInterviews {
+			exists = `NOT ` + exists
+		}
+		conds = append(conds, exists)
+	}
+	if len(query.InterviewTypes) > 0 {
+		conds = append(conds, `EXISTS (SELECT 1 FROM interviews WHERE application_id = applications.id AND interview_type IN (`+
+			placeholders(len(query.InterviewTypes))+`))`)
+		for _, interviewType := range query.InterviewTypes {
+			args = append(args, int(interviewType))
+		}
+	}
+
+	if query.StartDate != nil {
+		conds = append(conds, `julianday(date) >= julianday(?)`)
+		args = append(args, formatTime(*query.StartDate))
+	}
+	if query.EndDate != nil {
+		conds = append(conds, `julianday(date) <= julianday(?)`)
+		args = append(args, formatTime(*query.EndDate))
+	}
+
+	return ` WHERE ` + strings.Join(conds, ` AND `), args
+}
+
+func (s *Store) QueryApplications(ctx context.Context, query *storage.Query) ([]*models.Application, int, error) {
+	where, args := queryWhere(query)
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM applications`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	order := ` ORDER BY id`
+	if query.OrderBy != "" {
+		column, ok := orderColumns[query.OrderBy]
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid order %q", query.OrderBy)
+		}
+		direction := ` ASC`
+		if query.Desc {
+			direction = ` DESC`
+		}
+		// applications with equal keys keep the order they were added in
+		order = ` ORDER BY ` + column + direction + `, id`
+	}
+
+	limit := -1
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+	rows, err := s.db.QueryContext(ctx, selectApplications+where+order+` LIMIT ? OFFSET ?`, append(args, limit, query.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	applications, err := s.scanApplications(ctx, rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return applications, total, nil
+}
+
+func (s *Store) ListTenants(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT tenant FROM applications ORDER BY tenant`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tenants := []string{}
+	for rows.Next() {
+		var tenant string
+		if err := rows.Scan(&tenant); err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+func (s *Store) CountApplications(ctx context.Context) (map[gcp.Status]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM applications GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[gcp.Status]int)
+	for rows.Next() {
+		var status, count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[gcp.Status(status)] = count
+	}
+	return counts, rows.Err()
+}
+
+func (s *Store) GetApplication(ctx context.Context, tenant, id string) (*models.Application, error) {
+	rows, err := s.db.QueryContext(ctx, selectApplications+` WHERE tenant = ? AND uid = ?`, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	applications, err := s.scanApplications(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(applications) == 0 {
+		return nil, storage.ErrNotFound
+	}
+	return applications[0], nil
+}
+
+func (s *Store) AddApplications(ctx context.Context, applications []*models.Application) error {
+	return s.SaveApplications(ctx, applications, nil)
+}
+
+func (s *Store) SaveApplications(ctx context.Context, added, updated []*models.Application) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, app := range added {
+		if err := addApplication(ctx, tx, app); err != nil {
+			return err
+		}
+	}
+	for _, app := range updated {
+		if err := updateApplication(ctx, tx, app); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func addApplication(ctx context.Context, tx *sql.Tx, app *models.Application) error {
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO applications (uid, tenant, date, company, position, status, message_id, subject) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		app.ID, app.Tenant, formatTime(app.Date), app.Company, app.Position, int(app.Status), app.MessageID, app.Subject)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if err := insertInterviews(ctx, tx, id, app.Interviews); err != nil {
+		return err
+	}
+	return insertEvents(ctx, tx, id, app.Events)
+}
+
+func insertInterviews(ctx context.Context, tx *sql.Tx, appId int64, interviews []models.Interview) error {
+	for _, interview := range interviews {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO interviews (application_id, datetime, interview_type, duration_min) VALUES (?, ?, ?, ?)`,
+			appId, formatTime(interview.DateTime), int(interview.InterviewType), interview.DurationMin)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertEvents(ctx context.Context, tx *sql.Tx, appId int64, events []models.Event) error {
+	for _, e := range events {
+		interviewTime := ""
+		if !e.InterviewTime.IsZero() {
+			interviewTime = formatTime(e.InterviewTime)
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO events (application_id, time, type, source, previous_status, status, interview_type, interview_time, message_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			appId, formatTime(e.Time), int(e.Type), int(e.Source), int(e.PreviousStatus), int(e.Status),
+			int(e.InterviewType), interviewTime, e.MessageID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) UpdateApplication(ctx context.Context, app *models.Application) error {
+	return s.SaveApplications(ctx, nil, []*models.Application{app})
+}
+
+func updateApplication(ctx context.Context, tx *sql.Tx, app *models.Application) error {
+	var id int64
+	err := tx.QueryRowContext(ctx, `SELECT id FROM applications WHERE tenant = ? AND uid = ?`, app.Tenant, app.ID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return storage.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE applications SET date = ?, company = ?, position = ?, status = ?, message_id = ?, subject = ? WHERE id = ?`,
+		formatTime(app.Date), app.Company, app.Position, int(app.Status), app.MessageID, app.Subject, id)
+	if err != nil {
+		return err
+	}
+
+	// Replace the interviews and the timeline
+	if _, err := tx.ExecContext(ctx, `DELETE FROM interviews WHERE application_id = ?`, id); err != nil {
+		return err
+	}
+	if err := insertInterviews(ctx, tx, id, app.Interviews); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM events WHERE application_id = ?`, id); err != nil {
+		return err
+	}
+	return insertEvents(ctx, tx, id, app.Events)
+}
+
+func (s *Store) DeleteApplication(ctx context.Context, tenant, id string) error {
+	// interviews are removed by the ON DELETE CASCADE constraint
+	res, err := s.db.ExecContext(ctx, `DELETE FROM applications WHERE tenant = ? AND uid = ?`, tenant, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
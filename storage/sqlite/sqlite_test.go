@@ -0,0 +1,295 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+	"github.com/MaxBear/maxhire/storage"
+)
+
+func setup(t *testing.T) (*Store, string, context.Context) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "maxhire.db")
+
+	s, err := Open(ctx, path)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	return s, path, ctx
+}
+
+func TestAddApplications_PersistAcrossReopen(t *testing.T) {
+	s, path, ctx := setup(t)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	err := s.AddApplications(ctx, []*models.Application{
+		{
+			ID:       "app-1",
+			Tenant:   "jane",
+			Date:     testDate,
+			Company:  "TestCompany",
+			Position: "Software Engineer",
+			Status:   gcp.Reject,
+			Interviews: []models.Interview{
+				{
+					DateTime:      time.Date(2024, 1, 20, 14, 0, 0, 0, time.UTC),
+					InterviewType: models.RecruiterScreen,
+					DurationMin:   30,
+				},
+			},
+			Events: []models.Event{
+				{Time: testDate, Type: models.StatusChanged, Source: models.SourceLlm, Status: gcp.Pending},
+				{Time: testDate.Add(time.Hour), Type: models.InterviewScheduled, InterviewType: models.RecruiterScreen,
+					InterviewTime: time.Date(2024, 1, 20, 14, 0, 0, 0, time.UTC)},
+				{Time: testDate.Add(2 * time.Hour), Type: models.StatusChanged, Source: models.SourceRule,
+					PreviousStatus: gcp.Pending, Status: gcp.Reject},
+			},
+		},
+		{
+			ID:      "app-2",
+			Date:    testDate.Add(time.Hour),
+			Company: "OtherCompany",
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	// migrations must be idempotent when the database already exists
+	reopened, err := Open(ctx, path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	apps, err := reopened.ListApplications(ctx, "jane")
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, "app-1", apps[0].ID)
+	assert.Equal(t, "jane", apps[0].Tenant)
+	assert.Equal(t, testDate, apps[0].Date)
+	assert.Equal(t, "TestCompany", apps[0].Company)
+	assert.Equal(t, "Software Engineer", apps[0].Position)
+	assert.Equal(t, gcp.Reject, apps[0].Status)
+	require.Len(t, apps[0].Interviews, 1)
+	assert.Equal(t, models.RecruiterScreen, apps[0].Interviews[0].InterviewType)
+	assert.Equal(t, int32(30), apps[0].Interviews[0].DurationMin)
+	require.Len(t, apps[0].Events, 3)
+	assert.Equal(t, models.SourceLlm, apps[0].Events[0].Source)
+	assert.True(t, apps[0].Events[0].InterviewTime.IsZero())
+	assert.Equal(t, models.InterviewScheduled, apps[0].Events[1].Type)
+	assert.Equal(t, time.Date(2024, 1, 20, 14, 0, 0, 0, time.UTC), apps[0].Events[1].InterviewTime)
+	assert.Equal(t, gcp.Pending, apps[0].Events[2].PreviousStatus)
+	assert.Equal(t, gcp.Reject, apps[0].Events[2].Status)
+
+	apps, err = reopened.ListApplications(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, "OtherCompany", apps[0].Company)
+	assert.Equal(t, "", apps[0].Tenant)
+	assert.Len(t, apps[0].Events, 0)
+	assert.Len(t, apps[0].Interviews, 0)
+}
+
+func TestUpdateApplication_ReplaceInterviews(t *testing.T) {
+	s, _, ctx := setup(t)
+
+	testDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	err := s.AddApplications(ctx, []*models.Application{
+		{
+			ID:      "app-1",
+			Date:    testDate,
+			Company: "TestCompany",
+			Interviews: []models.Interview{
+				{DateTime: testDate, InterviewType: models.ManagerScreen, DurationMin: 45},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	app, err := s.GetApplication(ctx, "", "app-1")
+	require.NoError(t, err)
+	app.Status = gcp.Success
+	app.Interviews = []models.Interview{
+		{DateTime: testDate.Add(24 * time.Hour), InterviewType: models.TechCoding, DurationMin: 60},
+		{DateTime: testDate.Add(48 * time.Hour), InterviewType: models.TeamMatch, DurationMin: 30},
+	}
+	require.NoError(t, s.UpdateApplication(ctx, app))
+
+	app, err = s.GetApplication(ctx, "", "app-1")
+	require.NoError(t, err)
+	assert.Equal(t, gcp.Success, app.Status)
+	require.Len(t, app.Interviews, 2)
+	assert.Equal(t, models.TechCoding, app.Interviews[0].InterviewType)
+	assert.Equal(t, models.TeamMatch, app.Interviews[1].InterviewType)
+}
+
+func TestDeleteApplication(t *testing.T) {
+	s, _, ctx := setup(t)
+
+	err := s.AddApplications(ctx, []*models.Application{
+		{
+			ID:      "app-1",
+			Date:    time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			Company: "TestCompany",
+			Interviews: []models.Interview{
+				{DateTime: time.Date(2024, 1, 20, 14, 0, 0, 0, time.UTC), InterviewType: models.ManagerScreen, DurationMin: 45},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeleteApplication(ctx, "", "app-1"))
+
+	_, err = s.GetApplication(ctx, "", "app-1")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	var n int
+	require.NoError(t, s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM interviews`).Scan(&n))
+	assert.Equal(t, 0, n, "interviews must be deleted with their application")
+}
+
+func TestNotFound(t *testing.T) {
+	s, _, ctx := setup(t)
+
+	_, err := s.GetApplication(ctx, "", "missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	err = s.UpdateApplication(ctx, &models.Application{ID: "missing"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	err = s.DeleteApplication(ctx, "", "missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestSaveApplications_Atomic(t *testing.T) {
+	s, _, ctx := setup(t)
+
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, s.AddApplications(ctx, []*models.Application{{ID: "app-1", Date: date, Company: "TestCompany"}}))
+
+	// the missing update rolls back the whole batch
+	err := s.SaveApplications(ctx,
+		[]*models.Application{{ID: "app-2", Date: date, Company: "Other"}},
+		[]*models.Application{{ID: "app-1", Date: date, Company: "Renamed"}, {ID: "missing", Date: date}})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	applications, err := s.ListApplications(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, applications, 1)
+	assert.Equal(t, "TestCompany", applications[0].Company)
+
+	require.NoError(t, s.SaveApplications(ctx,
+		[]*models.Application{{ID: "app-2", Date: date, Company: "Other"}},
+		[]*models.Application{{ID: "app-1", Date: date, Company: "Renamed"}}))
+	applications, err = s.ListApplications(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, applications, 2)
+	app, err := s.GetApplication(ctx, "", "app-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", app.Company)
+}
+
+func TestTenants(t *testing.T) {
+	s, _, ctx := setup(t)
+
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, s.AddApplications(ctx, []*models.Application{
+		{ID: "app-1", Tenant: "jane", Date: date, Company: "TestCompany", Status: gcp.Applied},
+		{ID: "app-2", Tenant: "john", Date: date, Company: "OtherCompany", Status: gcp.Applied},
+		{ID: "app-3", Tenant: "john", Date: date, Company: "TestCompany", Status: gcp.Reject},
+	}))
+
+	applications, err := s.ListApplications(ctx, "john")
+	require.NoError(t, err)
+	require.Len(t, applications, 2)
+	assert.Equal(t, "app-2", applications[0].ID)
+	assert.Equal(t, "app-3", applications[1].ID)
+
+	tenants, err := s.ListTenants(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jane", "john"}, tenants)
+
+	counts, err := s.CountApplications(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[gcp.Status]int{gcp.Applied: 2, gcp.Reject: 1}, counts)
+
+	// the applications of other tenants are not found
+	_, err = s.GetApplication(ctx, "jane", "app-2")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	err = s.UpdateApplication(ctx, &models.Application{ID: "app-2", Tenant: "jane", Date: date, Company: "Renamed"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	err = s.DeleteApplication(ctx, "jane", "app-2")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	app, err := s.GetApplication(ctx, "john", "app-2")
+	require.NoError(t, err)
+	assert.Equal(t, "OtherCompany", app.Company)
+}
+
+func TestQueryApplications(t *testing.T) {
+	s, _, ctx := setup(t)
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 10, 0, 0, 0, time.UTC)
+	}
+	require.NoError(t, s.AddApplications(ctx, []*models.Application{
+		{ID: "app-1", Date: day(3), Company: "Stripe", Position: "Backend Engineer, Data", Subject: "Thanks for applying to Stripe!", Status: gcp.Pending},
+		{ID: "app-2", Date: day(1), Company: "Stripe Payments", Position: "Full Stack Engineer", Status: gcp.Applied},
+		{ID: "app-3", Date: day(4), Company: "lyft", Position: "Software Engineer", Subject: "Infrastructure Automation", Status: gcp.Reject,
+			Interviews: []models.Interview{{DateTime: day(20), InterviewType: models.RecruiterScreen, DurationMin: 30}}},
+		{ID: "app-4", Date: day(2), Company: "Zapier", Position: "Sr. Software Engineer (L4)", Status: gcp.Success,
+			Interviews: []models.Interview{{DateTime: day(12), InterviewType: models.TechCoding, DurationMin: 60}}},
+		{ID: "app-5", Tenant: "jane", Date: day(1), Company: "Stripe", Status: gcp.Pending},
+	}))
+
+	hasInterviews := true
+	noInterviews := false
+	rejected := gcp.Reject
+	start, end := day(2), day(3)
+
+	tcs := []struct {
+		name  string
+		query storage.Query
+		ids   []string
+		total int
+	}{
+		{"all", storage.Query{}, []string{"app-1", "app-2", "app-3", "app-4"}, 4},
+		{"tenant", storage.Query{Tenant: "jane"}, []string{"app-5"}, 1},
+		{"company is case-insensitive", storage.Query{Company: " STRIPE "}, []string{"app-1"}, 1},
+		{"company prefix", storage.Query{Company: "stripe", CompanyPrefix: true}, []string{"app-1", "app-2"}, 2},
+		{"status", storage.Query{Status: &rejected}, []string{"app-3"}, 1},
+		{"statuses", storage.Query{Statuses: []gcp.Status{gcp.Pending, gcp.Applied}}, []string{"app-1", "app-2"}, 2},
+		{"words span position and subject", storage.Query{Words: []string{"Backend", "applying"}}, []string{"app-1"}, 1},
+		{"has interviews", storage.Query{HasInterviews: &hasInterviews}, []string{"app-3", "app-4"}, 2},
+		{"has no interviews", storage.Query{HasInterviews: &noInterviews}, []string{"app-1", "app-2"}, 2},
+		{"interview type", storage.Query{InterviewTypes: []models.InterviewType{models.TechCoding}}, []string{"app-4"}, 1},
+		{"dates", storage.Query{StartDate: &start, EndDate: &end}, []string{"app-1", "app-4"}, 2},
+		{"date desc", storage.Query{OrderBy: storage.OrderDate, Desc: true}, []string{"app-3", "app-1", "app-4", "app-2"}, 4},
+		{"company", storage.Query{OrderBy: storage.OrderCompany}, []string{"app-3", "app-1", "app-2", "app-4"}, 4},
+		{"status", storage.Query{OrderBy: storage.OrderStatus}, []string{"app-1", "app-3", "app-4", "app-2"}, 4},
+		{"last activity desc", storage.Query{OrderBy: storage.OrderLastActivity, Desc: true}, []string{"app-3", "app-4", "app-1", "app-2"}, 4},
+		{"page", storage.Query{OrderBy: storage.OrderDate, Offset: 1, Limit: 2}, []string{"app-4", "app-1"}, 4},
+		{"past the end", storage.Query{Offset: 10, Limit: 2}, []string{}, 4},
+	}
+
+	for _, tc := range tcs {
+		applications, total, err := s.QueryApplications(ctx, &tc.query)
+		require.NoError(t, err, tc.name)
+		ids := []string{}
+		for _, app := range applications {
+			ids = append(ids, app.ID)
+		}
+		assert.Equal(t, tc.ids, ids, tc.name)
+		assert.Equal(t, tc.total, total, tc.name)
+	}
+
+	// the page carries the children of its applications
+	applications, _, err := s.QueryApplications(ctx, &storage.Query{OrderBy: storage.OrderDate, Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, applications, 1)
+	require.Len(t, applications[0].Interviews, 1)
+	assert.Equal(t, models.TechCoding, applications[0].Interviews[0].InterviewType)
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+)
+
+var ErrNotFound = errors.New("application not found")
+
+// Store persists job applications and their interviews for the service layer.
+// Applications are identified by their ID, which is assigned before they are added, and belong to
+// their tenant: the applications of other tenants are not found.
+type Store interface {
+	// ListApplications returns the applications of the tenant in the order they were added
+	ListApplications(ctx context.Context, tenant string) ([]*models.Application, error)
+	// QueryApplications returns the applications of the query tenant matching its filters, in its order
+	// and paged, with the number of matching applications across all pages
+	QueryApplications(context.Context, *Query) ([]*models.Application, int, error)
+	// ListTenants returns the tenants having applications
+	ListTenants(context.Context) ([]string, error)
+	// CountApplications returns the number of applications of every tenant, by status
+	CountApplications(context.Context) (map[gcp.Status]int, error)
+	// GetApplication returns ErrNotFound if the tenant has no application with the given ID
+	GetApplication(ctx context.Context, tenant, id string) (*models.Application, error)
+	AddApplications(context.Context, []*models.Application) error
+	// UpdateApplication replaces the stored application with the same ID and tenant, including its interviews,
+	// returns ErrNotFound if there is no such application
+	UpdateApplication(context.Context, *models.Application) error
+	// SaveApplications adds the added applications and replaces the updated ones at once: if one of them
+	// can't be stored, e.g. an updated application is not found, none is and the error is returned
+	SaveApplications(ctx context.Context, added, updated []*models.Application) error
+	// DeleteApplication returns ErrNotFound if the tenant has no application with the given ID
+	DeleteApplication(ctx context.Context, tenant, id string) error
+	// Ping returns an error if the store can't serve requests
+	Ping(context.Context) error
+	Close() error
+}
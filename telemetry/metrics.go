@@ -0,0 +1,244 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/storage"
+)
+
+// Registry holds the metrics of the process
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+}
+
+var (
+	grpcRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "maxhire_grpc_server_requests_total",
+		Help: "Number of grpc calls completed by the server, by method and status code.",
+	}, []string{"method", "code"})
+	grpcDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "maxhire_grpc_server_request_duration_seconds",
+		Help:    "Duration of the grpc calls completed by the server, by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	appScriptCalls = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "maxhire_appscript_calls_total",
+		Help: "Number of Google Apps Script calls, by result.",
+	}, []string{"result"})
+	appScriptDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Name:    "maxhire_appscript_call_duration_seconds",
+		Help:    "Duration of the Google Apps Script calls.",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 10),
+	})
+	appScriptEmails = factory.NewCounter(prometheus.CounterOpts{
+		Name: "maxhire_appscript_emails_total",
+		Help: "Number of emails returned by the Google Apps Script.",
+	})
+
+	sourceFetches = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "maxhire_source_fetches_total",
+		Help: "Number of fetches of the emails of a source, by source and result.",
+	}, []string{"source", "result"})
+	sourceDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "maxhire_source_fetch_duration_seconds",
+		Help:    "Duration of the fetches of the emails of a source, by source.",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"source"})
+	sourceEmails = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "maxhire_source_emails_total",
+		Help: "Number of emails fetched from a source, by source.",
+	}, []string{"source"})
+
+	llmCalls = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "maxhire_llm_calls_total",
+		Help: "Number of LLM calls, by provider and result.",
+	}, []string{"provider", "result"})
+	llmDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "maxhire_llm_call_duration_seconds",
+		Help:    "Duration of the LLM calls, by provider.",
+		Buckets: prometheus.ExponentialBuckets(0.25, 2, 8),
+	}, []string{"provider"})
+	llmTokens = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "maxhire_llm_tokens_total",
+		Help: "Number of tokens used by the LLM calls, by provider and kind: prompt or completion.",
+	}, []string{"provider", "kind"})
+	llmRetries = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "maxhire_llm_retries_total",
+		Help: "Number of LLM calls retried after a failure, by provider.",
+	}, []string{"provider"})
+
+	classifierEmails = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "maxhire_classifier_emails_total",
+		Help: "Number of emails classified by the rules, by ats and result: rules or fallback.",
+	}, []string{"ats", "result"})
+)
+
+// result is the label of a call returning err
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// ObserveAppScriptCall records a Google Apps Script call which started at start and returned emails
+func ObserveAppScriptCall(start time.Time, emails int, err error) {
+	appScriptCalls.WithLabelValues(result(err)).Inc()
+	appScriptDuration.Observe(time.Since(start).Seconds())
+	appScriptEmails.Add(float64(emails))
+}
+
+// ObserveSourceFetch records a fetch of the emails of source which started at start and returned emails
+func ObserveSourceFetch(source string, start time.Time, emails int, err error) {
+	sourceFetches.WithLabelValues(source, result(err)).Inc()
+	sourceDuration.WithLabelValues(source).Observe(time.Since(start).Seconds())
+	sourceEmails.WithLabelValues(source).Add(float64(emails))
+}
+
+// ObserveLLMCall records a call to the LLM of provider which started at start
+func ObserveLLMCall(provider string, start time.Time, promptTokens, completionTokens int, err error) {
+	llmCalls.WithLabelValues(provider, result(err)).Inc()
+	llmDuration.WithLabelValues(provider).Observe(time.Since(start).Seconds())
+	llmTokens.WithLabelValues(provider, "prompt").Add(float64(promptTokens))
+	llmTokens.WithLabelValues(provider, "completion").Add(float64(completionTokens))
+}
+
+// ObserveLLMRetry records a call to the LLM of provider retried after a failure
+func ObserveLLMRetry(provider string) {
+	llmRetries.WithLabelValues(provider).Inc()
+}
+
+// ObserveClassification records an email classified by the rules of ats, empty if no ats was recognized,
+// and whether it was left to the fallback
+func ObserveClassification(ats string, fallback bool) {
+	if ats == "" {
+		ats = "none"
+	}
+	result := "rules"
+	if fallback {
+		result = "fallback"
+	}
+	classifierEmails.WithLabelValues(ats, result).Inc()
+}
+
+func observeGRPC(method string, start time.Time, err error) {
+	grpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	grpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// UnaryServerInterceptor records the count, duration and status code of the calls
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		observeGRPC(info.FullMethod, start, err)
+		return res, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls, which are recorded when they end
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeGRPC(info.FullMethod, start, err)
+		return err
+	}
+}
+
+// ApplicationsMaxAge is how long the number of applications is cached between scrapes, so frequent
+// scrapes don't query the store every time
+const ApplicationsMaxAge = 30 * time.Second
+
+// applicationsCollector reports the number of stored applications by status, of every tenant
+type applicationsCollector struct {
+	store  storage.Store
+	logger *slog.Logger
+	desc   *prometheus.Desc
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	counts    map[gcp.Status]int
+	refreshed time.Time
+}
+
+func newApplicationsCollector(store storage.Store, logger *slog.Logger) *applicationsCollector {
+	return &applicationsCollector{
+		store:  store,
+		logger: logger,
+		desc:   prometheus.NewDesc("maxhire_applications", "Number of stored applications, by status.", []string{"status"}, nil),
+		maxAge: ApplicationsMaxAge,
+		now:    time.Now,
+	}
+}
+
+// RegisterApplications reports the applications of the store in the maxhire_applications metric, counted
+// at most every ApplicationsMaxAge, and logs the errors counting them to logger
+func RegisterApplications(store storage.Store, logger *slog.Logger) error {
+	return Registry.Register(newApplicationsCollector(store, logger))
+}
+
+func (c *applicationsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// current returns the cached counts, the applications are counted again once they are older than maxAge
+func (c *applicationsCollector) current() (map[gcp.Status]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts != nil && c.now().Sub(c.refreshed) < c.maxAge {
+		return c.counts, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	counts, err := c.store.CountApplications(ctx)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "error counting applications for metrics", "error", err)
+		return nil, err
+	}
+	c.counts, c.refreshed = counts, c.now()
+	return counts, nil
+}
+
+func (c *applicationsCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.current()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	for s := gcp.Pending; s <= gcp.Ghosted; s++ {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[s]), s.String())
+	}
+}
+
+// Handler serves the metrics in the prometheus format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// WriteFile writes the metrics to path in the prometheus text format, e.g. for the textfile collector of
+// the node exporter
+func WriteFile(path string) error {
+	return prometheus.WriteToTextfile(path, Registry)
+}
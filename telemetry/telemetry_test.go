@@ -0,0 +1,117 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gcp "github.com/MaxBear/maxhire/deps/gcp/models"
+	"github.com/MaxBear/maxhire/models"
+	"github.com/MaxBear/maxhire/storage/memory"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(grpcRequests.WithLabelValues(info.FullMethod, "OK")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(grpcRequests.WithLabelValues(info.FullMethod, "NotFound")))
+	assert.Equal(t, 1, testutil.CollectAndCount(grpcDuration))
+}
+
+func TestApplicationsCollector(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, store.AddApplications(ctx, []*models.Application{
+		{ID: "1", Date: date, Company: "A", Status: gcp.Applied},
+		{ID: "2", Date: date, Company: "B", Status: gcp.Applied, Tenant: "jane"},
+		{ID: "3", Date: date, Company: "C", Status: gcp.Reject},
+	}))
+	require.NoError(t, RegisterApplications(store, slog.Default()))
+
+	expected := `
+# HELP maxhire_applications Number of stored applications, by status.
+# TYPE maxhire_applications gauge
+maxhire_applications{status="Applied"} 2
+maxhire_applications{status="Ghosted"} 0
+maxhire_applications{status="Interviewing"} 0
+maxhire_applications{status="Offer"} 0
+maxhire_applications{status="OfferAccepted"} 0
+maxhire_applications{status="OfferDeclined"} 0
+maxhire_applications{status="Pending"} 0
+maxhire_applications{status="Reject"} 1
+maxhire_applications{status="Success"} 0
+maxhire_applications{status="Withdrawn"} 0
+`
+	require.NoError(t, testutil.GatherAndCompare(Registry, strings.NewReader(expected), "maxhire_applications"))
+
+	ObserveLLMCall("openai", time.Now(), 100, 20, nil)
+	path := filepath.Join(t.TempDir(), "maxhire.prom")
+	require.NoError(t, WriteFile(path))
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `maxhire_llm_tokens_total{kind="prompt",provider="openai"} 100`)
+}
+
+func TestApplicationsCollector_Cache(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, store.AddApplications(ctx, []*models.Application{{ID: "1", Date: date, Company: "A", Status: gcp.Applied}}))
+
+	now := date
+	collector := newApplicationsCollector(store, slog.Default())
+	collector.now = func() time.Time { return now }
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+	applied := func() float64 {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, metric := range families[0].GetMetric() {
+			if metric.GetLabel()[0].GetValue() == "Applied" {
+				return metric.GetGauge().GetValue()
+			}
+		}
+		return -1
+	}
+	assert.Equal(t, 1.0, applied())
+
+	// the store is not counted again until the counts are too old
+	require.NoError(t, store.AddApplications(ctx, []*models.Application{{ID: "2", Date: date, Company: "B", Status: gcp.Applied}}))
+	assert.Equal(t, 1.0, applied())
+	now = now.Add(ApplicationsMaxAge)
+	assert.Equal(t, 2.0, applied())
+}
+
+func TestSetupTracing(t *testing.T) {
+	ctx := context.Background()
+	shutdown, err := SetupTracing(ctx, "test", "")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(ctx))
+
+	shutdown, err = SetupTracing(ctx, "test", "http://localhost:4318/v1/traces")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(ctx))
+}
@@ -0,0 +1,44 @@
+#!/bin/bash
+
+# List applications in REJECT or APPLIED status, ordered by date
+curl -s 'localhost:8080/v1/applications?statuses=REJECT,APPLIED&order_by=date&page_size=20'
+
+# Create or update applications
+curl -s -X POST localhost:8080/v1/applications -d \
+'{
+    "applications": [
+        {"date": "2026-01-15T10:00:00Z", "company": "Acme", "position": "Software Engineer"}
+    ]
+}'
+
+# Update the status of an application
+curl -s -X PATCH 'localhost:8080/v1/applications/<application id>?update_mask=status' -d \
+'{
+    "status": "INTERVIEWING"
+}'
+
+# Set the interviews of an application
+curl -s -X PUT 'localhost:8080/v1/applications/<application id>/interviews' -d \
+'{
+    "interviews": [
+        {"datetime": "2026-01-20T14:00:00Z", "interview_type": "RECRUITER_SCREEN", "duration_min": 30}
+    ]
+}'
+
+# Timeline of an application
+curl -s 'localhost:8080/v1/applications/<application id>/events'
+
+# Application statistics for January 2026
+curl -s 'localhost:8080/v1/stats?start_date=2026-01-01T00:00:00Z&end_date=2026-02-01T00:00:00Z'
+
+# Stream the changes to interviewing applications, one json object per line
+curl -s -N 'localhost:8080/v1/applications:watch?statuses=INTERVIEWING'
+
+# OpenAPI document
+curl -s localhost:8080/openapi.json
+
+# With authentication enabled, pass the api key or JWT of the tenant
+curl -s -H 'Authorization: Bearer <token>' localhost:8080/v1/applications
+
+# With TLS and client certificates enabled
+curl -s --cacert ca.pem --cert client.pem --key client.key https://localhost:8080/v1/applications
@@ -1,9 +1,14 @@
 #!/bin/bash
 
-grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.proto -plaintext localhost:9000 list
+# The server must run with -reflection, otherwise pass -import-path ./proto/applications/v1 -proto applications.proto
+
+# Health of the server, NOT_SERVING while the storage is unavailable
+grpcurl -plaintext -d '{"service": "maxbear.maxhire.Applications"}' localhost:9000 grpc.health.v1.Health/Check
+
+grpcurl -emit-defaults -plaintext localhost:9000 list
 
 # protobuf timestamps require RFC3339 (ISO 8601)
-grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.proto -plaintext -d \
+grpcurl -emit-defaults -plaintext -d \
 '{
     "Applications": [
         {"date": "2026-01-30T17:11:47Z", "company": "DoorDash"}
@@ -12,16 +17,16 @@ grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.
  localhost:9000 maxbear.maxhire.Applications/SetApplications
 
 # List all applications
-grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.proto -plaintext localhost:9000 maxbear.maxhire.Applications/ListApplications
+grpcurl -emit-defaults -plaintext localhost:9000 maxbear.maxhire.Applications/ListApplications
 
 # List applications filtered by company name
-grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.proto -plaintext -d \
+grpcurl -emit-defaults -plaintext -d \
 '{
     "company": "DoorDash"
 }' \
 localhost:9000 maxbear.maxhire.Applications/ListApplications
 
-grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.proto -plaintext -d \
+grpcurl -emit-defaults -plaintext -d \
 '{
     "company": "DoorDash",
     "status": "PENDING"
@@ -29,7 +34,7 @@ grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.
 localhost:9000 maxbear.maxhire.Applications/ListApplications
 
 
-grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.proto -plaintext -d \
+grpcurl -emit-defaults -plaintext -d \
 '{
     "start_date": "2026-01-28T00:00:00Z",
     "end_date": "2026-01-29T00:00:00Z"
@@ -37,7 +42,7 @@ grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.
 localhost:9000 maxbear.maxhire.Applications/ListApplications
 
 # Set interviews for GitLab application
-grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.proto -plaintext -d \
+grpcurl -emit-defaults -plaintext -d \
 '{
     "date": "2026-01-28T02:50:10Z",
     "company": "GitLab",
@@ -48,4 +53,71 @@ grpcurl -emit-defaults -import-path ./proto/applications/v1 -proto applications.
         }
     ]
 }' \
-localhost:9000 maxbear.maxhire.Applications/SetInterviews
\ No newline at end of file
+localhost:9000 maxbear.maxhire.Applications/SetInterviews
+
+# Get, update and delete an application by the id assigned by SetApplications
+grpcurl -emit-defaults -plaintext -d \
+'{
+    "id": "<application id>"
+}' \
+localhost:9000 maxbear.maxhire.Applications/GetApplication
+
+grpcurl -emit-defaults -plaintext -d \
+'{
+    "application": {"id": "<application id>", "status": "REJECT"},
+    "update_mask": "status"
+}' \
+localhost:9000 maxbear.maxhire.Applications/UpdateApplication
+
+grpcurl -emit-defaults -plaintext -d \
+'{
+    "id": "<application id>"
+}' \
+localhost:9000 maxbear.maxhire.Applications/DeleteApplication
+
+# List applications page by page, newest first, pass next_page_token as page_token to get the next page
+grpcurl -emit-defaults -plaintext -d \
+'{
+    "page_size": 20,
+    "order_by": "date desc"
+}' \
+localhost:9000 maxbear.maxhire.Applications/ListApplications
+
+# List pending and applied applications at companies starting with "stripe" for backend positions
+grpcurl -emit-defaults -plaintext -d \
+'{
+    "statuses": ["PENDING", "APPLIED"],
+    "company": "stripe",
+    "company_prefix": true,
+    "query": "backend"
+}' \
+localhost:9000 maxbear.maxhire.Applications/ListApplications
+
+# Application statistics for January 2026
+grpcurl -emit-defaults -plaintext -d \
+'{
+    "start_date": "2026-01-01T00:00:00Z",
+    "end_date": "2026-02-01T00:00:00Z"
+}' \
+localhost:9000 maxbear.maxhire.Applications/GetStats
+
+# Timeline of an application
+grpcurl -emit-defaults -plaintext -d \
+'{
+    "id": "<application id>"
+}' \
+localhost:9000 maxbear.maxhire.Applications/ListApplicationEvents
+
+# Stream the changes to interviewing applications, resuming after the revision returned by ListApplications
+grpcurl -emit-defaults -plaintext -d \
+'{
+    "statuses": ["INTERVIEWING"],
+    "from_revision": "<revision>"
+}' \
+localhost:9000 maxbear.maxhire.Applications/WatchApplications
+
+# With authentication enabled, pass the api key or JWT of the tenant
+grpcurl -emit-defaults -plaintext -H 'authorization: Bearer <token>' localhost:9000 maxbear.maxhire.Applications/ListApplications
+
+# With TLS and client certificates enabled
+grpcurl -emit-defaults -cacert ca.pem -cert client.pem -key client.key localhost:9000 maxbear.maxhire.Applications/ListApplications